@@ -1,40 +1,144 @@
 package app
 
 import (
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"my-api/app/models"
 	"my-api/app/pkg/logger"
+	"my-api/app/pkg/mail"
+	"my-api/app/pkg/oidc"
+	"my-api/app/pkg/revocation"
+	"my-api/app/pkg/throttle"
+	"my-api/app/policies"
 	"my-api/app/repositories"
 	"my-api/app/services"
+	"my-api/config"
 )
 
 // App - 應用程式容器（類似 Laravel 的 Service Container）
 type App struct {
 	DB     *gorm.DB
 	Logger *logger.Logger
+	Mailer mail.Mailer
+
+	// Token 撤銷清單（登出黑名單）
+	TokenRevocations revocation.Store
+
+	// 登入失敗計數
+	Throttle throttle.Store
+
+	// 授權閘道（資源層級的 Policy）
+	Gate *policies.Gate
 
 	// Repositories
-	UserRepository repositories.UserRepository
-	PostRepository repositories.PostRepository
+	UserRepository          repositories.UserRepository
+	PostRepository          repositories.PostRepository
+	RefreshTokenRepository  repositories.RefreshTokenRepository
+	RoleRepository          repositories.RoleRepository
+	PasswordResetRepository repositories.PasswordResetTokenRepository
+	AccessTokenRepository   repositories.PersonalAccessTokenRepository
+	SessionRepository       repositories.SessionRepository
+	LoginHistoryRepository  repositories.LoginHistoryRepository
+	IdentityRepository      repositories.IdentityRepository
 
 	// Services
-	UserService services.UserService
-	AuthService services.AuthService
+	UserService              services.UserService
+	AuthService              services.AuthService
+	RoleService              services.RoleService
+	PasswordService          services.PasswordService
+	EmailVerificationService services.EmailVerificationService
+	AccessTokenService       services.PersonalAccessTokenService
+	TwoFactorService         services.TwoFactorService
+	SessionService           services.SessionService
+	SocialAuthService        services.SocialAuthService
+	AccountService           services.AccountService
 }
 
 // NewApp - 建立新的應用程式容器
-func NewApp(db *gorm.DB, log *logger.Logger) *App {
+// redisClient 為 nil 時（未啟用 Redis），撤銷清單與登入失敗計數改用記憶體儲存
+func NewApp(db *gorm.DB, log *logger.Logger, redisClient *redis.Client, mailer mail.Mailer) *App {
 	app := &App{
 		DB:     db,
 		Logger: log,
+		Mailer: mailer,
+	}
+
+	// 初始化共用儲存
+	if redisClient != nil {
+		app.TokenRevocations = revocation.NewRedisStore(redisClient)
+		app.Throttle = throttle.NewRedisStore(redisClient)
+	} else {
+		app.TokenRevocations = revocation.NewMemoryStore()
+		app.Throttle = throttle.NewMemoryStore()
 	}
 
+	// 註冊 Policies
+	app.Gate = policies.NewGate()
+	policies.Register[*models.Post](app.Gate, &policies.PostPolicy{})
+
 	// 初始化 Repositories
 	app.UserRepository = repositories.NewUserRepository(db)
 	app.PostRepository = repositories.NewPostRepository(db)
+	app.RefreshTokenRepository = repositories.NewRefreshTokenRepository(db)
+	app.RoleRepository = repositories.NewRoleRepository(db)
+	app.PasswordResetRepository = repositories.NewPasswordResetTokenRepository(db)
+	app.AccessTokenRepository = repositories.NewPersonalAccessTokenRepository(db)
+	app.SessionRepository = repositories.NewSessionRepository(db)
+	app.LoginHistoryRepository = repositories.NewLoginHistoryRepository(db)
+	app.IdentityRepository = repositories.NewIdentityRepository(db)
 
 	// 初始化 Services（注入 Repository 依賴）
-	app.UserService = services.NewUserService(app.UserRepository)
-	app.AuthService = services.NewAuthService(app.UserRepository)
+	app.EmailVerificationService = services.NewEmailVerificationService(app.UserRepository, app.Mailer)
+	app.TwoFactorService = services.NewTwoFactorService(app.UserRepository, app.Throttle)
+	app.SessionService = services.NewSessionService(app.SessionRepository, app.LoginHistoryRepository, app.RefreshTokenRepository, app.TokenRevocations)
+	app.AuthService = services.NewAuthService(
+		app.UserRepository,
+		app.RefreshTokenRepository,
+		app.RoleRepository,
+		app.TokenRevocations,
+		app.EmailVerificationService,
+		services.NewLoginThrottle(app.Throttle, log),
+		app.TwoFactorService,
+		app.SessionService,
+	)
+	app.RoleService = services.NewRoleService(app.RoleRepository, app.UserRepository)
+	app.PasswordService = services.NewPasswordService(app.UserRepository, app.PasswordResetRepository, app.AccessTokenRepository, app.AuthService, app.Mailer)
+	app.AccountService = services.NewAccountService(
+		app.UserRepository,
+		app.PostRepository,
+		app.RoleRepository,
+		app.SessionRepository,
+		app.LoginHistoryRepository,
+		app.IdentityRepository,
+		app.AccessTokenRepository,
+		app.AuthService,
+		app.Mailer,
+	)
+	app.UserService = services.NewUserService(app.UserRepository, app.EmailVerificationService, app.PasswordService, app.AccountService)
+	app.AccessTokenService = services.NewPersonalAccessTokenService(app.AccessTokenRepository, app.UserRepository, app.RoleService)
+	app.SocialAuthService = services.NewSocialAuthService(
+		oidcProviders(config.GlobalConfig.OIDC),
+		app.IdentityRepository,
+		app.UserRepository,
+		app.RoleRepository,
+		app.AuthService,
+		app.EmailVerificationService,
+	)
 
 	return app
 }
+
+// oidcProviders - 依設定建立 OpenID Connect IdP 用戶端（discovery 文件在第一次登入時才下載）
+func oidcProviders(cfg config.OIDCConfig) map[string]*oidc.Provider {
+	providers := make(map[string]*oidc.Provider, len(cfg.Providers))
+	for name, provider := range cfg.Providers {
+		providers[name] = oidc.NewProvider(oidc.Config{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		})
+	}
+	return providers
+}
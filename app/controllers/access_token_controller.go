@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/pkg/logger"
+	"my-api/app/requests"
+	"my-api/app/services"
+	"my-api/app/traits"
+)
+
+// AccessTokenController - 個人存取 Token（API Key）管理控制器
+// 只能管理自己的 Token，且必須以 JWT 登入（路由上掛 middleware.RequireJWT）
+type AccessTokenController struct {
+	app *app.App
+}
+
+// NewAccessTokenController - 建立新的個人存取 Token 控制器
+func NewAccessTokenController(app *app.App) *AccessTokenController {
+	return &AccessTokenController{app: app}
+}
+
+// Index - 取得自己的所有 Token（不含 Token 本身）
+// GET /api/tokens
+func (ctrl *AccessTokenController) Index(c *gin.Context) {
+	tokens, err := ctrl.app.AccessTokenService.List(c.GetUint("user_id"))
+	if err != nil {
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	traits.RespondSuccess(c, tokens, "成功取得 Token 列表")
+}
+
+// Store - 建立 Token，回應中的 plain_text_token 只會出現這一次
+// POST /api/tokens
+func (ctrl *AccessTokenController) Store(c *gin.Context) {
+	var req requests.CreatePersonalAccessTokenRequest
+
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	result, err := ctrl.app.AccessTokenService.Create(c.GetUint("user_id"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTokenScope) {
+			traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	logger.FromGinContext(c).Info("個人存取 Token 建立成功", map[string]interface{}{
+		"channel":  "security",
+		"user_id":  c.GetUint("user_id"),
+		"token_id": result.AccessToken.ID,
+		"scopes":   result.AccessToken.Scopes,
+	})
+
+	traits.RespondCreated(c, result, "Token 建立成功，請妥善保存，之後無法再次查看")
+}
+
+// Destroy - 撤銷 Token
+// DELETE /api/tokens/:id
+func (ctrl *AccessTokenController) Destroy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的 Token ID", err.Error())
+		return
+	}
+
+	if err := ctrl.app.AccessTokenService.Revoke(c.GetUint("user_id"), uint(id)); err != nil {
+		if errors.Is(err, services.ErrAccessTokenNotFound) {
+			traits.RespondNotFound(c, err.Error())
+			return
+		}
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	logger.FromGinContext(c).Info("個人存取 Token 已撤銷", map[string]interface{}{
+		"channel":  "security",
+		"user_id":  c.GetUint("user_id"),
+		"token_id": id,
+	})
+
+	traits.RespondSuccess(c, nil, "Token 已撤銷")
+}
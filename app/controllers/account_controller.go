@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/pkg/logger"
+	"my-api/app/requests"
+	"my-api/app/responses"
+	"my-api/app/services"
+	"my-api/app/traits"
+)
+
+// AccountController - 個人資料匯出與刪除帳號控制器
+// 只能操作自己的帳號，且必須以 JWT 登入（路由上掛 middleware.RequireJWT）
+type AccountController struct {
+	app *app.App
+}
+
+// NewAccountController - 建立新的帳號控制器
+func NewAccountController(app *app.App) *AccountController {
+	return &AccountController{app: app}
+}
+
+// Export - 匯出自己的個人資料
+// GET /api/me/export?format=json|zip
+func (ctrl *AccountController) Export(c *gin.Context) {
+	var req requests.ExportAccountRequest
+
+	// 驗證請求
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	logger.FromGinContext(c).Info("匯出個人資料", map[string]interface{}{
+		"channel": "security",
+		"user_id": userID,
+		"format":  req.Format,
+	})
+
+	if req.Format != "zip" {
+		export, err := ctrl.app.AccountService.Export(userID)
+		if err != nil {
+			traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+			return
+		}
+		traits.RespondSuccess(c, export, "成功匯出個人資料")
+		return
+	}
+
+	// 先寫入緩衝區，產生失敗時還能回應錯誤
+	var archive bytes.Buffer
+	if err := ctrl.app.AccountService.ExportArchive(userID, &archive); err != nil {
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	filename := fmt.Sprintf("account-export-%d-%s.zip", userID, time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
+
+// Destroy - 申請刪除自己的帳號，寬限期內可以取消
+// 輸入密碼時直接申請；沒有輸入密碼時寄出確認信，以 POST 送出信中的連結（ConfirmDeletion）後才申請
+// DELETE /api/me
+func (ctrl *AccountController) Destroy(c *gin.Context) {
+	var req requests.DeleteAccountRequest
+
+	// 驗證請求
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	result, err := ctrl.app.AccountService.RequestDeletion(userID, req.Password)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	if result.ConfirmationSent {
+		logger.FromGinContext(c).Info("寄出確認刪除帳號信件", map[string]interface{}{
+			"channel": "security",
+			"user_id": userID,
+		})
+		traits.RespondSuccess(c, result, "已寄出確認信，請開啟信中的連結並確認送出")
+		return
+	}
+
+	respondDeletionRequested(c, userID, result)
+}
+
+// ShowDeletionConfirmation - 開啟確認信中的簽章連結，回傳確認所需的參數，不會申請刪除
+// 信件掃描器、連結預覽會自動開啟 GET 連結，實際申請必須由使用者確認後以 POST 送出
+// GET /api/me/deletion/confirm/:id/:hash?expires=...&signature=...
+func (ctrl *AccountController) ShowDeletionConfirmation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的使用者 ID", err.Error())
+		return
+	}
+
+	traits.RespondSuccess(c, &responses.AccountDeletionConfirmationResponse{
+		UserID:     uint(id),
+		Hash:       c.Param("hash"),
+		Expires:    c.Query("expires"),
+		Signature:  c.Query("signature"),
+		ConfirmURL: c.Request.URL.RequestURI(),
+	}, "請以 POST 送出 confirm_url 確認刪除帳號")
+}
+
+// ConfirmDeletion - 以 POST 送出確認信中的簽章連結，申請刪除帳號
+// POST /api/me/deletion/confirm/:id/:hash?expires=...&signature=...
+func (ctrl *AccountController) ConfirmDeletion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的使用者 ID", err.Error())
+		return
+	}
+
+	result, err := ctrl.app.AccountService.ConfirmDeletion(uint(id), c.Param("hash"))
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	respondDeletionRequested(c, uint(id), result)
+}
+
+// CancelDeletion - 取消刪除帳號
+// DELETE /api/me/deletion
+func (ctrl *AccountController) CancelDeletion(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	if err := ctrl.app.AccountService.CancelDeletion(userID); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	logger.FromGinContext(c).Info("取消刪除帳號", map[string]interface{}{
+		"channel": "security",
+		"user_id": userID,
+	})
+
+	traits.RespondSuccess(c, nil, "已取消刪除帳號")
+}
+
+// respondDeletionRequested - 已申請（或寬限期為 0 時已完成）刪除帳號
+func respondDeletionRequested(c *gin.Context, userID uint, result *responses.AccountDeletionResponse) {
+	logger.FromGinContext(c).Warning("申請刪除帳號", map[string]interface{}{
+		"channel":      "security",
+		"user_id":      userID,
+		"scheduled_at": result.ScheduledAt,
+		"deleted":      result.Deleted,
+	})
+
+	if result.Deleted {
+		traits.RespondSuccess(c, result, "帳號已刪除")
+		return
+	}
+	traits.RespondSuccess(c, result, "已申請刪除帳號，期限前都可以取消")
+}
+
+// respondAccountError - 刪除帳號錯誤對應的 HTTP 狀態碼
+func respondAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrIncorrectPassword),
+		errors.Is(err, services.ErrInvalidDeletionLink):
+		traits.RespondError(c, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, services.ErrAccountDeletionPending),
+		errors.Is(err, services.ErrNoAccountDeletion),
+		errors.Is(err, services.ErrLastAdmin):
+		traits.RespondError(c, http.StatusConflict, err.Error(), nil)
+	default:
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
@@ -1,12 +1,16 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"my-api/app"
+	"my-api/app/pkg/password"
 	"my-api/app/requests"
+	"my-api/app/services"
 	"my-api/app/traits"
+	"my-api/app/utils"
 )
 
 // AuthController - 認證控制器
@@ -32,8 +36,13 @@ func (ctrl *AuthController) Register(c *gin.Context) {
 	}
 
 	// 呼叫 Service 註冊使用者
-	response, err := ctrl.app.AuthService.Register(&req)
+	response, err := ctrl.app.AuthService.Register(&req, clientInfo(c))
 	if err != nil {
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			traits.RespondValidationError(c, requests.FormatValidationError(err))
+			return
+		}
 		traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
@@ -54,8 +63,43 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 	}
 
 	// 呼叫 Service 登入
-	response, err := ctrl.app.AuthService.Login(&req)
+	response, err := ctrl.app.AuthService.Login(&req, clientInfo(c))
 	if err != nil {
+		var lockout *services.LockoutError
+		if errors.As(err, &lockout) {
+			traits.RespondTooManyRequests(c, lockout.RetryAfterSeconds(), lockout.Error())
+			return
+		}
+		traits.RespondUnauthorized(c, err.Error())
+		return
+	}
+
+	if response.TwoFactorRequired {
+		traits.RespondSuccess(c, response, "請輸入兩步驟驗證碼")
+		return
+	}
+	traits.RespondSuccess(c, response, "登入成功")
+}
+
+// TwoFactorChallenge - 以兩步驟驗證碼（或復原碼）完成登入
+// POST /api/two-factor/challenge
+func (ctrl *AuthController) TwoFactorChallenge(c *gin.Context) {
+	var req requests.TwoFactorChallengeRequest
+
+	// 驗證請求
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	response, err := ctrl.app.AuthService.TwoFactorLogin(&req, clientInfo(c))
+	if err != nil {
+		var lockout *services.LockoutError
+		if errors.As(err, &lockout) {
+			traits.RespondTooManyRequests(c, lockout.RetryAfterSeconds(), lockout.Error())
+			return
+		}
 		traits.RespondUnauthorized(c, err.Error())
 		return
 	}
@@ -63,14 +107,88 @@ func (ctrl *AuthController) Login(c *gin.Context) {
 	traits.RespondSuccess(c, response, "登入成功")
 }
 
+// Refresh - 使用 Refresh Token 換發新的 Token
+// POST /api/refresh
+func (ctrl *AuthController) Refresh(c *gin.Context) {
+	var req requests.RefreshTokenRequest
+
+	// 驗證請求
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	// 呼叫 Service 輪替 Token
+	response, err := ctrl.app.AuthService.Refresh(&req)
+	if err != nil {
+		traits.RespondUnauthorized(c, err.Error())
+		return
+	}
+
+	traits.RespondSuccess(c, response, "Token 刷新成功")
+}
+
 // Logout - 使用者登出
 // POST /api/logout
+//
+// 目前的 Access Token 會加入撤銷清單直到過期；
+// body 帶 refresh_token 時，該次登入的 Refresh Token 也會一併撤銷
 func (ctrl *AuthController) Logout(c *gin.Context) {
-	// JWT 是無狀態的，登出只需客戶端刪除 Token
-	// 如果需要 Token 黑名單，可以將 Token 存入 Redis
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		traits.RespondUnauthorized(c, "未授權")
+		return
+	}
+
+	var req requests.LogoutRequest
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	if err := ctrl.app.AuthService.Logout(claims.(*utils.JWTClaims), &req); err != nil {
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
 	traits.RespondSuccess(c, nil, "登出成功")
 }
 
+// LogoutAll - 登出所有裝置，revoke_api_tokens 為 true 時一併撤銷所有 API Token
+// POST /api/logout/all
+func (ctrl *AuthController) LogoutAll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		traits.RespondUnauthorized(c, "未授權")
+		return
+	}
+
+	var req requests.LogoutAllRequest
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	if err := ctrl.app.AuthService.LogoutAll(userID.(uint)); err != nil {
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	if req.RevokeAPITokens {
+		if err := ctrl.app.AccessTokenService.RevokeAll(userID.(uint)); err != nil {
+			traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+			return
+		}
+		traits.RespondSuccess(c, nil, "已登出所有裝置並撤銷所有 API Token")
+		return
+	}
+
+	traits.RespondSuccess(c, nil, "已登出所有裝置")
+}
+
 // Me - 取得當前用戶資訊
 // GET /api/me
 func (ctrl *AuthController) Me(c *gin.Context) {
@@ -90,3 +208,11 @@ func (ctrl *AuthController) Me(c *gin.Context) {
 
 	traits.RespondSuccess(c, response, "取得用戶資訊成功")
 }
+
+// clientInfo - 取得用戶端 IP 與 User-Agent
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
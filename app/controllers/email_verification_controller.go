@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/services"
+	"my-api/app/traits"
+)
+
+// EmailVerificationController - Email 驗證控制器
+type EmailVerificationController struct {
+	app *app.App
+}
+
+// NewEmailVerificationController - 建立新的 Email 驗證控制器
+func NewEmailVerificationController(app *app.App) *EmailVerificationController {
+	return &EmailVerificationController{app: app}
+}
+
+// Verify - 完成 Email 驗證（信件中的連結）
+// GET /api/email/verify/:id/:hash?expires=...&signature=...
+//
+// 簽章與過期時間由 middleware.ValidateSignature 檢查，不需要登入
+func (ctrl *EmailVerificationController) Verify(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的使用者 ID", err.Error())
+		return
+	}
+
+	if err := ctrl.app.EmailVerificationService.Verify(uint(id), c.Param("hash")); err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationLink) {
+			traits.RespondForbidden(c, err.Error())
+			return
+		}
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	traits.RespondSuccess(c, nil, "Email 驗證成功")
+}
+
+// Resend - 重新寄送驗證信
+// POST /api/email/resend
+func (ctrl *EmailVerificationController) Resend(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		traits.RespondUnauthorized(c, "未授權")
+		return
+	}
+
+	if err := ctrl.app.EmailVerificationService.Resend(userID.(uint)); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyVerified) {
+			traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	traits.RespondSuccess(c, nil, "驗證信已寄出")
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/traits"
+	"my-api/app/utils"
+)
+
+// JWKSController - 發佈 JWT 驗證公鑰
+type JWKSController struct {
+	app *app.App
+}
+
+// NewJWKSController - 建立新的 JWKS 控制器
+func NewJWKSController(app *app.App) *JWKSController {
+	return &JWKSController{app: app}
+}
+
+// Show - 目前所有可用來驗證 Token 的公鑰
+// GET /.well-known/jwks.json
+//
+// 回應格式依 RFC 7517，不包在統一的 success/data 結構裡，其他服務的 JWT 函式庫可直接使用
+func (ctrl *JWKSController) Show(c *gin.Context) {
+	keys, err := utils.CurrentKeySet()
+	if err != nil {
+		traits.RespondError(c, http.StatusInternalServerError, "取得金鑰失敗", nil)
+		return
+	}
+
+	// 允許快取一段時間；金鑰輪替時新公鑰要比新私鑰早上線
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keys.JWKS())
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/pkg/logger"
+	"my-api/app/pkg/password"
+	"my-api/app/requests"
+	"my-api/app/services"
+	"my-api/app/traits"
+)
+
+// PasswordController - 忘記密碼 / 重設密碼 / 變更密碼控制器
+type PasswordController struct {
+	app *app.App
+}
+
+// NewPasswordController - 建立新的密碼控制器
+func NewPasswordController(app *app.App) *PasswordController {
+	return &PasswordController{app: app}
+}
+
+// Forgot - 寄送重設密碼連結
+// POST /api/password/forgot
+//
+// 不論 Email 是否存在都回應相同訊息，避免被用來探測帳號
+func (ctrl *PasswordController) Forgot(c *gin.Context) {
+	var req requests.ForgotPasswordRequest
+
+	// 驗證請求
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	if err := ctrl.app.PasswordService.ForgotPassword(&req); err != nil {
+		logger.FromGinContext(c).Error("寄送重設密碼信件失敗", map[string]interface{}{
+			"error": err.Error(),
+		})
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	traits.RespondSuccess(c, nil, "如果此 Email 已註冊，我們已寄出重設密碼連結")
+}
+
+// Reset - 使用信件中的 Token 重設密碼
+// POST /api/password/reset
+func (ctrl *PasswordController) Reset(c *gin.Context) {
+	var req requests.ResetPasswordRequest
+
+	// 驗證請求
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	if err := ctrl.app.PasswordService.ResetPassword(&req); err != nil {
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			traits.RespondValidationError(c, requests.FormatValidationError(err))
+			return
+		}
+		if errors.Is(err, services.ErrInvalidPasswordResetToken) {
+			traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	traits.RespondSuccess(c, nil, "密碼已重設，請使用新密碼重新登入")
+}
+
+// Update - 變更自己的密碼（需輸入目前的密碼），成功後登出其他所有裝置
+// PUT /api/me/password
+func (ctrl *PasswordController) Update(c *gin.Context) {
+	var req requests.ChangePasswordRequest
+
+	// 驗證請求
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if err := ctrl.app.PasswordService.ChangePassword(userID, currentSessionID(c), &req); err != nil {
+		var policyErr *password.PolicyError
+		switch {
+		case errors.As(err, &policyErr):
+			traits.RespondValidationError(c, requests.FormatValidationError(err))
+		case errors.Is(err, services.ErrIncorrectPassword):
+			traits.RespondError(c, http.StatusForbidden, err.Error(), nil)
+		default:
+			traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		}
+		return
+	}
+
+	logger.FromGinContext(c).Info("密碼已變更", map[string]interface{}{
+		"channel": "security",
+		"user_id": userID,
+	})
+
+	traits.RespondSuccess(c, nil, "密碼已變更，其他裝置已登出")
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"my-api/app"
 	"my-api/app/models"
+	"my-api/app/policies"
 	"my-api/app/requests"
 	"my-api/app/traits"
 )
@@ -43,10 +44,15 @@ func (ctrl *PostController) Show(c *gin.Context) {
 		return
 	}
 
+	if ctrl.app.Gate.Denies(policies.ActorFromContext(c), "view", post) {
+		traits.RespondForbidden(c, "沒有權限檢視此文章")
+		return
+	}
+
 	traits.RespondSuccess(c, post, "成功取得文章")
 }
 
-// Store - 建立新文章
+// Store - 建立新文章（作者為目前登入的使用者）
 func (ctrl *PostController) Store(c *gin.Context) {
 	var req requests.CreatePostRequest
 
@@ -55,11 +61,22 @@ func (ctrl *PostController) Store(c *gin.Context) {
 		return
 	}
 
+	actor := policies.ActorFromContext(c)
+	if actor == nil {
+		traits.RespondUnauthorized(c, "未授權")
+		return
+	}
+
 	post := &models.Post{
 		Title:       req.Title,
 		Content:     req.Content,
 		Description: req.Description,
-		UserID:      req.UserID,
+		UserID:      actor.ID,
+	}
+
+	if ctrl.app.Gate.Denies(actor, "create", post) {
+		traits.RespondForbidden(c, "沒有權限建立文章")
+		return
 	}
 
 	if err := ctrl.app.PostRepository.Create(post); err != nil {
@@ -94,6 +111,11 @@ func (ctrl *PostController) Update(c *gin.Context) {
 		return
 	}
 
+	if ctrl.app.Gate.Denies(policies.ActorFromContext(c), "update", post) {
+		traits.RespondForbidden(c, "沒有權限修改此文章")
+		return
+	}
+
 	if req.Title != "" {
 		post.Title = req.Title
 	}
@@ -120,7 +142,18 @@ func (ctrl *PostController) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := ctrl.app.PostRepository.Delete(uint(id)); err != nil {
+	post, err := ctrl.app.PostRepository.FindByID(uint(id))
+	if err != nil {
+		traits.RespondError(c, http.StatusNotFound, "文章不存在", err.Error())
+		return
+	}
+
+	if ctrl.app.Gate.Denies(policies.ActorFromContext(c), "delete", post) {
+		traits.RespondForbidden(c, "沒有權限刪除此文章")
+		return
+	}
+
+	if err := ctrl.app.PostRepository.Delete(post.ID); err != nil {
 		traits.RespondError(c, http.StatusInternalServerError, "刪除文章失敗", err.Error())
 		return
 	}
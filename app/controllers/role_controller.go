@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/pkg/logger"
+	"my-api/app/requests"
+	"my-api/app/traits"
+)
+
+// RoleController - 角色權限管理控制器（需 roles.manage 權限）
+type RoleController struct {
+	app *app.App
+}
+
+// NewRoleController - 建立新的角色控制器
+func NewRoleController(app *app.App) *RoleController {
+	return &RoleController{app: app}
+}
+
+// Index - 取得所有角色
+// GET /api/roles
+func (ctrl *RoleController) Index(c *gin.Context) {
+	roles, err := ctrl.app.RoleService.GetAllRoles()
+	if err != nil {
+		traits.RespondError(c, http.StatusInternalServerError, "取得角色列表失敗", err.Error())
+		return
+	}
+
+	traits.RespondSuccess(c, roles, "成功取得角色列表")
+}
+
+// Show - 取得單一角色
+// GET /api/roles/:id
+func (ctrl *RoleController) Show(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的角色 ID", err.Error())
+		return
+	}
+
+	role, err := ctrl.app.RoleService.GetRoleByID(uint(id))
+	if err != nil {
+		traits.RespondNotFound(c, err.Error())
+		return
+	}
+
+	traits.RespondSuccess(c, role, "成功取得角色")
+}
+
+// Store - 新增角色
+// POST /api/roles
+func (ctrl *RoleController) Store(c *gin.Context) {
+	var req requests.CreateRoleRequest
+
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	role, err := ctrl.app.RoleService.CreateRole(&req)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	logger.FromGinContext(c).Info("角色建立成功", map[string]interface{}{
+		"role":        role.Name,
+		"permissions": role.Permissions,
+		"operator_id": c.GetUint("user_id"),
+	})
+
+	traits.RespondCreated(c, role, "角色建立成功")
+}
+
+// Update - 更新角色
+// PUT /api/roles/:id
+func (ctrl *RoleController) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的角色 ID", err.Error())
+		return
+	}
+
+	var req requests.UpdateRoleRequest
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	role, err := ctrl.app.RoleService.UpdateRole(uint(id), &req)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	traits.RespondSuccess(c, role, "角色更新成功")
+}
+
+// Destroy - 刪除角色
+// DELETE /api/roles/:id
+func (ctrl *RoleController) Destroy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的角色 ID", err.Error())
+		return
+	}
+
+	if err := ctrl.app.RoleService.DeleteRole(uint(id)); err != nil {
+		traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	logger.FromGinContext(c).Info("角色刪除成功", map[string]interface{}{
+		"role_id":     id,
+		"operator_id": c.GetUint("user_id"),
+	})
+
+	traits.RespondSuccess(c, nil, "角色刪除成功")
+}
+
+// SyncPermissions - 設定角色權限（整批取代）
+// PUT /api/roles/:id/permissions
+func (ctrl *RoleController) SyncPermissions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的角色 ID", err.Error())
+		return
+	}
+
+	var req requests.SyncPermissionsRequest
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	role, err := ctrl.app.RoleService.SyncRolePermissions(uint(id), &req)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	logger.FromGinContext(c).Info("角色權限更新成功", map[string]interface{}{
+		"role":        role.Name,
+		"permissions": role.Permissions,
+		"operator_id": c.GetUint("user_id"),
+	})
+
+	traits.RespondSuccess(c, role, "角色權限更新成功")
+}
+
+// Permissions - 取得所有權限
+// GET /api/permissions
+func (ctrl *RoleController) Permissions(c *gin.Context) {
+	permissions, err := ctrl.app.RoleService.GetAllPermissions()
+	if err != nil {
+		traits.RespondError(c, http.StatusInternalServerError, "取得權限列表失敗", err.Error())
+		return
+	}
+
+	traits.RespondSuccess(c, permissions, "成功取得權限列表")
+}
+
+// SyncUserRoles - 設定使用者角色（整批取代）
+// PUT /api/users/:id/roles
+func (ctrl *RoleController) SyncUserRoles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的使用者 ID", err.Error())
+		return
+	}
+
+	var req requests.SyncRolesRequest
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	roles, err := ctrl.app.RoleService.SyncUserRoles(uint(id), &req)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	logger.FromGinContext(c).Info("使用者角色更新成功", map[string]interface{}{
+		"user_id":     id,
+		"roles":       roles,
+		"operator_id": c.GetUint("user_id"),
+	})
+
+	traits.RespondSuccess(c, gin.H{"user_id": id, "roles": roles}, "使用者角色更新成功")
+}
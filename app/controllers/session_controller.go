@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/pkg/logger"
+	"my-api/app/services"
+	"my-api/app/traits"
+	"my-api/app/utils"
+)
+
+// SessionController - 登入裝置（工作階段）管理控制器
+// 只能管理自己的裝置，且必須以 JWT 登入（路由上掛 middleware.RequireJWT）
+type SessionController struct {
+	app *app.App
+}
+
+// NewSessionController - 建立新的登入裝置控制器
+func NewSessionController(app *app.App) *SessionController {
+	return &SessionController{app: app}
+}
+
+// Index - 取得目前登入中的裝置
+// GET /api/sessions
+func (ctrl *SessionController) Index(c *gin.Context) {
+	sessions, err := ctrl.app.SessionService.List(c.GetUint("user_id"), currentSessionID(c))
+	if err != nil {
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	traits.RespondSuccess(c, sessions, "成功取得登入裝置列表")
+}
+
+// Destroy - 登出某個裝置（該裝置的 Access Token 與 Refresh Token 立即失效）
+// DELETE /api/sessions/:id
+func (ctrl *SessionController) Destroy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的裝置 ID", err.Error())
+		return
+	}
+
+	if err := ctrl.app.SessionService.Revoke(c.GetUint("user_id"), uint(id)); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			traits.RespondNotFound(c, err.Error())
+			return
+		}
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	logger.FromGinContext(c).Info("登入裝置已登出", map[string]interface{}{
+		"channel":    "security",
+		"user_id":    c.GetUint("user_id"),
+		"session_id": id,
+	})
+
+	traits.RespondSuccess(c, nil, "裝置已登出")
+}
+
+// History - 取得最近的登入紀錄（含密碼錯誤的嘗試）
+// GET /api/sessions/history
+func (ctrl *SessionController) History(c *gin.Context) {
+	histories, err := ctrl.app.SessionService.History(c.GetUint("user_id"))
+	if err != nil {
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	traits.RespondSuccess(c, histories, "成功取得登入紀錄")
+}
+
+// currentSessionID - 目前請求的 Access Token 所屬的工作階段
+func currentSessionID(c *gin.Context) string {
+	claims, exists := c.Get("jwt_claims")
+	if !exists {
+		return ""
+	}
+	return claims.(*utils.JWTClaims).SessionID
+}
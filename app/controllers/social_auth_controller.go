@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/pkg/logger"
+	"my-api/app/requests"
+	"my-api/app/services"
+	"my-api/app/traits"
+	"my-api/config"
+)
+
+const (
+	// oidcStateCookie - 存放加密登入狀態（state、nonce、PKCE code_verifier）的 Cookie
+	oidcStateCookie = "oidc_state"
+	// oidcCookiePath - Cookie 只送往 OpenID Connect 的路由
+	oidcCookiePath = "/api/auth/oidc"
+)
+
+// SocialAuthController - OpenID Connect 登入控制器
+type SocialAuthController struct {
+	app *app.App
+}
+
+// NewSocialAuthController - 建立新的 OpenID Connect 登入控制器
+func NewSocialAuthController(app *app.App) *SocialAuthController {
+	return &SocialAuthController{app: app}
+}
+
+// Providers - 可使用的登入方式（前端依此顯示「使用 X 登入」按鈕）
+// GET /api/auth/oidc
+func (ctrl *SocialAuthController) Providers(c *gin.Context) {
+	traits.RespondSuccess(c, ctrl.app.SocialAuthService.Providers(), "成功取得登入方式")
+}
+
+// Redirect - 把瀏覽器導向 IdP 登入
+// GET /api/auth/oidc/:provider/redirect
+func (ctrl *SocialAuthController) Redirect(c *gin.Context) {
+	authURL, state, err := ctrl.app.SocialAuthService.Redirect(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownOIDCProvider) {
+			traits.RespondNotFound(c, err.Error())
+			return
+		}
+		logger.FromGinContext(c).Error("無法連線到 OpenID Connect IdP", map[string]interface{}{
+			"provider": c.Param("provider"),
+			"error":    err.Error(),
+		})
+		traits.RespondError(c, http.StatusBadGateway, "無法連線到登入服務", nil)
+		return
+	}
+
+	setOIDCStateCookie(c, state, config.GlobalConfig.OIDC.StateTTLMinutes*60)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback - IdP 導回，完成登入並回傳 Token
+// GET /api/auth/oidc/:provider/callback?code=...&state=...
+func (ctrl *SocialAuthController) Callback(c *gin.Context) {
+	var req requests.SocialCallbackRequest
+
+	// 驗證請求
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	// 登入狀態只能使用一次
+	state, _ := c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "", -1)
+
+	provider := c.Param("provider")
+	response, err := ctrl.app.SocialAuthService.Callback(c.Request.Context(), provider, &req, state, clientInfo(c))
+	if err != nil {
+		respondSocialAuthError(c, provider, err)
+		return
+	}
+
+	logger.FromGinContext(c).Info("OpenID Connect 登入", map[string]interface{}{
+		"channel":  "security",
+		"provider": provider,
+	})
+
+	if response.TwoFactorRequired {
+		traits.RespondSuccess(c, response, "請輸入兩步驟驗證碼")
+		return
+	}
+	traits.RespondSuccess(c, response, "登入成功")
+}
+
+// setOIDCStateCookie - 設定（maxAge < 0 時刪除）登入狀態 Cookie
+// SameSite=Lax：IdP 導回是跨站的 GET 導覽，Strict 會讓 Cookie 送不回來
+func setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	secure := strings.HasPrefix(config.GlobalConfig.App.URL, "https://")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, oidcCookiePath, "", secure, true)
+}
+
+// respondSocialAuthError - OpenID Connect 登入錯誤對應的 HTTP 狀態碼
+func respondSocialAuthError(c *gin.Context, provider string, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnknownOIDCProvider):
+		traits.RespondNotFound(c, err.Error())
+	case errors.Is(err, services.ErrInvalidOIDCState),
+		errors.Is(err, services.ErrOIDCLoginCancelled),
+		errors.Is(err, services.ErrOIDCEmailRequired):
+		traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, services.ErrOIDCEmailTaken):
+		traits.RespondError(c, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, services.ErrOIDCSignupDisabled):
+		traits.RespondForbidden(c, err.Error())
+	case errors.Is(err, services.ErrOIDCLoginFailed):
+		// 詳細原因（IdP 回應、ID Token 驗證失敗）只寫入日誌
+		logger.FromGinContext(c).Warning("OpenID Connect 登入失敗", map[string]interface{}{
+			"channel":  "security",
+			"provider": provider,
+			"error":    err.Error(),
+		})
+		traits.RespondUnauthorized(c, services.ErrOIDCLoginFailed.Error())
+	default:
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/pkg/logger"
+	"my-api/app/requests"
+	"my-api/app/services"
+	"my-api/app/traits"
+)
+
+// TwoFactorController - 兩步驟驗證（TOTP）設定控制器
+// 只能設定自己的帳號，且必須以 JWT 登入（路由上掛 middleware.RequireJWT）
+type TwoFactorController struct {
+	app *app.App
+}
+
+// NewTwoFactorController - 建立新的兩步驟驗證控制器
+func NewTwoFactorController(app *app.App) *TwoFactorController {
+	return &TwoFactorController{app: app}
+}
+
+// Enable - 產生 TOTP 金鑰，需再呼叫 Confirm 才會啟用
+// POST /api/two-factor
+func (ctrl *TwoFactorController) Enable(c *gin.Context) {
+	result, err := ctrl.app.TwoFactorService.Enable(c.GetUint("user_id"))
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	traits.RespondSuccess(c, result, "請使用驗證器 App 掃描 QR Code，並輸入驗證碼完成設定")
+}
+
+// Confirm - 輸入驗證碼確認啟用，回傳復原碼（只會出現這一次）
+// POST /api/two-factor/confirm
+func (ctrl *TwoFactorController) Confirm(c *gin.Context) {
+	var req requests.TwoFactorCodeRequest
+
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	result, err := ctrl.app.TwoFactorService.Confirm(c.GetUint("user_id"), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	logger.FromGinContext(c).Info("兩步驟驗證已啟用", map[string]interface{}{
+		"channel": "security",
+		"user_id": c.GetUint("user_id"),
+	})
+
+	traits.RespondSuccess(c, result, "兩步驟驗證已啟用，請妥善保存復原碼")
+}
+
+// Disable - 停用兩步驟驗證（需輸入密碼）
+// DELETE /api/two-factor
+func (ctrl *TwoFactorController) Disable(c *gin.Context) {
+	var req requests.TwoFactorPasswordRequest
+
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	if err := ctrl.app.TwoFactorService.Disable(c.GetUint("user_id"), req.Password); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	logger.FromGinContext(c).Warning("兩步驟驗證已停用", map[string]interface{}{
+		"channel": "security",
+		"user_id": c.GetUint("user_id"),
+	})
+
+	traits.RespondSuccess(c, nil, "兩步驟驗證已停用")
+}
+
+// RecoveryCodes - 重新產生復原碼，舊的復原碼全部失效（需輸入密碼）
+// POST /api/two-factor/recovery-codes
+func (ctrl *TwoFactorController) RecoveryCodes(c *gin.Context) {
+	var req requests.TwoFactorPasswordRequest
+
+	if err := req.Validate(c); err != nil {
+		validationErrors := requests.FormatValidationError(err)
+		traits.RespondValidationError(c, validationErrors)
+		return
+	}
+
+	result, err := ctrl.app.TwoFactorService.RegenerateRecoveryCodes(c.GetUint("user_id"), req.Password)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	logger.FromGinContext(c).Info("復原碼已重新產生", map[string]interface{}{
+		"channel": "security",
+		"user_id": c.GetUint("user_id"),
+	})
+
+	traits.RespondSuccess(c, result, "復原碼已重新產生，舊的復原碼已失效")
+}
+
+// respondTwoFactorError - 兩步驟驗證設定錯誤對應的 HTTP 狀態碼
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrIncorrectPassword):
+		traits.RespondError(c, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		traits.RespondError(c, http.StatusUnprocessableEntity, err.Error(), nil)
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, services.ErrTwoFactorNotEnabled),
+		errors.Is(err, services.ErrTwoFactorSetupRequired):
+		traits.RespondError(c, http.StatusBadRequest, err.Error(), nil)
+	default:
+		traits.RespondError(c, http.StatusInternalServerError, err.Error(), nil)
+	}
+}
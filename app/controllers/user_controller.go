@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -8,6 +9,7 @@ import (
 	"my-api/app"
 	"my-api/app/pkg/logger"
 	"my-api/app/requests"
+	"my-api/app/services"
 	"my-api/app/traits"
 )
 
@@ -159,6 +161,10 @@ func (ctrl *UserController) Destroy(c *gin.Context) {
 
 	// 呼叫 Service 刪除使用者
 	if err := ctrl.app.UserService.DeleteUser(uint(id)); err != nil {
+		if errors.Is(err, services.ErrLastAdmin) {
+			traits.RespondError(c, http.StatusConflict, err.Error(), nil)
+			return
+		}
 		log.Warning("刪除使用者失敗：找不到使用者", map[string]interface{}{
 			"user_id": id,
 			"error":   err.Error(),
@@ -173,3 +179,32 @@ func (ctrl *UserController) Destroy(c *gin.Context) {
 
 	traits.RespondSuccess(c, nil, "使用者刪除成功")
 }
+
+// Unlock - 解除使用者的登入鎖定
+// POST /api/users/:id/unlock
+func (ctrl *UserController) Unlock(c *gin.Context) {
+	log := logger.FromGinContext(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		traits.RespondError(c, http.StatusBadRequest, "無效的使用者 ID", err.Error())
+		return
+	}
+
+	user, err := ctrl.app.AuthService.UnlockUser(uint(id))
+	if err != nil {
+		traits.RespondNotFound(c, err.Error())
+		return
+	}
+
+	// 安全性事件：記錄是哪位管理員解除鎖定
+	adminID, _ := c.Get("user_id")
+	log.WithContext(map[string]interface{}{"channel": "security"}).Info("管理員解除帳號鎖定", map[string]interface{}{
+		"event":    "login_unlocked",
+		"user_id":  user.ID,
+		"email":    user.Email,
+		"admin_id": adminID,
+	})
+
+	traits.RespondSuccess(c, nil, "已解除帳號鎖定")
+}
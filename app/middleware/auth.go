@@ -5,12 +5,26 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"my-api/app/utils"
+	"my-api/app"
+	"my-api/app/models"
+	"my-api/app/services"
 )
 
-// AuthMiddleware - JWT 驗證中間件
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware - 驗證中間件
+//
+// 接受兩種憑證：
+//   - JWT：Authorization: Bearer <jwt>，驗證簽名與過期時間外，也會檢查 Token 或所屬的登入裝置是否已被撤銷（登出）
+//   - 個人存取 Token：X-API-Key: pat_xxx，或 Authorization: Bearer pat_xxx
+//
+// Context 中的 token_scopes 為目前憑證的 Scope（JWT 為 ["*"]），可搭配 RequireScope 使用
+func AuthMiddleware(application *app.App) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// API Key header
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			authenticateAccessToken(c, application, apiKey)
+			return
+		}
+
 		// 從 Header 取得 Token
 		authHeader := c.GetHeader("Authorization")
 
@@ -36,8 +50,14 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		// 驗證 JWT Token
-		claims, err := utils.ValidateToken(tokenString)
+		// 個人存取 Token 以固定前綴區分
+		if strings.HasPrefix(tokenString, services.PersonalAccessTokenPrefix) {
+			authenticateAccessToken(c, application, tokenString)
+			return
+		}
+
+		// 驗證 JWT Token（含撤銷清單檢查）
+		claims, err := application.AuthService.Authenticate(tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"success": false,
@@ -47,10 +67,66 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// 依 Token 內的角色取得權限（RoleService 內有快取）
+		permissions, err := application.RoleService.PermissionsForRoles(claims.Roles)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "取得權限失敗",
+			})
+			c.Abort()
+			return
+		}
+
 		// 將用戶資訊存入 context，供後續 handler 使用
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
+		c.Set("roles", claims.Roles)
+		c.Set("permissions", permissions)
+		c.Set("jwt_claims", claims) // 登出時需要 jti 與過期時間
+		c.Set("token_scopes", []string{models.ScopeAll})
 
 		c.Next()
 	}
 }
+
+// authenticateAccessToken - 以個人存取 Token 驗證
+// 權限為使用者角色權限與 Token Scope 的交集
+func authenticateAccessToken(c *gin.Context, application *app.App, plainTextToken string) {
+	token, err := application.AccessTokenService.Authenticate(plainTextToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "無效或已過期的授權憑證",
+		})
+		c.Abort()
+		return
+	}
+
+	roles := token.User.RoleNames()
+	permissions, err := application.RoleService.PermissionsForRoles(roles)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "取得權限失敗",
+		})
+		c.Abort()
+		return
+	}
+
+	scoped := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if token.Can(permission) {
+			scoped = append(scoped, permission)
+		}
+	}
+
+	c.Set("user_id", token.UserID)
+	c.Set("user_email", token.User.Email)
+	c.Set("roles", roles)
+	c.Set("permissions", scoped)
+	c.Set("token_scopes", token.Scopes)
+	c.Set("access_token", token) // 目前使用的個人存取 Token
+
+	c.Next()
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"my-api/app/traits"
+)
+
+// RequirePermission - 權限檢查中間件（類似 Laravel 的 can:users.delete）
+// 必須放在 AuthMiddleware 之後；傳入多個權限時需全部具備
+//
+// 使用方式：
+//
+//	users.DELETE("/:id", middleware.RequirePermission("users.delete"), userCtrl.Destroy)
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, permission := range permissions {
+			if !HasPermission(c, permission) {
+				traits.RespondForbidden(c, "沒有執行此操作的權限")
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// HasPermission - 檢查目前使用者是否具備指定權限
+func HasPermission(c *gin.Context, permission string) bool {
+	value, exists := c.Get("permissions")
+	if !exists {
+		return false
+	}
+
+	granted, ok := value.([]string)
+	if !ok {
+		return false
+	}
+
+	for _, p := range granted {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
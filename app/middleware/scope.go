@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"my-api/app/models"
+	"my-api/app/traits"
+)
+
+// RequireScope - Token Scope 檢查中間件（類似 Laravel Sanctum 的 abilities）
+// 必須放在 AuthMiddleware 之後；JWT 登入的 Scope 為 "*"，一律通過
+//
+// 使用方式：
+//
+//	posts.POST("", middleware.RequireScope("posts.write"), postCtrl.Store)
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, scope := range scopes {
+			if !TokenCan(c, scope) {
+				traits.RespondForbidden(c, "Token 沒有執行此操作的 Scope")
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireJWT - 只允許 JWT 登入（不接受個人存取 Token）
+// 用於管理 Token 本身等敏感操作，避免 Token 被拿來產生新的 Token
+func RequireJWT() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("jwt_claims"); !ok {
+			traits.RespondForbidden(c, "此操作需要登入，不能使用 API Token")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// TokenCan - 目前的憑證是否具備指定 Scope
+func TokenCan(c *gin.Context, scope string) bool {
+	value, exists := c.Get("token_scopes")
+	if !exists {
+		return false
+	}
+
+	scopes, ok := value.([]string)
+	if !ok {
+		return false
+	}
+
+	for _, s := range scopes {
+		if s == models.ScopeAll || s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"my-api/app/traits"
+	"my-api/app/utils"
+)
+
+// ValidateSignature - 簽章連結驗證中間件（類似 Laravel 的 signed 中間件）
+// 連結由 utils.SignURL 產生，簽章不符或過期時回應 403
+func ValidateSignature() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := utils.ValidateSignedURL(c.Request.URL); err != nil {
+			traits.RespondForbidden(c, err.Error())
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"my-api/app"
+	"my-api/app/traits"
+)
+
+// RequireVerified - 要求 Email 已驗證（類似 Laravel 的 verified 中間件）
+// 必須放在 AuthMiddleware 之後；路由群組可自行選擇是否套用
+//
+// 使用方式：
+//
+//	posts.Use(middleware.RequireVerified(application))
+func RequireVerified(application *app.App) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			traits.RespondUnauthorized(c, "未授權")
+			c.Abort()
+			return
+		}
+
+		// 每次都查資料庫，驗證完成後不需要重新取得 Token 就能生效
+		user, err := application.UserRepository.FindByID(userID.(uint))
+		if err != nil {
+			traits.RespondUnauthorized(c, "使用者不存在")
+			c.Abort()
+			return
+		}
+
+		if !user.HasVerifiedEmail() {
+			traits.RespondForbidden(c, "請先完成 Email 驗證")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
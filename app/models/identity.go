@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Identity 外部身分（OpenID Connect 登入）
+//
+// 以 Provider + Subject（IdP 的 sub）識別，一個使用者可以連結多個 IdP；
+// Email 只是最後一次登入時 IdP 提供的參考資訊，不用來比對身分。
+type Identity struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;index"`
+	Provider    string    `json:"provider" gorm:"type:varchar(50);not null;uniqueIndex:idx_identities_provider_subject"`
+	Subject     string    `json:"-" gorm:"type:varchar(255);not null;uniqueIndex:idx_identities_provider_subject"`
+	Email       string    `json:"email" gorm:"type:varchar(255)"`
+	LastLoginAt time.Time `json:"last_login_at" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// LoginHistory 登入紀錄
+// 成功登入時 SessionID 指向建立的工作階段；密碼錯誤時 Successful 為 false
+type LoginHistory struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"not null;index"`
+	SessionID  *uint     `json:"session_id"`
+	Successful bool      `json:"successful" gorm:"not null"`
+	IPAddress  string    `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent  string    `json:"user_agent" gorm:"type:varchar(255)"`
+	CreatedAt  time.Time `json:"created_at"`
+}
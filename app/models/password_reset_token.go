@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PasswordResetToken 重設密碼 Token 模型
+//
+// Token 本身只出現在寄給使用者的信件中，資料庫只存 SHA-256 雜湊；
+// 使用過（UsedAt）或過期的 Token 都不能再用。
+type PasswordResetToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"type:char(64);uniqueIndex;not null"` // 只存 SHA-256 雜湊
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Token Scope
+// 除了下列 Scope，使用者擁有的權限名稱（例如 users.view）也可以當作 Scope
+const (
+	ScopeAll        = "*"           // 不限制範圍（與使用者本身的權限相同）
+	ScopePostsRead  = "posts.read"  // 讀取文章
+	ScopePostsWrite = "posts.write" // 新增、修改、刪除文章
+)
+
+// PersonalAccessToken 個人存取 Token 模型（API Key）
+//
+// 給 CI、外部整合等無法走密碼登入的情境使用，由使用者自行建立與撤銷。
+// Token 明文只在建立時回傳一次，資料庫只存 SHA-256 雜湊；
+// Scopes 限制 Token 能做的事，實際權限為使用者權限與 Scopes 的交集。
+type PersonalAccessToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	User       *User      `json:"-"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	TokenHash  string     `json:"-" gorm:"type:char(64);uniqueIndex;not null"` // 只存 SHA-256 雜湊
+	Scopes     []string   `json:"scopes" gorm:"serializer:json;type:text"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // nil 表示不會過期
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Can - Token 是否具備指定 Scope
+func (t *PersonalAccessToken) Can(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == ScopeAll || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired - Token 是否已過期
+func (t *PersonalAccessToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
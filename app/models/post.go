@@ -5,7 +5,7 @@ import "gorm.io/gorm"
 type Post struct {
 	gorm.Model
 	Title       string `json:"title" gorm:"type:varchar(255);not null"`
-	Content     string `json:"content" gorm:"type:longtext;not null"`
+	Content     string `json:"content" gorm:"not null"` // 不指定長度：MySQL 為 longtext，Postgres 為 text
 	Description string `json:"description" gorm:"type:varchar(500)"`
 	UserID      uint   `json:"user_id" gorm:"not null;index"`
 	User        User   `json:"user" gorm:"foreignKey:UserID"`
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RefreshToken Refresh Token 模型
+//
+// 每次使用 Refresh Token 換發新 Token 時都會輪替（rotation）：
+// 舊的紀錄標記 UsedAt，新的紀錄沿用同一個 FamilyID。
+// 若已使用過的 Token 再次出現，代表可能被竊取，整個 Family 會被撤銷。
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	FamilyID  string     `json:"family_id" gorm:"type:varchar(36);not null;index"`
+	TokenHash string     `json:"-" gorm:"type:char(64);uniqueIndex;not null"` // 只存 SHA-256 雜湊
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`    // 已輪替換發的時間
+	RevokedAt *time.Time `json:"revoked_at"` // 被撤銷的時間
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
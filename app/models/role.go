@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Role 角色模型（多對多：users ↔ roles ↔ permissions）
+type Role struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	Name        string       `json:"name" gorm:"type:varchar(50);uniqueIndex;not null"`
+	Description string       `json:"description" gorm:"type:varchar(255)"`
+	Permissions []Permission `json:"permissions,omitempty" gorm:"many2many:role_permissions;"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Permission 權限模型，名稱採「資源.動作」格式，例如 users.delete
+type Permission struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"type:varchar(100);uniqueIndex;not null"`
+	Description string    `json:"description" gorm:"type:varchar(255)"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// 系統內建角色
+const (
+	RoleAdmin = "admin" // 系統管理員，擁有所有權限
+	RoleUser  = "user"  // 一般使用者，註冊時預設指派
+)
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// Session 登入工作階段（裝置）
+//
+// 每次登入（或註冊）建立一筆，Refresh Token 輪替時沿用同一筆；
+// FamilyID 與 RefreshToken.FamilyID 相同，也會寫入 Access Token 的 sid，
+// 撤銷工作階段後，該裝置的 Access Token 與 Refresh Token 都會失效。
+type Session struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	FamilyID   string     `json:"-" gorm:"type:varchar(36);uniqueIndex;not null"`
+	IPAddress  string     `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent  string     `json:"user_agent" gorm:"type:varchar(255)"`
+	LastSeenAt time.Time  `json:"last_seen_at" gorm:"not null"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"` // Refresh Token 的到期時間，每次刷新都會延長
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// IsActive - 工作階段是否仍有效（未撤銷、未過期）
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // User 使用者模型
 //
@@ -23,8 +27,43 @@ import "gorm.io/gorm"
 //   - binding:"required"   → Gin 綁定驗證
 type User struct {
 	gorm.Model
-	Name     string `json:"name" gorm:"type:varchar(100);not null"`         // 輸出為 "name"
-	Email    string `json:"email" gorm:"type:varchar(100);uniqueIndex;not null"` // 輸出為 "email"
-	Password string `json:"-" gorm:"type:varchar(255)"`                     // "-" 表示隱藏，不輸出到 JSON
-	Age      int    `json:"age"`                                            // 輸出為 "age"
+	Name            string     `json:"name" gorm:"type:varchar(100);not null"`              // 輸出為 "name"
+	Email           string     `json:"email" gorm:"type:varchar(100);uniqueIndex;not null"` // 輸出為 "email"
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`                                   // Email 驗證時間，nil 表示尚未驗證
+	Password        string     `json:"-" gorm:"type:varchar(255)"`                          // "-" 表示隱藏，不輸出到 JSON
+	Age             int        `json:"age"`                                                 // 輸出為 "age"
+	Roles           []Role     `json:"roles,omitempty" gorm:"many2many:user_roles;"`        // 多對多關聯
+
+	// 兩步驟驗證（TOTP）
+	TwoFactorSecret        string     `json:"-" gorm:"type:text"`                 // 以 APP_KEY 加密的 TOTP 金鑰
+	TwoFactorRecoveryCodes []string   `json:"-" gorm:"serializer:json;type:text"` // 復原碼的 SHA-256 雜湊，使用後移除
+	TwoFactorLastStep      int64      `json:"-" gorm:"not null;default:0"`        // 最後一次使用的時間區間，避免驗證碼重複使用
+	TwoFactorConfirmedAt   *time.Time `json:"two_factor_confirmed_at"`            // 完成設定的時間，nil 表示未啟用
+
+	// 刪除帳號（寬限期內可以取消，期滿後匿名化）
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at" gorm:"index"` // 預定刪除的時間，nil 表示沒有刪除申請
+}
+
+// HasVerifiedEmail - Email 是否已驗證
+func (u *User) HasVerifiedEmail() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// HasTwoFactorEnabled - 是否已啟用兩步驟驗證
+func (u *User) HasTwoFactorEnabled() bool {
+	return u.TwoFactorConfirmedAt != nil && u.TwoFactorSecret != ""
+}
+
+// IsPendingDeletion - 是否已申請刪除帳號（仍在寬限期內）
+func (u *User) IsPendingDeletion() bool {
+	return u.DeletionScheduledAt != nil
+}
+
+// RoleNames - 角色名稱列表（需先 Preload Roles）
+func (u *User) RoleNames() []string {
+	names := make([]string, 0, len(u.Roles))
+	for _, role := range u.Roles {
+		names = append(names, role.Name)
+	}
+	return names
 }
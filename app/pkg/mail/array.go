@@ -0,0 +1,43 @@
+package mail
+
+import "sync"
+
+// ArrayMailer - 信件只保留在記憶體中（測試用，類似 Laravel 的 array driver）
+type ArrayMailer struct {
+	mu       sync.Mutex
+	from     string
+	messages []Message
+}
+
+// NewArrayMailer - 建立 ArrayMailer
+func NewArrayMailer(from string) *ArrayMailer {
+	return &ArrayMailer{from: from}
+}
+
+// Send - 實作 Mailer 介面
+func (m *ArrayMailer) Send(msg *Message) error {
+	prepare(msg, m.from)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, *msg)
+	return nil
+}
+
+// Messages - 取得目前為止寄出的信件
+func (m *ArrayMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+// Last - 取得最後一封信件，沒有信件時回傳 nil
+func (m *ArrayMailer) Last() *Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.messages) == 0 {
+		return nil
+	}
+	msg := m.messages[len(m.messages)-1]
+	return &msg
+}
@@ -0,0 +1,62 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// FileMailer - 每封信件寫成一個 .eml 檔（方便在本機直接打開查看）
+type FileMailer struct {
+	dir  string
+	from string
+	seq  atomic.Uint64
+}
+
+// NewFileMailer - 建立 FileMailer，信件存放在 dir 目錄
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+// Send - 實作 Mailer 介面
+func (m *FileMailer) Send(msg *Message) error {
+	prepare(msg, m.from)
+
+	if err := os.MkdirAll(m.dir, 0755); err != nil {
+		return fmt.Errorf("建立郵件目錄失敗: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%d_%s.eml",
+		msg.SentAt.Format("20060102_150405"),
+		m.seq.Add(1),
+		sanitizeFilename(msg.To),
+	)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", msg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.SentAt.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("寫入郵件檔案失敗: %w", err)
+	}
+	return nil
+}
+
+// sanitizeFilename - 收件者地址轉為安全的檔名
+func sanitizeFilename(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
@@ -0,0 +1,29 @@
+package mail
+
+import "my-api/app/pkg/logger"
+
+// LogMailer - 把信件內容寫入日誌（開發環境預設）
+type LogMailer struct {
+	log  *logger.Logger
+	from string
+}
+
+// NewLogMailer - 建立 LogMailer
+func NewLogMailer(log *logger.Logger, from string) *LogMailer {
+	return &LogMailer{log: log, from: from}
+}
+
+// Send - 實作 Mailer 介面
+func (m *LogMailer) Send(msg *Message) error {
+	prepare(msg, m.from)
+
+	if m.log != nil {
+		m.log.Info("寄送郵件", map[string]interface{}{
+			"from":    msg.From,
+			"to":      msg.To,
+			"subject": msg.Subject,
+			"body":    msg.Body,
+		})
+	}
+	return nil
+}
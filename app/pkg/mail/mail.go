@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"fmt"
+	"time"
+
+	"my-api/app/pkg/logger"
+	"my-api/config"
+)
+
+// Message - 一封信件
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string // 純文字內容
+	SentAt  time.Time
+}
+
+// Mailer - 寄信介面（類似 Laravel 的 Mail facade）
+// 目前提供 log、file、array 三種 driver，不需要郵件伺服器就能完成整個流程；
+// 之後接 SMTP 或第三方服務時實作這個介面即可
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// New - 依設定建立 Mailer
+func New(cfg config.MailConfig, log *logger.Logger) (Mailer, error) {
+	from := cfg.FromAddress
+	if cfg.FromName != "" {
+		from = fmt.Sprintf("%s <%s>", cfg.FromName, cfg.FromAddress)
+	}
+
+	switch cfg.Driver {
+	case "", "log":
+		return NewLogMailer(log, from), nil
+	case "file":
+		return NewFileMailer(cfg.FilePath, from), nil
+	case "array":
+		return NewArrayMailer(from), nil
+	default:
+		return nil, fmt.Errorf("不支援的 MAIL_DRIVER: %s", cfg.Driver)
+	}
+}
+
+// prepare - 補上寄件者與寄送時間
+func prepare(msg *Message, from string) {
+	if msg.From == "" {
+		msg.From = from
+	}
+	msg.SentAt = time.Now()
+}
@@ -0,0 +1,275 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// clockSkew - 驗證 exp / iat 時容許與 IdP 的時鐘誤差
+const clockSkew = time.Minute
+
+// supportedAlgorithms - 接受的 ID Token 簽章演算法（不接受 none 與 HS*）
+var supportedAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// IDToken - 驗證通過的 ID Token claims
+type IDToken struct {
+	Nonce           string `json:"nonce"`
+	Email           string `json:"email"`
+	EmailVerified   Bool   `json:"email_verified"`
+	Name            string `json:"name"`
+	AuthorizedParty string `json:"azp,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Bool - 布林值的 claim，同時接受 JSON 布林值與 "true"、"false" 字串
+// 規格定義 email_verified 為布林值，但 Amazon Cognito、舊版 Azure AD 會以字串簽發
+type Bool bool
+
+// UnmarshalJSON - 解析布林值或 "true"、"false" 字串，其他值回傳錯誤
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case nil:
+		*b = false
+	case bool:
+		*b = Bool(v)
+	case string:
+		switch strings.ToLower(v) {
+		case "true":
+			*b = true
+		case "false":
+			*b = false
+		default:
+			return fmt.Errorf("無法解析布林值 %q", v)
+		}
+	default:
+		return fmt.Errorf("無法解析布林值 %s", data)
+	}
+	return nil
+}
+
+// VerifyIDToken - 驗證 ID Token（OpenID Connect Core 3.1.3.7）
+// 檢查簽章（IdP 公開的 JWKS）、iss、aud、exp、iat、nonce；失敗時回傳包裝 ErrInvalidIDToken 的錯誤
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*IDToken, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(p.signingAlgorithms(doc)),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+
+	p.mu.Lock()
+	keys := p.keys
+	p.mu.Unlock()
+
+	claims := &IDToken{}
+	_, err = parser.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return keys.lookup(ctx, p, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: 缺少 sub", ErrInvalidIDToken)
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("%w: nonce 不符", ErrInvalidIDToken)
+	}
+	// 發給多個 audience 時，azp 必須是自己
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, fmt.Errorf("%w: azp 不符", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+// signingAlgorithms - IdP 宣告的演算法與本套件支援的交集；未宣告時規格預設為 RS256
+func (p *Provider) signingAlgorithms(doc *Discovery) []string {
+	if len(doc.IDTokenSigningAlgValuesSupported) == 0 {
+		return []string{"RS256"}
+	}
+
+	var algorithms []string
+	for _, alg := range doc.IDTokenSigningAlgValuesSupported {
+		if slices.Contains(supportedAlgorithms, alg) {
+			algorithms = append(algorithms, alg)
+		}
+	}
+	return algorithms
+}
+
+// keyCache - IdP 簽章公鑰快取（以 kid 查詢）
+// IdP 輪替金鑰後會出現新的 kid，此時重新下載 JWKS；
+// ID Token 是伺服器直接向 token endpoint 取得的，不會有外部傳入大量未知 kid 的情況
+type keyCache struct {
+	uri string
+
+	mu   sync.Mutex
+	keys map[string]crypto.PublicKey
+}
+
+func newKeyCache(uri string) *keyCache {
+	return &keyCache{uri: uri}
+}
+
+// lookup - 取得 kid 對應的公鑰；ID Token 沒有 kid 時，JWKS 只有一把簽章金鑰才能使用
+func (c *keyCache) lookup(ctx context.Context, p *Provider, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.find(kid); ok {
+		return key, nil
+	}
+
+	if err := c.refresh(ctx, p); err != nil {
+		return nil, err
+	}
+	if key, ok := c.find(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("找不到 kid %q 的公鑰", kid)
+}
+
+// find - 從快取找公鑰
+func (c *keyCache) find(kid string) (crypto.PublicKey, bool) {
+	if kid == "" {
+		if len(c.keys) != 1 {
+			return nil, false
+		}
+		for _, key := range c.keys {
+			return key, true
+		}
+	}
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// refresh - 重新下載 JWKS，略過無法解析或用於加密的金鑰
+func (c *keyCache) refresh(ctx context.Context, p *Provider) error {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, c.uri, &set); err != nil {
+		return fmt.Errorf("取得 IdP 公鑰失敗: %v", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	c.keys = keys
+	return nil
+}
+
+// jsonWebKey - JWKS 中的一把公鑰（RFC 7517）
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey - 轉成 Go 的公鑰型別
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent 不合法")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("不支援的曲線 %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("EC 公鑰不在曲線上")
+		}
+		return key, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("不支援的曲線 %q", k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("Ed25519 公鑰長度錯誤")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("不支援的金鑰類型 %q", k.Kty)
+	}
+}
@@ -0,0 +1,236 @@
+// Package oidc 實作 OpenID Connect 授權碼流程（Authorization Code + PKCE）的用戶端
+//
+// 支援任何提供 discovery 文件（{issuer}/.well-known/openid-configuration）的 IdP，
+// 例如公司內部的 IdP、Google、Microsoft Entra ID、GitLab、Keycloak：
+//
+//	provider := oidc.NewProvider(oidc.Config{Issuer: "https://accounts.google.com", ClientID: "...", ...})
+//	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier) // 導向 IdP 登入
+//	token, err := provider.Exchange(ctx, code, verifier)               // 回呼時以授權碼換取 Token
+//	idToken, err := provider.VerifyIDToken(ctx, token.IDToken, nonce)  // 驗證簽章、iss、aud、exp、nonce
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// discoveryTTL - discovery 文件快取多久
+	discoveryTTL = 24 * time.Hour
+	// requestTimeout - 呼叫 IdP 的逾時時間
+	requestTimeout = 10 * time.Second
+	// maxResponseSize - IdP 回應最大讀取位元組數
+	maxResponseSize = 1 << 20
+)
+
+// defaultScopes - 未設定 Scopes 時要求的權限
+var defaultScopes = []string{"openid", "email", "profile"}
+
+// ErrInvalidIDToken - ID Token 簽章、iss、aud、exp 或 nonce 驗證失敗
+var ErrInvalidIDToken = errors.New("ID Token 無效")
+
+// Config - IdP 用戶端設定
+type Config struct {
+	Issuer       string   // IdP 的 issuer（必須與 discovery 文件中的 issuer 完全相同）
+	ClientID     string   // 在 IdP 註冊的 client_id
+	ClientSecret string   // 公開用戶端（只用 PKCE）可以留空
+	RedirectURL  string   // 授權後導回的網址（需在 IdP 登記）
+	Scopes       []string // 空白時使用 openid email profile
+}
+
+// Discovery - OpenID Provider Metadata（只取用到的欄位）
+type Discovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// Token - Token endpoint 的回應
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	IDToken      string `json:"id_token"`
+}
+
+// Provider - 單一 IdP 的用戶端
+// discovery 文件與簽章公鑰在第一次使用時才下載並快取，IdP 暫時無法連線不會影響啟動
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mu           sync.Mutex
+	discovery    *Discovery
+	discoveredAt time.Time
+	keys         *keyCache
+}
+
+// NewProvider - 建立 IdP 用戶端
+func NewProvider(cfg Config) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = defaultScopes
+	}
+	return &Provider{
+		config: cfg,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Config - 用戶端設定
+func (p *Provider) Config() Config {
+	return p.config
+}
+
+// Discover - 取得 discovery 文件（快取 24 小時）
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil && time.Since(p.discoveredAt) < discoveryTTL {
+		return p.discovery, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	var doc Discovery
+	if err := p.getJSON(ctx, wellKnown, &doc); err != nil {
+		return nil, fmt.Errorf("取得 discovery 文件失敗: %v", err)
+	}
+
+	// 防止被導向其他 IdP（OpenID Connect Discovery 4.3）
+	if doc.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("discovery 文件的 issuer %q 與設定的 %q 不符", doc.Issuer, p.config.Issuer)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("discovery 文件缺少 authorization_endpoint、token_endpoint 或 jwks_uri")
+	}
+	if len(doc.CodeChallengeMethodsSupported) > 0 && !slices.Contains(doc.CodeChallengeMethodsSupported, "S256") {
+		return nil, errors.New("IdP 不支援 PKCE S256")
+	}
+
+	p.discovery = &doc
+	p.discoveredAt = time.Now()
+	if p.keys == nil || p.keys.uri != doc.JWKSURI {
+		p.keys = newKeyCache(doc.JWKSURI)
+	}
+	return p.discovery, nil
+}
+
+// AuthCodeURL - 產生導向 IdP 的授權網址
+// state 防止 CSRF，nonce 綁定 ID Token，verifier 為 PKCE code_verifier（只送出其 S256 雜湊）
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("authorization_endpoint 格式錯誤: %v", err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", S256Challenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange - 以授權碼與 PKCE code_verifier 換取 Token
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	doc, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {verifier},
+		"client_id":     {p.config.ClientID},
+	}
+
+	// 未列出支援的方式時，規格預設為 client_secret_basic
+	useBasic := p.config.ClientSecret != "" &&
+		(len(doc.TokenEndpointAuthMethodsSupported) == 0 || slices.Contains(doc.TokenEndpointAuthMethodsSupported, "client_secret_basic"))
+	if p.config.ClientSecret != "" && !useBasic {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if useBasic {
+		// RFC 6749 2.3.1：client_id 與 secret 需先做 form-urlencode
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("換取 Token 失敗: %v", err)
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, resp.Body, maxResponseSize))
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		_ = decoder.Decode(&oauthErr)
+		return nil, fmt.Errorf("換取 Token 失敗: HTTP %d %s %s", resp.StatusCode, oauthErr.Error, oauthErr.ErrorDescription)
+	}
+
+	var token Token
+	if err := decoder.Decode(&token); err != nil {
+		return nil, fmt.Errorf("解析 Token 回應失敗: %v", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("Token 回應缺少 id_token（scope 是否包含 openid？）")
+	}
+	return &token, nil
+}
+
+// getJSON - GET 並解析 JSON 回應
+func (p *Provider) getJSON(ctx context.Context, target string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s 回應 HTTP %d", target, resp.StatusCode)
+	}
+	return json.NewDecoder(http.MaxBytesReader(nil, resp.Body, maxResponseSize)).Decode(v)
+}
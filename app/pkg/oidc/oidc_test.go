@@ -0,0 +1,205 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"my-api/app/pkg/oidc"
+	"my-api/app/pkg/oidc/oidctest"
+)
+
+const redirectURL = "http://localhost:8080/api/auth/oidc/test/callback"
+
+// login 走完授權碼流程，回傳 IdP 簽發的 ID Token
+func login(t *testing.T, idp *oidctest.Server, provider *oidc.Provider, nonce string) *oidc.Token {
+	t.Helper()
+	ctx := context.Background()
+
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		t.Fatalf("GenerateVerifier() 發生錯誤: %v", err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state-123", nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL() 發生錯誤: %v", err)
+	}
+
+	code, state, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize() 發生錯誤: %v", err)
+	}
+	if state != "state-123" {
+		t.Fatalf("state 應原樣帶回，got %q", state)
+	}
+
+	token, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange() 發生錯誤: %v", err)
+	}
+	return token
+}
+
+// TestProvider_AuthorizationCodeFlow 測試完整的授權碼 + PKCE 流程
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	idp := oidctest.NewServer()
+	defer idp.Close()
+	idp.SetUser(oidctest.User{Subject: "abc-123", Email: "alice@example.com", EmailVerified: true, Name: "Alice"})
+
+	provider := oidc.NewProvider(idp.Config(redirectURL))
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "s", "n", "verifier")
+	if err != nil {
+		t.Fatalf("AuthCodeURL() 發生錯誤: %v", err)
+	}
+	query, _ := url.Parse(authURL)
+	if query.Query().Get("code_challenge") != oidc.S256Challenge("verifier") || query.Query().Get("scope") != "openid email profile" {
+		t.Errorf("授權網址參數不符: %s", authURL)
+	}
+
+	token := login(t, idp, provider, "nonce-1")
+	claims, err := provider.VerifyIDToken(context.Background(), token.IDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken() 發生錯誤: %v", err)
+	}
+	if claims.Subject != "abc-123" || claims.Email != "alice@example.com" || !claims.EmailVerified || claims.Name != "Alice" {
+		t.Errorf("claims 不符: %+v", claims)
+	}
+}
+
+// TestProvider_VerifyIDToken_EmailVerified 測試 email_verified 為布林值或字串（Cognito、舊版 Azure AD）都能解析
+func TestProvider_VerifyIDToken_EmailVerified(t *testing.T) {
+	idp := oidctest.NewServer()
+	defer idp.Close()
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    bool
+		wantErr bool
+	}{
+		{name: "布林值 true", value: true, want: true},
+		{name: "布林值 false", value: false, want: false},
+		{name: "字串 true", value: "true", want: true},
+		{name: "字串 false", value: "false", want: false},
+		{name: "沒有 email_verified", value: nil, want: false},
+		{name: "無法解析的字串", value: "yes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp.ModifyClaims = func(c jwt.MapClaims) {
+				if tt.value == nil {
+					delete(c, "email_verified")
+					return
+				}
+				c["email_verified"] = tt.value
+			}
+			provider := oidc.NewProvider(idp.Config(redirectURL))
+			token := login(t, idp, provider, "nonce-1")
+
+			claims, err := provider.VerifyIDToken(context.Background(), token.IDToken, "nonce-1")
+			if tt.wantErr {
+				if !errors.Is(err, oidc.ErrInvalidIDToken) {
+					t.Errorf("預期 ErrInvalidIDToken，got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyIDToken() 發生錯誤: %v", err)
+			}
+			if bool(claims.EmailVerified) != tt.want {
+				t.Errorf("EmailVerified = %v, want %v", claims.EmailVerified, tt.want)
+			}
+		})
+	}
+}
+
+// TestProvider_VerifyIDToken_Invalid 測試拒絕 nonce、aud、iss、exp 不符的 ID Token
+func TestProvider_VerifyIDToken_Invalid(t *testing.T) {
+	idp := oidctest.NewServer()
+	defer idp.Close()
+
+	tests := []struct {
+		name   string
+		nonce  string
+		modify func(claims jwt.MapClaims)
+	}{
+		{name: "nonce 不符", nonce: "other-nonce"},
+		{name: "aud 不是自己", nonce: "nonce-1", modify: func(c jwt.MapClaims) { c["aud"] = "another-client" }},
+		{name: "iss 不符", nonce: "nonce-1", modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "已過期", nonce: "nonce-1", modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "多個 aud 但 azp 不是自己", nonce: "nonce-1", modify: func(c jwt.MapClaims) {
+			c["aud"] = []string{"test-client", "another-client"}
+			c["azp"] = "another-client"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp.ModifyClaims = tt.modify
+			provider := oidc.NewProvider(idp.Config(redirectURL))
+			token := login(t, idp, provider, "nonce-1")
+
+			if _, err := provider.VerifyIDToken(context.Background(), token.IDToken, tt.nonce); !errors.Is(err, oidc.ErrInvalidIDToken) {
+				t.Errorf("預期 ErrInvalidIDToken，got %v", err)
+			}
+		})
+	}
+}
+
+// TestProvider_Exchange_PKCE 測試 code_verifier 不符時無法換取 Token，且授權碼只能用一次
+func TestProvider_Exchange_PKCE(t *testing.T) {
+	idp := oidctest.NewServer()
+	defer idp.Close()
+	provider := oidc.NewProvider(idp.Config(redirectURL))
+	ctx := context.Background()
+
+	authURL, _ := provider.AuthCodeURL(ctx, "state", "nonce", "correct-verifier-correct-verifier-correct-v")
+	code, _, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize() 發生錯誤: %v", err)
+	}
+
+	if _, err := provider.Exchange(ctx, code, "wrong-verifier-wrong-verifier-wrong-verifie"); err == nil {
+		t.Error("code_verifier 不符應該失敗")
+	}
+	if _, err := provider.Exchange(ctx, code, "correct-verifier-correct-verifier-correct-v"); err == nil {
+		t.Error("授權碼不能重複使用")
+	}
+}
+
+// TestProvider_KeyRotation 測試 IdP 輪替金鑰後重新下載公鑰
+func TestProvider_KeyRotation(t *testing.T) {
+	idp := oidctest.NewServer()
+	defer idp.Close()
+	provider := oidc.NewProvider(idp.Config(redirectURL))
+
+	token := login(t, idp, provider, "nonce")
+	if _, err := provider.VerifyIDToken(context.Background(), token.IDToken, "nonce"); err != nil {
+		t.Fatalf("VerifyIDToken() 發生錯誤: %v", err)
+	}
+
+	if err := idp.RotateKey(); err != nil {
+		t.Fatalf("RotateKey() 發生錯誤: %v", err)
+	}
+	token = login(t, idp, provider, "nonce")
+	if _, err := provider.VerifyIDToken(context.Background(), token.IDToken, "nonce"); err != nil {
+		t.Errorf("新的 kid 應該重新下載公鑰: %v", err)
+	}
+}
+
+// TestProvider_Discover_IssuerMismatch 測試 discovery 文件的 issuer 必須與設定相同
+func TestProvider_Discover_IssuerMismatch(t *testing.T) {
+	idp := oidctest.NewServer()
+	defer idp.Close()
+
+	cfg := idp.Config(redirectURL)
+	cfg.Issuer += "/"
+	if _, err := oidc.NewProvider(cfg).Discover(context.Background()); err == nil {
+		t.Error("issuer 不符應該失敗")
+	}
+}
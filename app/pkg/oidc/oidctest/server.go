@@ -0,0 +1,306 @@
+// Package oidctest 提供測試用的 OpenID Connect IdP（類似 net/http/httptest）
+//
+//	idp := oidctest.NewServer()
+//	defer idp.Close()
+//	idp.SetUser(oidctest.User{Subject: "u-1", Email: "alice@example.com", EmailVerified: true})
+//	provider := oidc.NewProvider(idp.Config("http://localhost/callback"))
+//	authURL, _ := provider.AuthCodeURL(ctx, state, nonce, verifier)
+//	code, returnedState, err := idp.Authorize(authURL) // 模擬使用者在 IdP 登入並同意
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"my-api/app/pkg/oidc"
+)
+
+// User - 在 IdP 登入的使用者
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Server - 測試用 IdP，提供 discovery、authorize、token、JWKS endpoint
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	// ModifyClaims - 簽發 ID Token 前修改 claims（測試錯誤的 aud、nonce、exp 等情境）
+	ModifyClaims func(claims jwt.MapClaims)
+
+	mu     sync.Mutex
+	user   User
+	keys   []signingKey
+	grants map[string]grant
+}
+
+// signingKey - 簽章金鑰，最後一把用來簽發新的 ID Token
+type signingKey struct {
+	kid     string
+	private *rsa.PrivateKey
+}
+
+// grant - 已核發、尚未使用的授權碼
+type grant struct {
+	user        User
+	redirectURI string
+	nonce       string
+	challenge   string
+}
+
+// NewServer - 啟動測試用 IdP（使用完畢需呼叫 Close）
+func NewServer() *Server {
+	s := &Server{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		user:         User{Subject: "user-1", Email: "user@example.com", EmailVerified: true, Name: "Test User"},
+		grants:       make(map[string]grant),
+	}
+	if err := s.RotateKey(); err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/authorize", s.handleAuthorize)
+	mux.HandleFunc("/token", s.handleToken)
+	mux.HandleFunc("/jwks", s.handleJWKS)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Config - 連到這個 IdP 的用戶端設定
+func (s *Server) Config(redirectURL string) oidc.Config {
+	return oidc.Config{
+		Issuer:       s.URL,
+		ClientID:     s.ClientID,
+		ClientSecret: s.ClientSecret,
+		RedirectURL:  redirectURL,
+	}
+}
+
+// SetUser - 設定下一次在 IdP 登入的使用者
+func (s *Server) SetUser(user User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.user = user
+}
+
+// RotateKey - 產生新的簽章金鑰（舊金鑰仍保留在 JWKS）
+func (s *Server) RotateKey() error {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, signingKey{kid: fmt.Sprintf("key-%d", len(s.keys)+1), private: private})
+	return nil
+}
+
+// Authorize - 模擬瀏覽器開啟授權網址、使用者登入並同意，回傳導回網址上的 code 與 state
+func (s *Server) Authorize(authURL string) (code, state string, err error) {
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("authorize 回應 HTTP %d", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	query := location.Query()
+	return query.Get("code"), query.Get("state"), nil
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+	})
+}
+
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	switch {
+	case query.Get("response_type") != "code",
+		query.Get("client_id") != s.ClientID,
+		query.Get("redirect_uri") == "",
+		!strings.Contains(" "+query.Get("scope")+" ", " openid "),
+		query.Get("code_challenge_method") != "S256",
+		query.Get("code_challenge") == "":
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomHex()
+	s.mu.Lock()
+	s.grants[code] = grant{
+		user:        s.user,
+		redirectURI: query.Get("redirect_uri"),
+		nonce:       query.Get("nonce"),
+		challenge:   query.Get("code_challenge"),
+	}
+	s.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if !s.authenticateClient(r) {
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	// 授權碼只能使用一次
+	s.mu.Lock()
+	code := r.PostForm.Get("code")
+	g, ok := s.grants[code]
+	delete(s.grants, code)
+	s.mu.Unlock()
+
+	if !ok || g.redirectURI != r.PostForm.Get("redirect_uri") ||
+		oidc.S256Challenge(r.PostForm.Get("code_verifier")) != g.challenge {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	idToken, err := s.signIDToken(g)
+	if err != nil {
+		writeOAuthError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomHex(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	keys := make([]map[string]string, 0, len(s.keys))
+	for _, key := range s.keys {
+		public := key.private.PublicKey
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": key.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   b64(public.N.Bytes()),
+			"e":   b64(big.NewInt(int64(public.E)).Bytes()),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"keys": keys})
+}
+
+// authenticateClient - 檢查 client_secret_basic 或 client_secret_post
+func (s *Server) authenticateClient(r *http.Request) bool {
+	clientID, secret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientID, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	return clientID == s.ClientID && subtle.ConstantTimeCompare([]byte(secret), []byte(s.ClientSecret)) == 1
+}
+
+// signIDToken - 以最新的金鑰簽發 ID Token
+func (s *Server) signIDToken(g grant) (string, error) {
+	s.mu.Lock()
+	key := s.keys[len(s.keys)-1]
+	modify := s.ModifyClaims
+	s.mu.Unlock()
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            s.URL,
+		"sub":            g.user.Subject,
+		"aud":            s.ClientID,
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          g.nonce,
+		"email":          g.user.Email,
+		"email_verified": g.user.EmailVerified,
+		"name":           g.user.Name,
+	}
+	if modify != nil {
+		modify(claims)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.private)
+}
+
+// writeJSON - 輸出 JSON 回應
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeOAuthError - 輸出 OAuth 2.0 錯誤回應（RFC 6749 5.2）
+func writeOAuthError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+// randomHex - 隨機字串（授權碼、Access Token）
+func randomHex() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		panic(errors.New("oidctest: 產生隨機字串失敗"))
+	}
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,25 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// verifierSize - code_verifier 的隨機位元組數（編碼後 43 個字元，RFC 7636 的下限）
+const verifierSize = 32
+
+// GenerateVerifier - 產生 PKCE code_verifier（RFC 7636）
+func GenerateVerifier() (string, error) {
+	bytes := make([]byte, verifierSize)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// S256Challenge - code_challenge = BASE64URL(SHA256(code_verifier))
+func S256Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,163 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minUserInfoLength - 個人資料長度小於此值時不檢查（避免「不可包含 "li"」這種誤判）
+const minUserInfoLength = 3
+
+// User - 檢查「不可包含個人資料」時使用的使用者資料
+type User struct {
+	Name  string
+	Email string
+}
+
+// Rule - 自訂密碼規則，符合時回傳空字串，否則回傳錯誤訊息
+type Rule func(password string, user User) string
+
+// Policy - 密碼規則（類似 Laravel 的 Password::min(8)->mixedCase()->numbers()）
+//
+//	policy := password.Min(10).MixedCase().Numbers().NotContainingUserInfo()
+//	policy.Rule(func(pw string, _ password.User) string { ... }) // 加入自訂規則
+//	err := policy.Validate("secret", password.User{Name: "Alice", Email: "alice@example.com"})
+type Policy struct {
+	minLength int
+	rules     []Rule
+}
+
+// Min - 建立最少 n 個字元的密碼規則
+func Min(n int) *Policy {
+	return &Policy{minLength: n}
+}
+
+// MinLength - 最少字元數
+func (p *Policy) MinLength() int {
+	return p.minLength
+}
+
+// MixedCase - 需同時包含大寫與小寫字母
+func (p *Policy) MixedCase() *Policy {
+	return p.Rule(func(password string, _ User) string {
+		if !containsFunc(password, unicode.IsUpper) || !containsFunc(password, unicode.IsLower) {
+			return "密碼需同時包含大寫與小寫字母"
+		}
+		return ""
+	})
+}
+
+// Numbers - 需包含數字
+func (p *Policy) Numbers() *Policy {
+	return p.Rule(func(password string, _ User) string {
+		if !containsFunc(password, unicode.IsDigit) {
+			return "密碼需包含數字"
+		}
+		return ""
+	})
+}
+
+// Symbols - 需包含符號（字母、數字以外的字元）
+func (p *Policy) Symbols() *Policy {
+	return p.Rule(func(password string, _ User) string {
+		if !containsFunc(password, isSymbol) {
+			return "密碼需包含符號"
+		}
+		return ""
+	})
+}
+
+// NotContainingUserInfo - 不可包含使用者的名稱、Email 或 Email 帳號（@ 前面的部分），不分大小寫
+func (p *Policy) NotContainingUserInfo() *Policy {
+	return p.Rule(func(password string, user User) string {
+		lower := strings.ToLower(password)
+		for _, info := range userInfo(user) {
+			if strings.Contains(lower, info) {
+				return "密碼不可包含名稱或電子郵件"
+			}
+		}
+		return ""
+	})
+}
+
+// Rule - 加入自訂規則
+func (p *Policy) Rule(rule Rule) *Policy {
+	p.rules = append(p.rules, rule)
+	return p
+}
+
+// Validate - 檢查密碼，不符合時回傳 *PolicyError（包含所有不符合的規則）
+func (p *Policy) Validate(password string, user User) error {
+	var messages []string
+	if utf8.RuneCountInString(password) < p.minLength {
+		messages = append(messages, fmt.Sprintf("密碼長度不得小於 %d", p.minLength))
+	}
+	for _, rule := range p.rules {
+		if message := rule(password, user); message != "" {
+			messages = append(messages, message)
+		}
+	}
+
+	if len(messages) > 0 {
+		return &PolicyError{Messages: messages}
+	}
+	return nil
+}
+
+// PolicyError - 密碼不符合規則
+type PolicyError struct {
+	Messages []string
+}
+
+func (e *PolicyError) Error() string {
+	return strings.Join(e.Messages, "；")
+}
+
+var (
+	defaultPolicyMu sync.RWMutex
+	defaultPolicy   = Min(8).NotContainingUserInfo()
+)
+
+// SetDefault - 設定全站使用的密碼規則（bootstrap.InitPasswordPolicy 於啟動時依設定呼叫）
+func SetDefault(p *Policy) {
+	defaultPolicyMu.Lock()
+	defer defaultPolicyMu.Unlock()
+	defaultPolicy = p
+}
+
+// Default - 目前的密碼規則；未設定時為最少 8 個字元且不可包含個人資料
+func Default() *Policy {
+	defaultPolicyMu.RLock()
+	defer defaultPolicyMu.RUnlock()
+	return defaultPolicy
+}
+
+// userInfo - 要比對的個人資料（轉小寫，過短的略過）
+func userInfo(user User) []string {
+	candidates := []string{user.Name, strings.ReplaceAll(user.Name, " ", ""), user.Email}
+	if at := strings.LastIndex(user.Email, "@"); at > 0 {
+		candidates = append(candidates, user.Email[:at])
+	}
+
+	var result []string
+	for _, candidate := range candidates {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if utf8.RuneCountInString(candidate) >= minUserInfoLength {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+// containsFunc - 是否有任一字元符合 f
+func containsFunc(s string, f func(rune) bool) bool {
+	return strings.IndexFunc(s, f) >= 0
+}
+
+// isSymbol - 字母、數字、空白以外的字元
+func isSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
@@ -0,0 +1,67 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestPolicy_Validate 測試各項規則
+func TestPolicy_Validate(t *testing.T) {
+	user := User{Name: "Alice Chen", Email: "alice.chen@example.com"}
+	strict := Min(10).MixedCase().Numbers().Symbols().NotContainingUserInfo()
+
+	tests := []struct {
+		name     string
+		policy   *Policy
+		password string
+		wantErr  bool
+	}{
+		{name: "長度足夠", policy: Min(8), password: "abcdefgh"},
+		{name: "長度不足", policy: Min(8), password: "abcdefg", wantErr: true},
+		{name: "中文以字元計算", policy: Min(4), password: "正確馬匹"},
+		{name: "符合所有規則", policy: strict, password: "Tr0ub4dor&3x"},
+		{name: "缺少大寫", policy: strict, password: "tr0ub4dor&3x", wantErr: true},
+		{name: "缺少數字", policy: strict, password: "Troubador&xx", wantErr: true},
+		{name: "缺少符號", policy: strict, password: "Tr0ub4dor3xx", wantErr: true},
+		{name: "包含名稱（不分大小寫）", policy: strict, password: "ALICECHEN-2026!a", wantErr: true},
+		{name: "包含 Email 帳號", policy: strict, password: "Alice.Chen#2026", wantErr: true},
+		{name: "自訂規則", policy: Min(1).Rule(func(pw string, _ User) string {
+			if pw == "password" {
+				return "太常見"
+			}
+			return ""
+		}), password: "password", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password, user)
+			if tt.wantErr && err == nil {
+				t.Errorf("Validate(%q) 應該失敗", tt.password)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate(%q) 不預期的錯誤: %v", tt.password, err)
+			}
+		})
+	}
+}
+
+// TestPolicy_AllMessages 測試一次回傳所有不符合的規則
+func TestPolicy_AllMessages(t *testing.T) {
+	err := Min(12).MixedCase().Numbers().Validate("short", User{})
+
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("預期 *PolicyError，got %v", err)
+	}
+	if len(policyErr.Messages) != 3 {
+		t.Errorf("應該有 3 條錯誤訊息，got %v", policyErr.Messages)
+	}
+}
+
+// TestPolicy_ShortUserInfoIgnored 測試過短的名稱不檢查
+func TestPolicy_ShortUserInfoIgnored(t *testing.T) {
+	if err := Min(8).NotContainingUserInfo().Validate("lighthouse", User{Name: "Li", Email: "li@example.com"}); err != nil {
+		t.Errorf("過短的名稱不應造成誤判: %v", err)
+	}
+}
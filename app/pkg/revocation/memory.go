@@ -0,0 +1,82 @@
+package revocation
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore - 記憶體版撤銷清單
+// 適合單一節點部署與測試；多台機器時請改用 RedisStore
+type MemoryStore struct {
+	mu    sync.RWMutex
+	jtis  map[string]time.Time // jti → 過期時間
+	users map[uint]userCutoff  // userID → 撤銷時間點
+}
+
+type userCutoff struct {
+	at        time.Time
+	expiresAt time.Time
+}
+
+// NewMemoryStore - 建立記憶體版撤銷清單
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jtis:  make(map[string]time.Time),
+		users: make(map[uint]userCutoff),
+	}
+}
+
+// Revoke - 撤銷單一 Token
+func (s *MemoryStore) Revoke(jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpired(time.Now())
+	s.jtis[jti] = expiresAt
+	return nil
+}
+
+// IsRevoked - 檢查 Token 是否已被撤銷
+func (s *MemoryStore) IsRevoked(jti string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	expiresAt, ok := s.jtis[jti]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+// RevokeUser - 撤銷使用者在 at 之前簽發的所有 Token
+func (s *MemoryStore) RevokeUser(userID uint, at time.Time, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeExpired(time.Now())
+	s.users[userID] = userCutoff{at: at, expiresAt: at.Add(ttl)}
+	return nil
+}
+
+// UserRevokedAt - 取得使用者的撤銷時間點
+func (s *MemoryStore) UserRevokedAt(userID uint) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cutoff, ok := s.users[userID]
+	if !ok || time.Now().After(cutoff.expiresAt) {
+		return time.Time{}, nil
+	}
+	return cutoff.at, nil
+}
+
+// purgeExpired - 清除已過期的紀錄（呼叫前需持有寫鎖）
+func (s *MemoryStore) purgeExpired(now time.Time) {
+	for jti, expiresAt := range s.jtis {
+		if now.After(expiresAt) {
+			delete(s.jtis, jti)
+		}
+	}
+	for userID, cutoff := range s.users {
+		if now.After(cutoff.expiresAt) {
+			delete(s.users, userID)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore - Redis 版撤銷清單
+// 利用 Redis key 的 TTL 自動清除過期紀錄，多台機器共享同一份黑名單
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore - 建立 Redis 版撤銷清單（通常傳入 bootstrap.RedisClient）
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: "auth:revoked:",
+	}
+}
+
+// Revoke - 撤銷單一 Token
+func (s *RedisStore) Revoke(jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		// Token 已經過期，不需要再加入黑名單
+		return nil
+	}
+	return s.client.Set(context.Background(), s.jtiKey(jti), 1, ttl).Err()
+}
+
+// IsRevoked - 檢查 Token 是否已被撤銷
+func (s *RedisStore) IsRevoked(jti string) (bool, error) {
+	n, err := s.client.Exists(context.Background(), s.jtiKey(jti)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RevokeUser - 撤銷使用者在 at 之前簽發的所有 Token
+func (s *RedisStore) RevokeUser(userID uint, at time.Time, ttl time.Duration) error {
+	return s.client.Set(context.Background(), s.userKey(userID), at.UnixNano(), ttl).Err()
+}
+
+// UserRevokedAt - 取得使用者的撤銷時間點
+func (s *RedisStore) UserRevokedAt(userID uint) (time.Time, error) {
+	value, err := s.client.Get(context.Background(), s.userKey(userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	nanos, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("撤銷時間格式錯誤: %v", err)
+	}
+	return time.Unix(0, nanos), nil
+}
+
+func (s *RedisStore) jtiKey(jti string) string {
+	return s.prefix + "jti:" + jti
+}
+
+func (s *RedisStore) userKey(userID uint) string {
+	return s.prefix + "user:" + strconv.FormatUint(uint64(userID), 10)
+}
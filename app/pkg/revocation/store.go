@@ -0,0 +1,30 @@
+package revocation
+
+import "time"
+
+// Store - Token 撤銷清單（黑名單）介面
+//
+// 兩種撤銷方式：
+//   - 單一 Token：以 JWT 的 jti 為 key，保存到 Token 原本的過期時間即可
+//   - 使用者全部 Token：記錄撤銷時間點，在此之前簽發（iat）的 Token 全部失效
+type Store interface {
+	// Revoke 撤銷單一 Token，直到 expiresAt 後自動清除
+	Revoke(jti string, expiresAt time.Time) error
+	// IsRevoked 檢查 Token 是否已被撤銷
+	IsRevoked(jti string) (bool, error)
+	// RevokeUser 撤銷使用者在 at 之前簽發的所有 Token，紀錄保留 ttl
+	RevokeUser(userID uint, at time.Time, ttl time.Duration) error
+	// UserRevokedAt 取得使用者的撤銷時間點，沒有紀錄時回傳零值
+	UserRevokedAt(userID uint) (time.Time, error)
+}
+
+// IssuedBeforeCutoff - 判斷 Token 的簽發時間是否早於使用者撤銷時間點
+// JWT 的 iat 只精確到秒，因此撤銷時間點也截斷到秒比較，
+// 避免「全部登出」後同一秒內重新登入拿到的新 Token 被誤判為已撤銷；
+// 同一秒內稍早簽發的 Token 不會被這裡拒絕，需搭配撤銷各工作階段（sid）
+func IssuedBeforeCutoff(issuedAt, cutoff time.Time) bool {
+	if cutoff.IsZero() {
+		return false
+	}
+	return issuedAt.Before(cutoff.Truncate(time.Second))
+}
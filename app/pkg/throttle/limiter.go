@@ -0,0 +1,107 @@
+package throttle
+
+import (
+	"time"
+)
+
+// Policy - 失敗次數與鎖定規則
+type Policy struct {
+	MaxAttempts int           // 連續失敗幾次後開始鎖定
+	BaseLockout time.Duration // 第一次鎖定的時間，之後每多失敗一次加倍
+	MaxLockout  time.Duration // 鎖定時間上限
+	Decay       time.Duration // 沒有新的失敗多久之後（鎖定結束起算）計數歸零
+}
+
+// Limiter - 指數退避的失敗計數器
+//
+// 失敗次數達到 MaxAttempts 時鎖定 BaseLockout，之後每多失敗一次鎖定時間加倍
+// （BaseLockout × 2^(失敗次數 - MaxAttempts)），最多 MaxLockout。
+type Limiter struct {
+	store  Store
+	name   string
+	policy Policy
+}
+
+// NewLimiter - 建立 Limiter，name 用來區分不同用途的 key（例如 "login:email"）
+func NewLimiter(store Store, name string, policy Policy) *Limiter {
+	return &Limiter{store: store, name: name, policy: policy}
+}
+
+// RetryAfter - 目前是否鎖定中，回傳剩餘的鎖定時間（未鎖定時為 0）
+func (l *Limiter) RetryAfter(key string) (time.Duration, error) {
+	until, err := l.store.Get(l.lockKey(key))
+	if err != nil {
+		return 0, err
+	}
+	if until == 0 {
+		return 0, nil
+	}
+
+	remaining := time.Until(time.Unix(0, until))
+	if remaining <= 0 {
+		return 0, nil
+	}
+	return remaining, nil
+}
+
+// Attempts - 目前累計的失敗次數
+func (l *Limiter) Attempts(key string) (int, error) {
+	attempts, err := l.store.Get(l.attemptsKey(key))
+	return int(attempts), err
+}
+
+// Fail - 記錄一次失敗，回傳累計次數與這次觸發的鎖定時間（未鎖定時為 0）
+func (l *Limiter) Fail(key string) (int, time.Duration, error) {
+	attempts, err := l.store.Increment(l.attemptsKey(key), l.policy.Decay)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lockout := l.lockoutFor(int(attempts))
+	if lockout == 0 {
+		return int(attempts), 0, nil
+	}
+
+	until := time.Now().Add(lockout)
+	if err := l.store.Set(l.lockKey(key), until.UnixNano(), lockout); err != nil {
+		return int(attempts), 0, err
+	}
+	// 計數在鎖定期間不能歸零，否則解鎖後又從第一次鎖定開始算
+	if err := l.store.Expire(l.attemptsKey(key), lockout+l.policy.Decay); err != nil {
+		return int(attempts), 0, err
+	}
+
+	return int(attempts), lockout, nil
+}
+
+// Clear - 清除失敗次數與鎖定（登入成功、管理員解鎖）
+func (l *Limiter) Clear(key string) error {
+	return l.store.Delete(l.attemptsKey(key), l.lockKey(key))
+}
+
+// lockoutFor - 依失敗次數計算鎖定時間
+func (l *Limiter) lockoutFor(attempts int) time.Duration {
+	if l.policy.MaxAttempts <= 0 || attempts < l.policy.MaxAttempts {
+		return 0
+	}
+
+	lockout := l.policy.BaseLockout
+	for i := l.policy.MaxAttempts; i < attempts; i++ {
+		lockout *= 2
+		if lockout >= l.policy.MaxLockout {
+			return l.policy.MaxLockout
+		}
+	}
+	if lockout > l.policy.MaxLockout {
+		return l.policy.MaxLockout
+	}
+	return lockout
+}
+
+func (l *Limiter) attemptsKey(key string) string {
+	return l.name + ":attempts:" + key
+}
+
+func (l *Limiter) lockKey(key string) string {
+	return l.name + ":lock:" + key
+}
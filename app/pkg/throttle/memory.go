@@ -0,0 +1,93 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore - 記憶體版計數器
+// 適合單一節點部署與測試；多台機器時請改用 RedisStore
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// NewMemoryStore - 建立記憶體版計數器
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Increment - 實作 Store 介面
+func (s *MemoryStore) Increment(key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.purgeExpired(now)
+
+	entry := s.entries[key]
+	entry.value++
+	entry.expiresAt = now.Add(ttl)
+	s.entries[key] = entry
+	return entry.value, nil
+}
+
+// Get - 實作 Store 介面
+func (s *MemoryStore) Get(key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		return 0, nil
+	}
+	return entry.value, nil
+}
+
+// Set - 實作 Store 介面
+func (s *MemoryStore) Set(key string, value int64, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{value: value, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Expire - 實作 Store 介面
+func (s *MemoryStore) Expire(key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok {
+		entry.expiresAt = time.Now().Add(ttl)
+		s.entries[key] = entry
+	}
+	return nil
+}
+
+// Delete - 實作 Store 介面
+func (s *MemoryStore) Delete(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// purgeExpired - 清除已過期的紀錄（呼叫前需持有鎖）
+func (s *MemoryStore) purgeExpired(now time.Time) {
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore - Redis 版計數器，多台機器共享同一份登入失敗紀錄
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore - 建立 Redis 版計數器（通常傳入 bootstrap.RedisClient）
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{
+		client: client,
+		prefix: "throttle:",
+	}
+}
+
+// Increment - 實作 Store 介面（INCR + EXPIRE 在同一個 transaction 中執行）
+func (s *RedisStore) Increment(key string, ttl time.Duration) (int64, error) {
+	ctx := context.Background()
+
+	var incr *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, s.prefix+key)
+		pipe.Expire(ctx, s.prefix+key, ttl)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// Get - 實作 Store 介面
+func (s *RedisStore) Get(key string) (int64, error) {
+	value, err := s.client.Get(context.Background(), s.prefix+key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return value, err
+}
+
+// Set - 實作 Store 介面
+func (s *RedisStore) Set(key string, value int64, ttl time.Duration) error {
+	return s.client.Set(context.Background(), s.prefix+key, value, ttl).Err()
+}
+
+// Expire - 實作 Store 介面
+func (s *RedisStore) Expire(key string, ttl time.Duration) error {
+	return s.client.Expire(context.Background(), s.prefix+key, ttl).Err()
+}
+
+// Delete - 實作 Store 介面
+func (s *RedisStore) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.prefix + key
+	}
+	return s.client.Del(context.Background(), prefixed...).Err()
+}
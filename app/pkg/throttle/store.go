@@ -0,0 +1,18 @@
+package throttle
+
+import "time"
+
+// Store - 計數器儲存介面（登入失敗次數、鎖定時間）
+// 目前提供 MemoryStore 與 RedisStore 兩種實作；所有 key 都會在 TTL 到期後自動清除
+type Store interface {
+	// Increment - 計數加一並重設 TTL，回傳加一後的值
+	Increment(key string, ttl time.Duration) (int64, error)
+	// Get - 取得目前的值，不存在時回傳 0
+	Get(key string) (int64, error)
+	// Set - 設定值與 TTL
+	Set(key string, value int64, ttl time.Duration) error
+	// Expire - 重設 TTL（key 不存在時不做任何事）
+	Expire(key string, ttl time.Duration) error
+	// Delete - 刪除 key
+	Delete(keys ...string) error
+}
@@ -0,0 +1,73 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret - RFC 6238 附錄 B 的 SHA-1 測試金鑰 "12345678901234567890"
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// TestCode_RFC6238 使用 RFC 6238 的測試向量（取後 6 位）
+func TestCode_RFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code() 發生錯誤: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(T=%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+// TestValidate 測試時鐘誤差容許範圍
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() 發生錯誤: %v", err)
+	}
+
+	now := time.Now()
+	current := Step(now)
+	previous, _ := Code(secret, current-1)
+	tooOld, _ := Code(secret, current-2)
+
+	if step, ok := Validate(secret, previous, now, 1); !ok || step != current-1 {
+		t.Errorf("前一個時間區間的驗證碼應該有效，got step=%d ok=%v", step, ok)
+	}
+	if _, ok := Validate(secret, tooOld, now, 1); ok {
+		t.Error("超出容許範圍的驗證碼應該無效")
+	}
+	if _, ok := Validate(secret, "12345", now, 1); ok {
+		t.Error("位數不符的驗證碼應該無效")
+	}
+}
+
+// TestURI 測試 otpauth URI 格式
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("My API", "user@example.com", "JBSWY3DPEHPK3PXP"))
+	if err != nil {
+		t.Fatalf("URI 格式錯誤: %v", err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/My API:user@example.com" {
+		t.Errorf("URI 不符: %s", uri)
+	}
+	if uri.Query().Get("secret") != "JBSWY3DPEHPK3PXP" || uri.Query().Get("issuer") != "My API" {
+		t.Errorf("URI 參數不符: %s", uri.RawQuery)
+	}
+}
@@ -0,0 +1,133 @@
+package policies
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Actor - 執行操作的使用者（由 AuthMiddleware 寫入 Context 的資訊組成）
+type Actor struct {
+	ID          uint
+	Roles       []string
+	Permissions []string
+}
+
+// HasPermission - 是否具備指定權限
+func (a *Actor) HasPermission(permission string) bool {
+	if a == nil {
+		return false
+	}
+	for _, p := range a.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole - 是否擁有指定角色
+func (a *Actor) HasRole(role string) bool {
+	if a == nil {
+		return false
+	}
+	for _, r := range a.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ActorFromContext - 從 Gin Context 取得目前使用者（未登入時回傳 nil）
+func ActorFromContext(c *gin.Context) *Actor {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		return nil
+	}
+	id, ok := userID.(uint)
+	if !ok {
+		return nil
+	}
+
+	actor := &Actor{ID: id}
+	if roles, ok := c.Get("roles"); ok {
+		actor.Roles, _ = roles.([]string)
+	}
+	if permissions, ok := c.Get("permissions"); ok {
+		actor.Permissions, _ = permissions.([]string)
+	}
+	return actor
+}
+
+// Ability - 判斷使用者能否對資源執行某個動作
+type Ability[T any] func(actor *Actor, resource T) bool
+
+// Policy - 某種資源的授權規則（類似 Laravel 的 Policy 類別）
+// Abilities 回傳 動作名稱 → 判斷函式，例如 "update" → PostPolicy.Update
+type Policy[T any] interface {
+	Abilities() map[string]Ability[T]
+}
+
+// Gate - 授權閘道（類似 Laravel 的 Gate facade）
+// 依資源的型別找到對應的 Policy，未註冊的資源或動作一律拒絕
+type Gate struct {
+	mu       sync.RWMutex
+	policies map[reflect.Type]map[string]func(actor *Actor, resource any) bool
+}
+
+// NewGate - 建立新的 Gate 實例
+func NewGate() *Gate {
+	return &Gate{
+		policies: make(map[reflect.Type]map[string]func(actor *Actor, resource any) bool),
+	}
+}
+
+// Register - 註冊資源型別 T 的 Policy
+//
+// 使用方式：
+//
+//	policies.Register[*models.Post](gate, &policies.PostPolicy{})
+func Register[T any](g *Gate, policy Policy[T]) {
+	abilities := make(map[string]func(actor *Actor, resource any) bool)
+	for name, ability := range policy.Abilities() {
+		abilities[name] = func(actor *Actor, resource any) bool {
+			typed, ok := resource.(T)
+			if !ok {
+				return false
+			}
+			return ability(actor, typed)
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.policies[reflect.TypeOf((*T)(nil)).Elem()] = abilities
+}
+
+// Allows - 使用者能否對資源執行指定動作
+// 未登入（actor 為 nil）時一律拒絕
+func (g *Gate) Allows(actor *Actor, ability string, resource any) bool {
+	if actor == nil || resource == nil {
+		return false
+	}
+
+	g.mu.RLock()
+	abilities, ok := g.policies[reflect.TypeOf(resource)]
+	g.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	check, ok := abilities[ability]
+	if !ok {
+		return false
+	}
+	return check(actor, resource)
+}
+
+// Denies - Allows 的反向
+func (g *Gate) Denies(actor *Actor, ability string, resource any) bool {
+	return !g.Allows(actor, ability, resource)
+}
@@ -0,0 +1,86 @@
+package policies
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"my-api/app/models"
+)
+
+// newTestGate 建立已註冊 PostPolicy 的 Gate
+func newTestGate() *Gate {
+	gate := NewGate()
+	Register[*models.Post](gate, &PostPolicy{})
+	return gate
+}
+
+// TestPostPolicy 測試文章的擁有者檢查
+func TestPostPolicy(t *testing.T) {
+	gate := newTestGate()
+
+	post := &models.Post{UserID: 1}
+	owner := &Actor{ID: 1}
+	other := &Actor{ID: 2}
+	admin := &Actor{ID: 3, Roles: []string{"admin"}, Permissions: []string{PermissionManagePosts}}
+
+	tests := []struct {
+		name    string
+		actor   *Actor
+		ability string
+		want    bool
+	}{
+		{name: "作者可以修改", actor: owner, ability: "update", want: true},
+		{name: "作者可以刪除", actor: owner, ability: "delete", want: true},
+		{name: "其他人不能修改", actor: other, ability: "update", want: false},
+		{name: "其他人不能刪除", actor: other, ability: "delete", want: false},
+		{name: "其他人可以檢視", actor: other, ability: "view", want: true},
+		{name: "posts.manage 可以修改", actor: admin, ability: "update", want: true},
+		{name: "posts.manage 可以刪除", actor: admin, ability: "delete", want: true},
+		{name: "未登入一律拒絕", actor: nil, ability: "view", want: false},
+		{name: "未定義的動作一律拒絕", actor: owner, ability: "publish", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gate.Allows(tt.actor, tt.ability, post); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.ability, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGate_UnregisteredResource 測試未註冊 Policy 的資源一律拒絕
+func TestGate_UnregisteredResource(t *testing.T) {
+	gate := newTestGate()
+	actor := &Actor{ID: 1}
+
+	if gate.Allows(actor, "update", &models.User{}) {
+		t.Error("未註冊 Policy 的資源應該拒絕")
+	}
+	if gate.Allows(actor, "update", models.Post{UserID: 1}) {
+		t.Error("型別不符（非指標）應該拒絕")
+	}
+}
+
+// TestActorFromContext 測試從 Context 組出 Actor
+func TestActorFromContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	if actor := ActorFromContext(c); actor != nil {
+		t.Fatalf("未登入應回傳 nil，got %+v", actor)
+	}
+
+	c.Set("user_id", uint(7))
+	c.Set("roles", []string{"user"})
+	c.Set("permissions", []string{"posts.manage"})
+
+	actor := ActorFromContext(c)
+	if actor == nil || actor.ID != 7 {
+		t.Fatalf("Actor 不符，got %+v", actor)
+	}
+	if !actor.HasRole("user") || !actor.HasPermission("posts.manage") {
+		t.Errorf("角色或權限不符，got %+v", actor)
+	}
+}
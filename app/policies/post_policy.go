@@ -0,0 +1,46 @@
+package policies
+
+import "my-api/app/models"
+
+// PermissionManagePosts - 可管理所有人文章的權限（管理員）
+const PermissionManagePosts = "posts.manage"
+
+// PostPolicy - 文章授權規則
+type PostPolicy struct{}
+
+// Abilities - 實作 Policy 介面
+func (p *PostPolicy) Abilities() map[string]Ability[*models.Post] {
+	return map[string]Ability[*models.Post]{
+		"view":   p.View,
+		"create": p.Create,
+		"update": p.Update,
+		"delete": p.Delete,
+	}
+}
+
+// View - 登入的使用者都可以檢視文章
+func (p *PostPolicy) View(actor *Actor, post *models.Post) bool {
+	return true
+}
+
+// Create - 登入的使用者都可以發表文章（作者固定為自己）
+func (p *PostPolicy) Create(actor *Actor, post *models.Post) bool {
+	return true
+}
+
+// Update - 只有作者或具備 posts.manage 權限者可以修改
+func (p *PostPolicy) Update(actor *Actor, post *models.Post) bool {
+	return p.ownsOrManages(actor, post)
+}
+
+// Delete - 只有作者或具備 posts.manage 權限者可以刪除
+func (p *PostPolicy) Delete(actor *Actor, post *models.Post) bool {
+	return p.ownsOrManages(actor, post)
+}
+
+func (p *PostPolicy) ownsOrManages(actor *Actor, post *models.Post) bool {
+	if post == nil {
+		return false
+	}
+	return post.UserID == actor.ID || actor.HasPermission(PermissionManagePosts)
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"my-api/app/models"
+)
+
+// IdentityRepository - 外部身分（OpenID Connect）資料存取層介面
+type IdentityRepository interface {
+	Create(identity *models.Identity) error
+	FindByProviderSubject(provider, subject string) (*models.Identity, error)
+	Update(identity *models.Identity) error
+	FindByUser(userID uint) ([]models.Identity, error)
+	DeleteByUser(userID uint) error
+}
+
+// identityRepository - 實作 IdentityRepository 介面
+type identityRepository struct {
+	db *gorm.DB
+}
+
+// NewIdentityRepository - 建立新的 IdentityRepository 實例
+func NewIdentityRepository(db *gorm.DB) IdentityRepository {
+	return &identityRepository{db: db}
+}
+
+// Create - 新增外部身分
+func (r *identityRepository) Create(identity *models.Identity) error {
+	return r.db.Create(identity).Error
+}
+
+// FindByProviderSubject - 以 IdP 名稱與 sub 查詢
+func (r *identityRepository) FindByProviderSubject(provider, subject string) (*models.Identity, error) {
+	var identity models.Identity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// Update - 更新外部身分
+func (r *identityRepository) Update(identity *models.Identity) error {
+	return r.db.Save(identity).Error
+}
+
+// FindByUser - 取得使用者連結的所有外部身分
+func (r *identityRepository) FindByUser(userID uint) ([]models.Identity, error) {
+	var identities []models.Identity
+	err := r.db.Where("user_id = ?", userID).Order("id").Find(&identities).Error
+	return identities, err
+}
+
+// DeleteByUser - 刪除使用者連結的所有外部身分（刪除帳號時使用）
+func (r *identityRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.Identity{}).Error
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"my-api/app/models"
+)
+
+// LoginHistoryRepository - 登入紀錄資料存取層介面
+type LoginHistoryRepository interface {
+	Create(history *models.LoginHistory) error
+	FindRecentByUser(userID uint, limit int) ([]models.LoginHistory, error)
+	FindByUser(userID uint) ([]models.LoginHistory, error)
+	DeleteByUser(userID uint) error
+}
+
+// loginHistoryRepository - 實作 LoginHistoryRepository 介面
+type loginHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginHistoryRepository - 建立新的 LoginHistoryRepository 實例
+func NewLoginHistoryRepository(db *gorm.DB) LoginHistoryRepository {
+	return &loginHistoryRepository{db: db}
+}
+
+// Create - 新增登入紀錄
+func (r *loginHistoryRepository) Create(history *models.LoginHistory) error {
+	return r.db.Create(history).Error
+}
+
+// FindRecentByUser - 取得使用者最近的登入紀錄（新的在前）
+func (r *loginHistoryRepository) FindRecentByUser(userID uint, limit int) ([]models.LoginHistory, error) {
+	var histories []models.LoginHistory
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&histories).Error
+	return histories, err
+}
+
+// FindByUser - 取得使用者所有的登入紀錄（新的在前）
+func (r *loginHistoryRepository) FindByUser(userID uint) ([]models.LoginHistory, error) {
+	var histories []models.LoginHistory
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&histories).Error
+	return histories, err
+}
+
+// DeleteByUser - 刪除使用者的所有登入紀錄（刪除帳號時使用）
+func (r *loginHistoryRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.LoginHistory{}).Error
+}
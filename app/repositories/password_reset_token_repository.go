@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"my-api/app/models"
+)
+
+// PasswordResetTokenRepository - 重設密碼 Token 資料存取層介面
+type PasswordResetTokenRepository interface {
+	Create(token *models.PasswordResetToken) error
+	FindByHash(hash string) (*models.PasswordResetToken, error)
+	MarkUsed(id uint) (bool, error)
+	DeleteForUser(userID uint) error
+}
+
+// passwordResetTokenRepository - 實作 PasswordResetTokenRepository 介面
+type passwordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetTokenRepository - 建立新的 PasswordResetTokenRepository 實例
+func NewPasswordResetTokenRepository(db *gorm.DB) PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{db: db}
+}
+
+// Create - 新增重設密碼 Token
+func (r *passwordResetTokenRepository) Create(token *models.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByHash - 根據雜湊值查詢重設密碼 Token
+func (r *passwordResetTokenRepository) FindByHash(hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed - 將 Token 標記為已使用
+// 使用條件式 UPDATE 確保同一個 Token 只會成功使用一次
+func (r *passwordResetTokenRepository) MarkUsed(id uint) (bool, error) {
+	result := r.db.Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteForUser - 刪除使用者的所有重設密碼 Token（重新申請時舊連結失效）
+func (r *passwordResetTokenRepository) DeleteForUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.PasswordResetToken{}).Error
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"my-api/app/models"
+)
+
+// PersonalAccessTokenRepository - 個人存取 Token 資料存取層介面
+type PersonalAccessTokenRepository interface {
+	Create(token *models.PersonalAccessToken) error
+	FindByHash(hash string) (*models.PersonalAccessToken, error)
+	FindByUser(userID uint) ([]models.PersonalAccessToken, error)
+	Delete(userID, id uint) (bool, error)
+	TouchLastUsed(id uint, usedAt time.Time, interval time.Duration) error
+	DeleteByUser(userID uint) error
+}
+
+// personalAccessTokenRepository - 實作 PersonalAccessTokenRepository 介面
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalAccessTokenRepository - 建立新的 PersonalAccessTokenRepository 實例
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+// Create - 新增個人存取 Token
+func (r *personalAccessTokenRepository) Create(token *models.PersonalAccessToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByHash - 根據雜湊值查詢 Token（含使用者與角色，驗證時需要）
+func (r *personalAccessTokenRepository) FindByHash(hash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	err := r.db.Preload("User.Roles").Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByUser - 取得使用者的所有 Token（新建立的在前）
+func (r *personalAccessTokenRepository) FindByUser(userID uint) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// Delete - 刪除使用者自己的 Token，回傳是否有刪除
+func (r *personalAccessTokenRepository) Delete(userID, id uint) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.PersonalAccessToken{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// TouchLastUsed - 更新最後使用時間
+// 距離上次更新不到 interval 時不寫入，避免每個 API 請求都更新資料庫
+func (r *personalAccessTokenRepository) TouchLastUsed(id uint, usedAt time.Time, interval time.Duration) error {
+	return r.db.Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-interval)).
+		UpdateColumn("last_used_at", usedAt).Error
+}
+
+// DeleteByUser - 刪除使用者的所有 Token（刪除帳號時使用）
+func (r *personalAccessTokenRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.PersonalAccessToken{}).Error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"my-api/app/models"
 )
 
@@ -13,6 +14,8 @@ type PostRepository interface {
 	Update(post *models.Post) error
 	Delete(id uint) error
 	FindByUserID(userID uint) ([]models.Post, error)
+	ReassignUser(fromUserID, toUserID uint) error
+	DeleteByUser(userID uint) error
 }
 
 // postRepository - 實作 PostRepository 介面
@@ -47,9 +50,9 @@ func (r *postRepository) FindByID(id uint) (*models.Post, error) {
 	return &post, nil
 }
 
-// Update - 更新文章
+// Update - 更新文章（不連帶更新已預載的作者）
 func (r *postRepository) Update(post *models.Post) error {
-	return r.db.Save(post).Error
+	return r.db.Omit(clause.Associations).Save(post).Error
 }
 
 // Delete - 刪除文章（軟刪除）
@@ -63,3 +66,15 @@ func (r *postRepository) FindByUserID(userID uint) ([]models.Post, error) {
 	err := r.db.Where("user_id = ?", userID).Preload("User").Find(&posts).Error
 	return posts, err
 }
+
+// ReassignUser - 把使用者的所有文章（含已軟刪除的）轉移給另一個使用者
+func (r *postRepository) ReassignUser(fromUserID, toUserID uint) error {
+	return r.db.Unscoped().Model(&models.Post{}).
+		Where("user_id = ?", fromUserID).
+		Update("user_id", toUserID).Error
+}
+
+// DeleteByUser - 永久刪除使用者的所有文章（含已軟刪除的，刪除帳號時使用）
+func (r *postRepository) DeleteByUser(userID uint) error {
+	return r.db.Unscoped().Where("user_id = ?", userID).Delete(&models.Post{}).Error
+}
@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"my-api/app/models"
+)
+
+// RoleRepository - 角色與權限資料存取層介面
+type RoleRepository interface {
+	Create(role *models.Role) error
+	FindAll() ([]models.Role, error)
+	FindByID(id uint) (*models.Role, error)
+	FindByName(name string) (*models.Role, error)
+	FindByNames(names []string) ([]models.Role, error)
+	Update(role *models.Role) error
+	Delete(id uint) error
+	SyncPermissions(role *models.Role, permissions []models.Permission) error
+
+	FindAllPermissions() ([]models.Permission, error)
+	FindPermissionsByNames(names []string) ([]models.Permission, error)
+	PermissionNamesForRoles(roleNames []string) ([]string, error)
+
+	RoleNamesForUser(userID uint) ([]string, error)
+	SyncUserRoles(userID uint, roles []models.Role) error
+	CountUsersWithRole(roleName string) (int64, error)
+}
+
+// roleRepository - 實作 RoleRepository 介面
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository - 建立新的 RoleRepository 實例
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// Create - 新增角色
+func (r *roleRepository) Create(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+// FindAll - 取得所有角色（含權限）
+func (r *roleRepository) FindAll() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Preload("Permissions").Order("id").Find(&roles).Error
+	return roles, err
+}
+
+// FindByID - 根據 ID 查詢角色（含權限）
+func (r *roleRepository) FindByID(id uint) (*models.Role, error) {
+	var role models.Role
+	err := r.db.Preload("Permissions").First(&role, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindByName - 根據名稱查詢角色
+func (r *roleRepository) FindByName(name string) (*models.Role, error) {
+	var role models.Role
+	err := r.db.Where("name = ?", name).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindByNames - 根據名稱批次查詢角色
+func (r *roleRepository) FindByNames(names []string) ([]models.Role, error) {
+	var roles []models.Role
+	if len(names) == 0 {
+		return roles, nil
+	}
+	err := r.db.Where("name IN ?", names).Find(&roles).Error
+	return roles, err
+}
+
+// Update - 更新角色
+func (r *roleRepository) Update(role *models.Role) error {
+	return r.db.Omit("Permissions").Save(role).Error
+}
+
+// Delete - 刪除角色（關聯表由外鍵 ON DELETE CASCADE 清除）
+func (r *roleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Role{}, id).Error
+}
+
+// SyncPermissions - 以傳入的權限取代角色目前的權限
+func (r *roleRepository) SyncPermissions(role *models.Role, permissions []models.Permission) error {
+	if len(permissions) == 0 {
+		return r.db.Model(role).Association("Permissions").Clear()
+	}
+	return r.db.Model(role).Association("Permissions").Replace(permissions)
+}
+
+// FindAllPermissions - 取得所有權限
+func (r *roleRepository) FindAllPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := r.db.Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+// FindPermissionsByNames - 根據名稱批次查詢權限
+func (r *roleRepository) FindPermissionsByNames(names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	err := r.db.Where("name IN ?", names).Find(&permissions).Error
+	return permissions, err
+}
+
+// PermissionNamesForRoles - 取得多個角色合併後的權限名稱
+func (r *roleRepository) PermissionNamesForRoles(roleNames []string) ([]string, error) {
+	var names []string
+	if len(roleNames) == 0 {
+		return names, nil
+	}
+	err := r.db.Table("permissions").
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.name IN ?", roleNames).
+		Pluck("permissions.name", &names).Error
+	return names, err
+}
+
+// RoleNamesForUser - 取得使用者的角色名稱
+func (r *roleRepository) RoleNamesForUser(userID uint) ([]string, error) {
+	var names []string
+	err := r.db.Table("roles").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Pluck("roles.name", &names).Error
+	return names, err
+}
+
+// SyncUserRoles - 以傳入的角色取代使用者目前的角色
+func (r *roleRepository) SyncUserRoles(userID uint, roles []models.Role) error {
+	user := &models.User{}
+	user.ID = userID
+	if len(roles) == 0 {
+		return r.db.Model(user).Association("Roles").Clear()
+	}
+	return r.db.Model(user).Association("Roles").Replace(roles)
+}
+
+// CountUsersWithRole - 計算擁有某角色的使用者數量
+func (r *roleRepository) CountUsersWithRole(roleName string) (int64, error) {
+	var count int64
+	err := r.db.Table("user_roles").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Joins("JOIN users ON users.id = user_roles.user_id").
+		Where("roles.name = ? AND users.deleted_at IS NULL", roleName).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"my-api/app/models"
+)
+
+// SessionRepository - 登入工作階段資料存取層介面
+type SessionRepository interface {
+	Create(session *models.Session) error
+	FindByID(userID, id uint) (*models.Session, error)
+	FindActiveByUser(userID uint) ([]models.Session, error)
+	FindByUser(userID uint) ([]models.Session, error)
+	Extend(familyID string, expiresAt time.Time) error
+	TouchLastSeen(familyID string, seenAt time.Time, interval time.Duration) error
+	Revoke(familyID string) error
+	RevokeAllForUser(userID uint) error
+	DeleteByUser(userID uint) error
+}
+
+// sessionRepository - 實作 SessionRepository 介面
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository - 建立新的 SessionRepository 實例
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+// Create - 新增工作階段
+func (r *sessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+// FindByID - 取得使用者自己的工作階段
+func (r *sessionRepository) FindByID(userID, id uint) (*models.Session, error) {
+	var session models.Session
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUser - 取得使用者所有未撤銷、未過期的工作階段（最近使用的在前）
+func (r *sessionRepository) FindActiveByUser(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// FindByUser - 取得使用者所有的工作階段（含已登出、已過期的，新的在前）
+func (r *sessionRepository) FindByUser(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+// Extend - 刷新 Token 時延長到期時間
+func (r *sessionRepository) Extend(familyID string, expiresAt time.Time) error {
+	now := time.Now()
+	return r.db.Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Updates(map[string]interface{}{"expires_at": expiresAt, "last_seen_at": now}).Error
+}
+
+// TouchLastSeen - 更新最後使用時間
+// 距離上次更新不到 interval 時不寫入，避免每個 API 請求都更新資料庫
+func (r *sessionRepository) TouchLastSeen(familyID string, seenAt time.Time, interval time.Duration) error {
+	return r.db.Model(&models.Session{}).
+		Where("family_id = ? AND last_seen_at < ?", familyID, seenAt.Add(-interval)).
+		UpdateColumn("last_seen_at", seenAt).Error
+}
+
+// Revoke - 撤銷工作階段
+func (r *sessionRepository) Revoke(familyID string) error {
+	return r.db.Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllForUser - 撤銷使用者的所有工作階段
+func (r *sessionRepository) RevokeAllForUser(userID uint) error {
+	return r.db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// DeleteByUser - 刪除使用者的所有工作階段（刪除帳號時使用）
+func (r *sessionRepository) DeleteByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.Session{}).Error
+}
@@ -1,7 +1,11 @@
 package repositories
 
 import (
+	"encoding/json"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"my-api/app/models"
 )
 
@@ -13,6 +17,9 @@ type UserRepository interface {
 	Update(user *models.User) error
 	Delete(id uint) error
 	FindByEmail(email string) (*models.User, error)
+	FindDueForDeletion(now time.Time) ([]models.User, error)
+	UpdateTwoFactorLastStep(id uint, step int64) (bool, error)
+	ReplaceRecoveryCodes(id uint, old, codes []string) (bool, error)
 }
 
 // userRepository - 實作 UserRepository 介面
@@ -30,26 +37,26 @@ func (r *userRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
 }
 
-// FindAll - 取得所有使用者
+// FindAll - 取得所有使用者（含角色）
 func (r *userRepository) FindAll() ([]models.User, error) {
 	var users []models.User
-	err := r.db.Find(&users).Error
+	err := r.db.Preload("Roles").Find(&users).Error
 	return users, err
 }
 
-// FindByID - 根據 ID 查詢使用者
+// FindByID - 根據 ID 查詢使用者（含角色）
 func (r *userRepository) FindByID(id uint) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, id).Error
+	err := r.db.Preload("Roles").First(&user, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-// Update - 更新使用者
+// Update - 更新使用者（不連帶寫入角色等關聯，關聯由各自的 Repository 管理）
 func (r *userRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	return r.db.Omit(clause.Associations).Save(user).Error
 }
 
 // Delete - 刪除使用者（軟刪除）
@@ -66,3 +73,44 @@ func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+// FindDueForDeletion - 取得寬限期已過、等待匿名化的使用者
+func (r *userRepository) FindDueForDeletion(now time.Time) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).Find(&users).Error
+	return users, err
+}
+
+// UpdateTwoFactorLastStep - 記錄最後一次使用的 TOTP 時間區間
+// 條件式更新：只有 step 比目前記錄的新時才更新，同時登入的請求只有一個會成功（回傳 true）
+func (r *userRepository) UpdateTwoFactorLastStep(id uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND two_factor_last_step < ?", id, step).
+		Update("two_factor_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes - 以 codes 取代復原碼
+// 條件式更新：只有目前的復原碼仍為 old 時才更新，同一個復原碼同時使用時只有一個會成功（回傳 true）
+func (r *userRepository) ReplaceRecoveryCodes(id uint, old, codes []string) (bool, error) {
+	// 與 serializer:json 寫入的格式相同
+	oldJSON, err := json.Marshal(old)
+	if err != nil {
+		return false, err
+	}
+	codesJSON, err := json.Marshal(codes)
+	if err != nil {
+		return false, err
+	}
+
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND two_factor_recovery_codes = ?", id, string(oldJSON)).
+		Update("two_factor_recovery_codes", string(codesJSON))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
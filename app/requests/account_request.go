@@ -0,0 +1,33 @@
+package requests
+
+import (
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportAccountRequest - 匯出個人資料的 query string
+// format 預設為 json，zip 會以附件下載（每類資料一個 JSON 檔）
+type ExportAccountRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json zip"`
+}
+
+// Validate - 驗證匯出個人資料請求
+func (r *ExportAccountRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindQuery(r)
+}
+
+// DeleteAccountRequest - 刪除帳號請求驗證
+// 輸入密碼時直接申請；沒有密碼（例如只用 OIDC 登入的帳號）時可以省略，改以 Email 確認
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// Validate - 驗證刪除帳號請求（可以沒有 body）
+func (r *DeleteAccountRequest) Validate(c *gin.Context) error {
+	if err := c.ShouldBindJSON(r); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
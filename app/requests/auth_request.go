@@ -3,10 +3,11 @@ package requests
 import "github.com/gin-gonic/gin"
 
 // RegisterRequest - 使用者註冊請求驗證
+// 密碼長度與強度由密碼規則（password.Default()）檢查，這裡只限制 bcrypt 的上限
 type RegisterRequest struct {
 	Name            string `json:"name" binding:"required,min=2,max=100"`
 	Email           string `json:"email" binding:"required,email"`
-	Password        string `json:"password" binding:"required,min=8,max=72"`
+	Password        string `json:"password" binding:"required,max=72"`
 	PasswordConfirm string `json:"password_confirm" binding:"required,eqfield=Password"`
 	Age             int    `json:"age" binding:"omitempty,min=0,max=150"`
 }
@@ -26,3 +27,73 @@ type LoginRequest struct {
 func (r *LoginRequest) Validate(c *gin.Context) error {
 	return c.ShouldBindJSON(r)
 }
+
+// RefreshTokenRequest - 刷新 Token 請求驗證
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Validate - 驗證刷新 Token 請求
+func (r *RefreshTokenRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
+
+// LogoutRequest - 登出請求（refresh_token 選填，提供時一併撤銷）
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Validate - 驗證登出請求（允許空 body）
+func (r *LogoutRequest) Validate(c *gin.Context) error {
+	if c.Request.ContentLength == 0 {
+		return nil
+	}
+	return c.ShouldBindJSON(r)
+}
+
+// LogoutAllRequest - 登出所有裝置請求（revoke_api_tokens 選填，true 時一併撤銷所有 API Token）
+type LogoutAllRequest struct {
+	RevokeAPITokens bool `json:"revoke_api_tokens"`
+}
+
+// Validate - 驗證登出所有裝置請求（允許空 body）
+func (r *LogoutAllRequest) Validate(c *gin.Context) error {
+	if c.Request.ContentLength == 0 {
+		return nil
+	}
+	return c.ShouldBindJSON(r)
+}
+
+// ForgotPasswordRequest - 忘記密碼請求驗證
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// Validate - 驗證忘記密碼請求
+func (r *ForgotPasswordRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
+
+// ResetPasswordRequest - 重設密碼請求驗證
+type ResetPasswordRequest struct {
+	Token           string `json:"token" binding:"required"`
+	Password        string `json:"password" binding:"required,max=72"`
+	PasswordConfirm string `json:"password_confirm" binding:"required,eqfield=Password"`
+}
+
+// Validate - 驗證重設密碼請求
+func (r *ResetPasswordRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
+
+// ChangePasswordRequest - 變更密碼請求驗證
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Password        string `json:"password" binding:"required,max=72"`
+	PasswordConfirm string `json:"password_confirm" binding:"required,eqfield=Password"`
+}
+
+// Validate - 驗證變更密碼請求
+func (r *ChangePasswordRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
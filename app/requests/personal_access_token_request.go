@@ -0,0 +1,19 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePersonalAccessTokenRequest - 建立個人存取 Token 請求驗證
+type CreatePersonalAccessTokenRequest struct {
+	Name      string     `json:"name" binding:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at" binding:"omitempty,gt"` // 選填，RFC 3339；不填表示不會過期
+}
+
+// Validate - 驗證建立個人存取 Token 請求
+func (r *CreatePersonalAccessTokenRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
@@ -0,0 +1,46 @@
+package requests
+
+import "github.com/gin-gonic/gin"
+
+// CreateRoleRequest - 建立角色請求驗證
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required,min=2,max=50"`
+	Description string   `json:"description" binding:"omitempty,max=255"`
+	Permissions []string `json:"permissions"` // 選填，權限名稱列表
+}
+
+// Validate - 驗證建立角色請求
+func (r *CreateRoleRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
+
+// UpdateRoleRequest - 更新角色請求驗證
+type UpdateRoleRequest struct {
+	Name        string `json:"name" binding:"omitempty,min=2,max=50"`
+	Description string `json:"description" binding:"omitempty,max=255"`
+}
+
+// Validate - 驗證更新角色請求
+func (r *UpdateRoleRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
+
+// SyncPermissionsRequest - 設定角色權限請求驗證（整批取代）
+type SyncPermissionsRequest struct {
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// Validate - 驗證設定角色權限請求
+func (r *SyncPermissionsRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
+
+// SyncRolesRequest - 設定使用者角色請求驗證（整批取代）
+type SyncRolesRequest struct {
+	Roles []string `json:"roles" binding:"required"`
+}
+
+// Validate - 驗證設定使用者角色請求
+func (r *SyncRolesRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
@@ -0,0 +1,17 @@
+package requests
+
+import "github.com/gin-gonic/gin"
+
+// SocialCallbackRequest - IdP 導回時的 query string
+// 使用者在 IdP 拒絕授權時只會帶 error（例如 access_denied），不會有 code
+type SocialCallbackRequest struct {
+	Code             string `form:"code" binding:"required_without=Error"`
+	State            string `form:"state" binding:"required"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// Validate - 驗證 IdP 回呼參數
+func (r *SocialCallbackRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindQuery(r)
+}
@@ -0,0 +1,36 @@
+package requests
+
+import "github.com/gin-gonic/gin"
+
+// TwoFactorCodeRequest - 確認啟用兩步驟驗證請求驗證
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// Validate - 驗證確認啟用兩步驟驗證請求
+func (r *TwoFactorCodeRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
+
+// TwoFactorPasswordRequest - 需要再次輸入密碼的兩步驟驗證操作（停用、重新產生復原碼）
+type TwoFactorPasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// Validate - 驗證密碼確認請求
+func (r *TwoFactorPasswordRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
+
+// TwoFactorChallengeRequest - 登入時的兩步驟驗證請求驗證
+// code（驗證器 App 的 6 位數驗證碼）與 recovery_code（復原碼）擇一
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode   string `json:"recovery_code" binding:"required_without=Code"`
+}
+
+// Validate - 驗證兩步驟驗證請求
+func (r *TwoFactorChallengeRequest) Validate(c *gin.Context) error {
+	return c.ShouldBindJSON(r)
+}
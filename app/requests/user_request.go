@@ -1,8 +1,11 @@
 package requests
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"my-api/app/pkg/password"
 )
 
 // CreateUserRequest - 建立使用者的請求驗證
@@ -57,9 +60,16 @@ func (r *UpdateUserRequest) Validate(c *gin.Context) error {
 //
 // e.Tag() 回傳驗證規則名稱，如 "required"、"email"、"min"
 // e.Param() 回傳規則參數，如 min=2 中的 "2"
+//
+// Service 回傳的密碼規則錯誤（*password.PolicyError）也會轉成 Password 欄位的錯誤訊息
 func FormatValidationError(err error) map[string]interface{} {
 	errors := make(map[string]interface{})
 
+	if policyErr := asPolicyError(err); policyErr != nil {
+		errors["Password"] = policyErr.Error()
+		return errors
+	}
+
 	// 型別斷言：將 error 轉換為 validator.ValidationErrors
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, e := range validationErrors {
@@ -81,3 +91,12 @@ func FormatValidationError(err error) map[string]interface{} {
 
 	return errors
 }
+
+// asPolicyError - 取出密碼規則錯誤（FormatValidationError 內的 errors 變數遮蔽了 errors 套件）
+func asPolicyError(err error) *password.PolicyError {
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		return policyErr
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package responses
+
+import (
+	"time"
+
+	"my-api/app/models"
+)
+
+// AccountExport - 個人資料匯出（GET /api/me/export）
+// 包含系統保存的所有與使用者相關的資料；密碼雜湊、TOTP 金鑰、Token 雜湊等憑證不匯出
+type AccountExport struct {
+	ExportedAt   time.Time                    `json:"exported_at"`
+	User         ExportedUser                 `json:"user"`
+	Posts        []ExportedPost               `json:"posts"`
+	Sessions     []models.Session             `json:"sessions"`
+	LoginHistory []models.LoginHistory        `json:"login_history"`
+	AccessTokens []models.PersonalAccessToken `json:"access_tokens"`
+	Identities   []models.Identity            `json:"identities"`
+}
+
+// ExportedUser - 匯出的使用者資料（models.User 中除了憑證以外的所有欄位）
+type ExportedUser struct {
+	ID                   uint       `json:"id"`
+	Name                 string     `json:"name"`
+	Email                string     `json:"email"`
+	EmailVerifiedAt      *time.Time `json:"email_verified_at"`
+	Age                  int        `json:"age"`
+	Roles                []string   `json:"roles"`
+	TwoFactorConfirmedAt *time.Time `json:"two_factor_confirmed_at"`
+	DeletionScheduledAt  *time.Time `json:"deletion_scheduled_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// NewExportedUser - 將 Model 轉換為匯出格式（需先 Preload Roles）
+func NewExportedUser(user *models.User) ExportedUser {
+	return ExportedUser{
+		ID:                   user.ID,
+		Name:                 user.Name,
+		Email:                user.Email,
+		EmailVerifiedAt:      user.EmailVerifiedAt,
+		Age:                  user.Age,
+		Roles:                user.RoleNames(),
+		TwoFactorConfirmedAt: user.TwoFactorConfirmedAt,
+		DeletionScheduledAt:  user.DeletionScheduledAt,
+		CreatedAt:            user.CreatedAt,
+		UpdatedAt:            user.UpdatedAt,
+	}
+}
+
+// ExportedPost - 匯出的文章
+type ExportedPost struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Content     string    `json:"content"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewExportedPost - 將 Model 轉換為匯出格式
+func NewExportedPost(post *models.Post) ExportedPost {
+	return ExportedPost{
+		ID:          post.ID,
+		Title:       post.Title,
+		Description: post.Description,
+		Content:     post.Content,
+		CreatedAt:   post.CreatedAt,
+		UpdatedAt:   post.UpdatedAt,
+	}
+}
+
+// AccountDeletionResponse - 申請刪除帳號的回應
+// 寬限期為 0 時帳號已立即刪除，ScheduledAt 為 nil；寄出確認信時尚未申請，ConfirmationSent 為 true
+type AccountDeletionResponse struct {
+	ScheduledAt      *time.Time `json:"deletion_scheduled_at"`
+	Deleted          bool       `json:"deleted"`
+	ConfirmationSent bool       `json:"confirmation_sent"`
+}
+
+// AccountDeletionConfirmationResponse - 開啟確認刪除帳號連結（GET）的回應
+// 只回傳連結中已簽章的參數，前端顯示確認畫面後以 POST 送出 ConfirmURL 才會申請
+type AccountDeletionConfirmationResponse struct {
+	UserID     uint   `json:"user_id"`
+	Hash       string `json:"hash"`
+	Expires    string `json:"expires"`
+	Signature  string `json:"signature"`
+	ConfirmURL string `json:"confirm_url"`
+}
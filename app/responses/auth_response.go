@@ -2,20 +2,26 @@ package responses
 
 import "my-api/app/models"
 
-// AuthResponse - 認證成功回應（登入/註冊後返回）
+// AuthResponse - 認證成功回應（登入/註冊/刷新後返回）
 type AuthResponse struct {
-	Token     string        `json:"token"`
-	TokenType string        `json:"token_type"`
-	ExpiresIn int           `json:"expires_in"` // 秒數
-	User      *UserResponse `json:"user"`
+	AccessToken      string        `json:"access_token"`
+	Token            string        `json:"token"` // Deprecated: 同 access_token，舊版用戶端使用的欄位名稱，下一版移除
+	TokenType        string        `json:"token_type"`
+	ExpiresIn        int           `json:"expires_in"` // Access Token 有效秒數
+	RefreshToken     string        `json:"refresh_token"`
+	RefreshExpiresIn int           `json:"refresh_expires_in"` // Refresh Token 有效秒數
+	User             *UserResponse `json:"user"`
 }
 
 // NewAuthResponse - 建立認證回應
-func NewAuthResponse(token string, expiresIn int, user *models.User) *AuthResponse {
+func NewAuthResponse(accessToken string, expiresIn int, refreshToken string, refreshExpiresIn int, user *models.User) *AuthResponse {
 	return &AuthResponse{
-		Token:     token,
-		TokenType: "Bearer",
-		ExpiresIn: expiresIn,
-		User:      NewUserResponse(user),
+		AccessToken:      accessToken,
+		Token:            accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        expiresIn,
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: refreshExpiresIn,
+		User:             NewUserResponse(user),
 	}
 }
@@ -0,0 +1,36 @@
+package responses
+
+import (
+	"time"
+
+	"my-api/app/models"
+)
+
+// PersonalAccessTokenResponse - 個人存取 Token 回應 DTO（不含 Token 本身）
+type PersonalAccessTokenResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewPersonalAccessTokenResponse - 將 Model 轉換為 Response DTO
+func NewPersonalAccessTokenResponse(token *models.PersonalAccessToken) *PersonalAccessTokenResponse {
+	return &PersonalAccessTokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.Scopes,
+		LastUsedAt: token.LastUsedAt,
+		ExpiresAt:  token.ExpiresAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+// NewAccessTokenResponse - 建立 Token 後的回應
+// PlainTextToken 只會在這裡出現一次，之後無法再取得
+type NewAccessTokenResponse struct {
+	PlainTextToken string                       `json:"plain_text_token"`
+	AccessToken    *PersonalAccessTokenResponse `json:"access_token"`
+}
@@ -0,0 +1,50 @@
+package responses
+
+import (
+	"time"
+
+	"my-api/app/models"
+)
+
+// RoleResponse - 角色回應 DTO
+type RoleResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewRoleResponse - 將 Model 轉換為 Response DTO
+func NewRoleResponse(role *models.Role) *RoleResponse {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		permissions = append(permissions, permission.Name)
+	}
+
+	return &RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+		UpdatedAt:   role.UpdatedAt,
+	}
+}
+
+// PermissionResponse - 權限回應 DTO
+type PermissionResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// NewPermissionResponse - 將 Model 轉換為 Response DTO
+func NewPermissionResponse(permission *models.Permission) *PermissionResponse {
+	return &PermissionResponse{
+		ID:          permission.ID,
+		Name:        permission.Name,
+		Description: permission.Description,
+	}
+}
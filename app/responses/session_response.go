@@ -0,0 +1,53 @@
+package responses
+
+import (
+	"time"
+
+	"my-api/app/models"
+)
+
+// SessionResponse - 登入工作階段（裝置）回應 DTO
+type SessionResponse struct {
+	ID         uint      `json:"id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"` // 是否為目前這個請求使用的工作階段
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewSessionResponse - 將 Model 轉換為 Response DTO
+func NewSessionResponse(session *models.Session, current bool) *SessionResponse {
+	return &SessionResponse{
+		ID:         session.ID,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		Current:    current,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		CreatedAt:  session.CreatedAt,
+	}
+}
+
+// LoginHistoryResponse - 登入紀錄回應 DTO
+type LoginHistoryResponse struct {
+	ID         uint      `json:"id"`
+	SessionID  *uint     `json:"session_id"`
+	Successful bool      `json:"successful"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewLoginHistoryResponse - 將 Model 轉換為 Response DTO
+func NewLoginHistoryResponse(history *models.LoginHistory) *LoginHistoryResponse {
+	return &LoginHistoryResponse{
+		ID:         history.ID,
+		SessionID:  history.SessionID,
+		Successful: history.Successful,
+		IPAddress:  history.IPAddress,
+		UserAgent:  history.UserAgent,
+		CreatedAt:  history.CreatedAt,
+	}
+}
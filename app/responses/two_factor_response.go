@@ -0,0 +1,23 @@
+package responses
+
+// TwoFactorSetupResponse - 開始設定兩步驟驗證的回應
+// 前端將 OTPAuthURI 轉成 QR Code，無法掃描時讓使用者手動輸入 Secret
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse - 復原碼（只在產生時回傳一次）
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginResponse - 登入回應
+// 未啟用兩步驟驗證時與 AuthResponse 相同；啟用時只回傳 challenge_token，
+// 需再呼叫 POST /api/two-factor/challenge 換取 Token
+type LoginResponse struct {
+	*AuthResponse
+	TwoFactorRequired  bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	ChallengeExpiresIn int    `json:"challenge_expires_in,omitempty"` // challenge_token 有效秒數
+}
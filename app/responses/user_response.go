@@ -1,29 +1,42 @@
 package responses
 
 import (
-	"time"
 	"my-api/app/models"
+	"time"
 )
 
 // UserResponse - 使用者回應 DTO（隱藏敏感資訊）
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Age       int       `json:"age"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                  uint       `json:"id"`
+	Name                string     `json:"name"`
+	Email               string     `json:"email"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at"`
+	TwoFactorEnabled    bool       `json:"two_factor_enabled"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	Age                 int        `json:"age"`
+	Roles               []string   `json:"roles,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
 }
 
 // NewUserResponse - 將 Model 轉換為 Response DTO
 func NewUserResponse(user *models.User) *UserResponse {
+	var roles []string
+	for _, role := range user.Roles {
+		roles = append(roles, role.Name)
+	}
+
 	return &UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Age:       user.Age,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:                  user.ID,
+		Name:                user.Name,
+		Email:               user.Email,
+		EmailVerifiedAt:     user.EmailVerifiedAt,
+		TwoFactorEnabled:    user.HasTwoFactorEnabled(),
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		Age:                 user.Age,
+		Roles:               roles,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
 	}
 }
 
@@ -0,0 +1,429 @@
+package services
+
+import (
+	"archive/zip"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/pkg/mail"
+	"my-api/app/repositories"
+	"my-api/app/responses"
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// 刪除帳號時文章的處理方式（ACCOUNT_DELETION_POST_POLICY）
+const (
+	PostPolicyDelete   = "delete"   // 永久刪除
+	PostPolicyReassign = "reassign" // 轉移給 ACCOUNT_DELETION_REASSIGN_TO
+)
+
+// deletedUserName - 匿名化後的使用者名稱
+const deletedUserName = "已刪除的使用者"
+
+var (
+	// ErrAccountDeletionPending - 已申請刪除帳號，尚在寬限期內
+	ErrAccountDeletionPending = errors.New("已申請刪除帳號")
+	// ErrNoAccountDeletion - 沒有可以取消的刪除帳號申請
+	ErrNoAccountDeletion = errors.New("沒有待處理的刪除帳號申請")
+	// ErrInvalidDeletionLink - 確認刪除帳號的連結與使用者不符
+	ErrInvalidDeletionLink = errors.New("確認刪除帳號的連結無效")
+	// ErrLastAdmin - 不可刪除最後一位管理員，否則系統中沒有人能管理角色
+	ErrLastAdmin = errors.New("不可刪除最後一位管理員的帳號")
+)
+
+// AccountService - 個人資料匯出與刪除帳號業務邏輯層介面
+//
+// 刪除流程：RequestDeletion 設定預定刪除時間（ACCOUNT_DELETION_GRACE_DAYS 天後），
+// 沒有輸入密碼時（例如只用 OIDC 登入、沒有設定密碼的帳號）改為寄出確認信，以 POST 送出連結後由 ConfirmDeletion 設定；
+// 寬限期內仍可登入並 CancelDeletion；期滿後由 PurgeDue（背景定期執行）呼叫 Erase：
+// 撤銷所有 Token、刪除登入裝置與紀錄、依設定刪除或轉移文章，最後匿名化並軟刪除使用者。
+// 使用者資料列保留（匿名化）是為了讓轉移前的外鍵與統計維持一致。
+type AccountService interface {
+	Export(userID uint) (*responses.AccountExport, error)
+	ExportArchive(userID uint, w io.Writer) error
+	RequestDeletion(userID uint, password string) (*responses.AccountDeletionResponse, error)
+	ConfirmDeletion(userID uint, hash string) (*responses.AccountDeletionResponse, error)
+	CancelDeletion(userID uint) error
+	Erase(userID uint) error
+	PurgeDue() (int, error)
+}
+
+// accountService - 實作 AccountService 介面
+type accountService struct {
+	userRepo     repositories.UserRepository
+	postRepo     repositories.PostRepository
+	roleRepo     repositories.RoleRepository
+	sessionRepo  repositories.SessionRepository
+	historyRepo  repositories.LoginHistoryRepository
+	identityRepo repositories.IdentityRepository
+	tokenRepo    repositories.PersonalAccessTokenRepository
+	authService  AuthService
+	mailer       mail.Mailer
+}
+
+// NewAccountService - 建立新的 AccountService 實例
+func NewAccountService(
+	userRepo repositories.UserRepository,
+	postRepo repositories.PostRepository,
+	roleRepo repositories.RoleRepository,
+	sessionRepo repositories.SessionRepository,
+	historyRepo repositories.LoginHistoryRepository,
+	identityRepo repositories.IdentityRepository,
+	tokenRepo repositories.PersonalAccessTokenRepository,
+	authService AuthService,
+	mailer mail.Mailer,
+) AccountService {
+	return &accountService{
+		userRepo:     userRepo,
+		postRepo:     postRepo,
+		roleRepo:     roleRepo,
+		sessionRepo:  sessionRepo,
+		historyRepo:  historyRepo,
+		identityRepo: identityRepo,
+		tokenRepo:    tokenRepo,
+		authService:  authService,
+		mailer:       mailer,
+	}
+}
+
+// Export - 匯出使用者的個人資料
+func (s *accountService) Export(userID uint) (*responses.AccountExport, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("使用者不存在")
+	}
+
+	posts, err := s.postRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.New("匯出個人資料失敗")
+	}
+	sessions, err := s.sessionRepo.FindByUser(userID)
+	if err != nil {
+		return nil, errors.New("匯出個人資料失敗")
+	}
+	histories, err := s.historyRepo.FindByUser(userID)
+	if err != nil {
+		return nil, errors.New("匯出個人資料失敗")
+	}
+	tokens, err := s.tokenRepo.FindByUser(userID)
+	if err != nil {
+		return nil, errors.New("匯出個人資料失敗")
+	}
+	identities, err := s.identityRepo.FindByUser(userID)
+	if err != nil {
+		return nil, errors.New("匯出個人資料失敗")
+	}
+
+	export := &responses.AccountExport{
+		ExportedAt:   time.Now(),
+		User:         responses.NewExportedUser(user),
+		Posts:        make([]responses.ExportedPost, 0, len(posts)),
+		Sessions:     nonNil(sessions),
+		LoginHistory: nonNil(histories),
+		AccessTokens: nonNil(tokens),
+		Identities:   nonNil(identities),
+	}
+	for i := range posts {
+		export.Posts = append(export.Posts, responses.NewExportedPost(&posts[i]))
+	}
+	return export, nil
+}
+
+// ExportArchive - 以 ZIP 格式匯出個人資料，每類資料一個 JSON 檔
+func (s *accountService) ExportArchive(userID uint, w io.Writer) error {
+	export, err := s.Export(userID)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"user.json", export.User},
+		{"posts.json", export.Posts},
+		{"sessions.json", export.Sessions},
+		{"login_history.json", export.LoginHistory},
+		{"access_tokens.json", export.AccessTokens},
+		{"identities.json", export.Identities},
+	}
+
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return errors.New("匯出個人資料失敗")
+		}
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return errors.New("匯出個人資料失敗")
+		}
+		if _, err := entry.Write(content); err != nil {
+			return errors.New("匯出個人資料失敗")
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return errors.New("匯出個人資料失敗")
+	}
+	return nil
+}
+
+// RequestDeletion - 申請刪除自己的帳號
+// 輸入密碼時立即申請；沒有輸入密碼時寄出確認信（ACCOUNT_DELETION_CONFIRM_MINUTES 分鐘內有效），點擊連結後才申請
+func (s *accountService) RequestDeletion(userID uint, password string) (*responses.AccountDeletionResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("使用者不存在")
+	}
+	if user.IsPendingDeletion() {
+		return nil, ErrAccountDeletionPending
+	}
+	if err := s.ensureNotLastAdmin(userID); err != nil {
+		return nil, err
+	}
+
+	if password == "" {
+		if err := s.sendDeletionConfirmation(user); err != nil {
+			return nil, err
+		}
+		return &responses.AccountDeletionResponse{ConfirmationSent: true}, nil
+	}
+	if !utils.CheckPassword(password, user.Password) {
+		return nil, ErrIncorrectPassword
+	}
+
+	return s.scheduleDeletion(user)
+}
+
+// ConfirmDeletion - 以 POST 送出確認信中的簽章連結後申請刪除帳號（簽章與期限由 middleware.ValidateSignature 檢查）
+// hash 為寄信當時 Email 的雜湊，變更 Email 後舊的連結失效
+func (s *accountService) ConfirmDeletion(userID uint, hash string) (*responses.AccountDeletionResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrInvalidDeletionLink
+	}
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(utils.HashToken(user.Email))) != 1 {
+		return nil, ErrInvalidDeletionLink
+	}
+	if user.IsPendingDeletion() {
+		return nil, ErrAccountDeletionPending
+	}
+	if err := s.ensureNotLastAdmin(userID); err != nil {
+		return nil, err
+	}
+
+	return s.scheduleDeletion(user)
+}
+
+// scheduleDeletion - 設定預定刪除時間並寄出通知信，寬限期為 0 時立即刪除
+func (s *accountService) scheduleDeletion(user *models.User) (*responses.AccountDeletionResponse, error) {
+	graceDays := config.GlobalConfig.Account.DeletionGraceDays
+	if graceDays <= 0 {
+		if err := s.Erase(user.ID); err != nil {
+			return nil, err
+		}
+		return &responses.AccountDeletionResponse{Deleted: true}, nil
+	}
+
+	scheduledAt := time.Now().AddDate(0, 0, graceDays)
+	user.DeletionScheduledAt = &scheduledAt
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("申請刪除帳號失敗")
+	}
+
+	// 通知信只是提醒，寄送失敗不影響申請
+	_ = s.sendDeletionNotice(user)
+
+	return &responses.AccountDeletionResponse{ScheduledAt: &scheduledAt}, nil
+}
+
+// CancelDeletion - 寬限期內取消刪除帳號
+func (s *accountService) CancelDeletion(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("使用者不存在")
+	}
+	if !user.IsPendingDeletion() {
+		return ErrNoAccountDeletion
+	}
+
+	user.DeletionScheduledAt = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("取消刪除帳號失敗")
+	}
+	return nil
+}
+
+// Erase - 刪除帳號：撤銷所有 Token、刪除關聯資料、處理文章，最後匿名化並軟刪除使用者
+// 每個步驟都可以重複執行，中途失敗時下一次 PurgeDue 會從頭再做一次
+func (s *accountService) Erase(userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("使用者不存在")
+	}
+	// 寬限期內其他管理員可能已被移除，刪除前再檢查一次
+	if err := s.ensureNotLastAdmin(userID); err != nil {
+		return err
+	}
+
+	if err := s.authService.LogoutAll(userID); err != nil {
+		return errors.New("撤銷 Token 失敗")
+	}
+	if err := s.tokenRepo.DeleteByUser(userID); err != nil {
+		return errors.New("刪除個人存取 Token 失敗")
+	}
+	if err := s.identityRepo.DeleteByUser(userID); err != nil {
+		return errors.New("刪除外部身分失敗")
+	}
+	if err := s.sessionRepo.DeleteByUser(userID); err != nil {
+		return errors.New("刪除登入裝置失敗")
+	}
+	if err := s.historyRepo.DeleteByUser(userID); err != nil {
+		return errors.New("刪除登入紀錄失敗")
+	}
+	if err := s.erasePosts(userID); err != nil {
+		return err
+	}
+	if err := s.roleRepo.SyncUserRoles(userID, nil); err != nil {
+		return errors.New("移除角色失敗")
+	}
+
+	hashedPassword, err := unusablePassword()
+	if err != nil {
+		return errors.New("刪除帳號失敗")
+	}
+	user.Name = deletedUserName
+	user.Email = fmt.Sprintf("deleted-%d@users.invalid", user.ID) // email 有唯一索引，以 ID 區分
+	user.EmailVerifiedAt = nil
+	user.Password = hashedPassword
+	user.Age = 0
+	user.TwoFactorSecret = ""
+	user.TwoFactorRecoveryCodes = nil
+	user.TwoFactorLastStep = 0
+	user.TwoFactorConfirmedAt = nil
+	user.DeletionScheduledAt = nil
+	user.Roles = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("匿名化使用者失敗")
+	}
+	if err := s.userRepo.Delete(userID); err != nil {
+		return errors.New("刪除帳號失敗")
+	}
+	return nil
+}
+
+// PurgeDue - 刪除寬限期已過的帳號，回傳成功刪除的數量
+// 個別帳號失敗時繼續處理其他帳號，錯誤一併回傳
+func (s *accountService) PurgeDue() (int, error) {
+	users, err := s.userRepo.FindDueForDeletion(time.Now())
+	if err != nil {
+		return 0, errors.New("查詢待刪除帳號失敗")
+	}
+
+	erased := 0
+	var errs []error
+	for _, user := range users {
+		if err := s.Erase(user.ID); err != nil {
+			errs = append(errs, fmt.Errorf("使用者 %d: %w", user.ID, err))
+			continue
+		}
+		erased++
+	}
+	return erased, errors.Join(errs...)
+}
+
+// erasePosts - 依 ACCOUNT_DELETION_POST_POLICY 處理使用者的文章
+// reassign 且沒有設定接收者時，文章保留在匿名化後的帳號；其他設定值一律刪除
+func (s *accountService) erasePosts(userID uint) error {
+	cfg := config.GlobalConfig.Account
+	if cfg.DeletionPostPolicy != PostPolicyReassign {
+		if err := s.postRepo.DeleteByUser(userID); err != nil {
+			return errors.New("刪除文章失敗")
+		}
+		return nil
+	}
+
+	if cfg.DeletionReassignTo == 0 {
+		return nil
+	}
+	if cfg.DeletionReassignTo == userID {
+		return errors.New("不能刪除接收文章的帳號")
+	}
+	if _, err := s.userRepo.FindByID(cfg.DeletionReassignTo); err != nil {
+		return errors.New("接收文章的使用者不存在")
+	}
+	if err := s.postRepo.ReassignUser(userID, cfg.DeletionReassignTo); err != nil {
+		return errors.New("轉移文章失敗")
+	}
+	return nil
+}
+
+// ensureNotLastAdmin - 使用者是唯一的管理員時回傳 ErrLastAdmin
+func (s *accountService) ensureNotLastAdmin(userID uint) error {
+	last, err := isLastAdmin(s.roleRepo, userID)
+	if err != nil {
+		return errors.New("檢查管理員失敗")
+	}
+	if last {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// sendDeletionConfirmation - 寄送確認刪除帳號的簽章連結
+func (s *accountService) sendDeletionConfirmation(user *models.User) error {
+	cfg := config.GlobalConfig
+	expiry := time.Duration(cfg.Account.DeletionConfirmMinutes) * time.Minute
+
+	link, err := utils.SignURL(
+		fmt.Sprintf("%s/api/me/deletion/confirm/%d/%s", cfg.App.URL, user.ID, utils.HashToken(user.Email)),
+		time.Now().Add(expiry),
+	)
+	if err != nil {
+		return errors.New("產生確認連結失敗")
+	}
+
+	msg := &mail.Message{
+		To:      user.Email,
+		Subject: "確認刪除帳號",
+		Body: fmt.Sprintf(
+			"%s 您好：\n\n我們收到了刪除帳號的申請，請在 %d 分鐘內開啟以下連結，並在確認頁面送出申請：\n\n%s\n\n只開啟連結不會刪除帳號；如果您沒有申請刪除帳號，請忽略這封信。\n",
+			user.Name, cfg.Account.DeletionConfirmMinutes, link,
+		),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		return errors.New("寄送確認信失敗")
+	}
+	return nil
+}
+
+// sendDeletionNotice - 寄送刪除帳號申請的通知信
+func (s *accountService) sendDeletionNotice(user *models.User) error {
+	msg := &mail.Message{
+		To:      user.Email,
+		Subject: "刪除帳號申請",
+		Body: fmt.Sprintf(
+			"%s 您好：\n\n我們收到了刪除帳號的申請，您的帳號與個人資料將於 %s 刪除，刪除後無法復原。\n\n在此之前登入並取消申請即可保留帳號。如果您沒有申請刪除帳號，請立即登入取消申請並變更密碼。\n",
+			user.Name, user.DeletionScheduledAt.Format("2006-01-02 15:04"),
+		),
+	}
+	return s.mailer.Send(msg)
+}
+
+// nonNil - 沒有資料時回傳空陣列，JSON 輸出為 [] 而不是 null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,426 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/requests"
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// ============================================================================
+// Mock Repository
+// ============================================================================
+
+// mockPostRepository 實作 PostRepository interface
+type mockPostRepository struct {
+	posts  map[uint]*models.Post
+	nextID uint
+}
+
+func newMockPostRepository() *mockPostRepository {
+	return &mockPostRepository{posts: make(map[uint]*models.Post), nextID: 1}
+}
+
+func (m *mockPostRepository) Create(post *models.Post) error {
+	post.ID = m.nextID
+	post.CreatedAt = time.Now()
+	m.nextID++
+	m.posts[post.ID] = post
+	return nil
+}
+
+func (m *mockPostRepository) FindAll() ([]models.Post, error) {
+	var posts []models.Post
+	for _, post := range m.posts {
+		posts = append(posts, *post)
+	}
+	return posts, nil
+}
+
+func (m *mockPostRepository) FindByID(id uint) (*models.Post, error) {
+	if post, ok := m.posts[id]; ok {
+		return post, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockPostRepository) Update(post *models.Post) error {
+	m.posts[post.ID] = post
+	return nil
+}
+
+func (m *mockPostRepository) Delete(id uint) error {
+	delete(m.posts, id)
+	return nil
+}
+
+func (m *mockPostRepository) FindByUserID(userID uint) ([]models.Post, error) {
+	var posts []models.Post
+	for _, post := range m.posts {
+		if post.UserID == userID {
+			posts = append(posts, *post)
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool { return posts[i].ID < posts[j].ID })
+	return posts, nil
+}
+
+func (m *mockPostRepository) ReassignUser(fromUserID, toUserID uint) error {
+	for _, post := range m.posts {
+		if post.UserID == fromUserID {
+			post.UserID = toUserID
+		}
+	}
+	return nil
+}
+
+func (m *mockPostRepository) DeleteByUser(userID uint) error {
+	for id, post := range m.posts {
+		if post.UserID == userID {
+			delete(m.posts, id)
+		}
+	}
+	return nil
+}
+
+// accountTestEnv 個人資料匯出與刪除帳號測試所需的依賴
+type accountTestEnv struct {
+	*testServices
+	service AccountService
+	auth    AuthService
+}
+
+// newAccountTestEnv 建立測試環境，寬限期 30 天、文章預設刪除
+func newAccountTestEnv(t *testing.T) *accountTestEnv {
+	t.Helper()
+	setupTestConfig()
+	config.GlobalConfig.Account = config.AccountConfig{DeletionGraceDays: 30, DeletionPostPolicy: PostPolicyDelete}
+
+	deps := newTestServices(newMockUserRepository())
+	return &accountTestEnv{testServices: deps, service: deps.accountService(), auth: deps.authService()}
+}
+
+// createUser 建立使用者並登入一次（產生工作階段與登入紀錄），回傳使用者與 Access Token
+func (env *accountTestEnv) createUser(t *testing.T, email string) (*models.User, string) {
+	t.Helper()
+	user := createTestUser(t, env.userRepo, &models.User{Name: "王小明", Email: email, Age: 30})
+
+	resp, err := env.auth.Login(&requests.LoginRequest{Email: email, Password: testPassword}, testClient)
+	if err != nil {
+		t.Fatalf("Login() 發生錯誤: %v", err)
+	}
+	return user, resp.AccessToken
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestAccountService_Export 測試匯出包含使用者的所有資料，但不包含密碼等憑證
+func TestAccountService_Export(t *testing.T) {
+	env := newAccountTestEnv(t)
+	user, _ := env.createUser(t, "export@example.com")
+	other, _ := env.createUser(t, "other@example.com")
+
+	env.postRepo.Create(&models.Post{Title: "我的文章", Content: "內容", UserID: user.ID})
+	env.postRepo.Create(&models.Post{Title: "別人的文章", Content: "內容", UserID: other.ID})
+	env.tokenRepo.Create(&models.PersonalAccessToken{UserID: user.ID, Name: "CI", TokenHash: "secret-hash"})
+	env.identityRepo.Create(&models.Identity{UserID: user.ID, Provider: "google", Subject: "sub-1", Email: user.Email})
+
+	export, err := env.service.Export(user.ID)
+	if err != nil {
+		t.Fatalf("Export() 發生錯誤: %v", err)
+	}
+	if export.User.Email != user.Email || export.User.Age != 30 {
+		t.Errorf("使用者資料不符: %+v", export.User)
+	}
+	if len(export.Posts) != 1 || export.Posts[0].Title != "我的文章" {
+		t.Errorf("只應匯出自己的文章: %+v", export.Posts)
+	}
+	if len(export.Sessions) != 1 || len(export.LoginHistory) != 1 || len(export.AccessTokens) != 1 || len(export.Identities) != 1 {
+		t.Errorf("匯出的資料筆數不符: %+v", export)
+	}
+
+	body, _ := json.Marshal(export)
+	for _, secret := range []string{user.Password, "secret-hash", "sub-1"} {
+		if strings.Contains(string(body), secret) {
+			t.Errorf("匯出的資料不應包含憑證 %q", secret)
+		}
+	}
+
+	// ZIP 每類資料一個檔案
+	var archive bytes.Buffer
+	if err := env.service.ExportArchive(user.ID, &archive); err != nil {
+		t.Fatalf("ExportArchive() 發生錯誤: %v", err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatalf("ZIP 格式錯誤: %v", err)
+	}
+	var names []string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+	}
+	if !containsString(names, "user.json") || !containsString(names, "posts.json") || len(names) != 6 {
+		t.Errorf("ZIP 檔案不符: %v", names)
+	}
+}
+
+// TestAccountService_RequestAndCancelDeletion 測試申請刪除需要密碼、寬限期內可以取消
+func TestAccountService_RequestAndCancelDeletion(t *testing.T) {
+	env := newAccountTestEnv(t)
+	user, accessToken := env.createUser(t, "delete@example.com")
+
+	if _, err := env.service.RequestDeletion(user.ID, "wrong-password"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("密碼錯誤應回傳 ErrIncorrectPassword，got %v", err)
+	}
+
+	result, err := env.service.RequestDeletion(user.ID, "password123")
+	if err != nil {
+		t.Fatalf("RequestDeletion() 發生錯誤: %v", err)
+	}
+	if result.Deleted || result.ScheduledAt == nil || time.Until(*result.ScheduledAt) < 29*24*time.Hour {
+		t.Errorf("應排定在 30 天後刪除: %+v", result)
+	}
+	if msg := env.mailer.Last(); msg == nil || msg.To != "delete@example.com" {
+		t.Error("應寄出刪除帳號通知信")
+	}
+	if _, err := env.service.RequestDeletion(user.ID, "password123"); !errors.Is(err, ErrAccountDeletionPending) {
+		t.Errorf("重複申請應回傳 ErrAccountDeletionPending，got %v", err)
+	}
+
+	// 寬限期內仍可使用，且還沒到期不會被刪除
+	if _, err := env.auth.Authenticate(accessToken); err != nil {
+		t.Errorf("寬限期內 Token 應該仍有效: %v", err)
+	}
+	if erased, err := env.service.PurgeDue(); err != nil || erased != 0 {
+		t.Errorf("寬限期內不應刪除，got %d, %v", erased, err)
+	}
+
+	if err := env.service.CancelDeletion(user.ID); err != nil {
+		t.Fatalf("CancelDeletion() 發生錯誤: %v", err)
+	}
+	if user.DeletionScheduledAt != nil {
+		t.Error("取消後應清除預定刪除時間")
+	}
+	if err := env.service.CancelDeletion(user.ID); !errors.Is(err, ErrNoAccountDeletion) {
+		t.Errorf("沒有申請時取消應回傳 ErrNoAccountDeletion，got %v", err)
+	}
+}
+
+// TestAccountService_PurgeDue 測試寬限期過後匿名化帳號、刪除關聯資料並撤銷所有 Token
+func TestAccountService_PurgeDue(t *testing.T) {
+	env := newAccountTestEnv(t)
+	user, accessToken := env.createUser(t, "purge@example.com")
+	other, _ := env.createUser(t, "keep@example.com")
+
+	env.postRepo.Create(&models.Post{Title: "文章", Content: "內容", UserID: user.ID})
+	env.postRepo.Create(&models.Post{Title: "別人的文章", Content: "內容", UserID: other.ID})
+	env.tokenRepo.Create(&models.PersonalAccessToken{UserID: user.ID, Name: "CI", TokenHash: "hash"})
+	env.identityRepo.Create(&models.Identity{UserID: user.ID, Provider: "google", Subject: "sub-1"})
+
+	if _, err := env.service.RequestDeletion(user.ID, "password123"); err != nil {
+		t.Fatalf("RequestDeletion() 發生錯誤: %v", err)
+	}
+	past := time.Now().Add(-time.Minute)
+	user.DeletionScheduledAt = &past
+
+	erased, err := env.service.PurgeDue()
+	if err != nil || erased != 1 {
+		t.Fatalf("應刪除 1 個帳號，got %d, %v", erased, err)
+	}
+
+	if user.Name != deletedUserName || user.Email == "purge@example.com" || user.Age != 0 || user.DeletionScheduledAt != nil {
+		t.Errorf("個人資料應被匿名化: %+v", user)
+	}
+	if utils.CheckPassword("password123", user.Password) {
+		t.Error("原本的密碼不應再能使用")
+	}
+	if _, err := env.userRepo.FindByID(user.ID); err == nil {
+		t.Error("使用者應被刪除")
+	}
+	if _, err := env.auth.Authenticate(accessToken); err == nil {
+		t.Error("刪除後 Access Token 應失效")
+	}
+
+	if posts, _ := env.postRepo.FindByUserID(user.ID); len(posts) != 0 {
+		t.Errorf("文章應被刪除，剩下 %d 篇", len(posts))
+	}
+	if posts, _ := env.postRepo.FindByUserID(other.ID); len(posts) != 1 {
+		t.Error("其他使用者的文章不應受影響")
+	}
+	if tokens, _ := env.tokenRepo.FindByUser(user.ID); len(tokens) != 0 {
+		t.Error("個人存取 Token 應被刪除")
+	}
+	if identities, _ := env.identityRepo.FindByUser(user.ID); len(identities) != 0 {
+		t.Error("外部身分應被刪除")
+	}
+	if sessions, _ := env.sessionRepo.FindByUser(user.ID); len(sessions) != 0 {
+		t.Error("登入裝置應被刪除")
+	}
+	if histories, _ := env.historyRepo.FindByUser(user.ID); len(histories) != 0 {
+		t.Error("登入紀錄應被刪除")
+	}
+	if histories, _ := env.historyRepo.FindByUser(other.ID); len(histories) != 1 {
+		t.Error("其他使用者的登入紀錄不應受影響")
+	}
+}
+
+// TestAccountService_Erase_SameSecondToken 測試刪除帳號時，同一秒內剛簽發的 Token 也會失效（iat 只精確到秒）
+func TestAccountService_Erase_SameSecondToken(t *testing.T) {
+	env := newAccountTestEnv(t)
+	user, accessToken := env.createUser(t, "same-second@example.com")
+
+	if _, err := env.auth.Authenticate(accessToken); err != nil {
+		t.Fatalf("Authenticate() 發生錯誤: %v", err)
+	}
+	if err := env.service.Erase(user.ID); err != nil {
+		t.Fatalf("Erase() 發生錯誤: %v", err)
+	}
+	if _, err := env.auth.Authenticate(accessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("同一秒簽發的 Token 應失效，預期 ErrTokenRevoked，got %v", err)
+	}
+}
+
+// TestAccountService_Erase_ReassignPosts 測試 reassign 設定下文章轉移給指定使用者
+func TestAccountService_Erase_ReassignPosts(t *testing.T) {
+	env := newAccountTestEnv(t)
+	user, _ := env.createUser(t, "author@example.com")
+	receiver, _ := env.createUser(t, "receiver@example.com")
+	env.postRepo.Create(&models.Post{Title: "文章", Content: "內容", UserID: user.ID})
+
+	config.GlobalConfig.Account.DeletionPostPolicy = PostPolicyReassign
+	config.GlobalConfig.Account.DeletionReassignTo = receiver.ID
+
+	if err := env.service.Erase(receiver.ID); err == nil {
+		t.Error("不應刪除接收文章的帳號")
+	}
+	if err := env.service.Erase(user.ID); err != nil {
+		t.Fatalf("Erase() 發生錯誤: %v", err)
+	}
+	if posts, _ := env.postRepo.FindByUserID(receiver.ID); len(posts) != 1 {
+		t.Errorf("文章應轉移給接收者，got %d 篇", len(posts))
+	}
+}
+
+// TestAccountService_RequestDeletion_NoGracePeriod 測試寬限期為 0 時立即刪除
+func TestAccountService_RequestDeletion_NoGracePeriod(t *testing.T) {
+	env := newAccountTestEnv(t)
+	config.GlobalConfig.Account.DeletionGraceDays = 0
+	user, _ := env.createUser(t, "now@example.com")
+
+	result, err := env.service.RequestDeletion(user.ID, "password123")
+	if err != nil {
+		t.Fatalf("RequestDeletion() 發生錯誤: %v", err)
+	}
+	if !result.Deleted || result.ScheduledAt != nil {
+		t.Errorf("應立即刪除: %+v", result)
+	}
+	if _, err := env.userRepo.FindByID(user.ID); err == nil {
+		t.Error("使用者應被刪除")
+	}
+}
+
+// TestAccountService_RequestDeletion_EmailConfirmation 測試沒有輸入密碼時寄出確認信，點擊簽章連結後才申請
+func TestAccountService_RequestDeletion_EmailConfirmation(t *testing.T) {
+	env := newAccountTestEnv(t)
+	config.GlobalConfig.App = config.AppConfig{URL: "https://api.example.com", Key: "test-app-key"}
+	config.GlobalConfig.Account.DeletionConfirmMinutes = 60
+
+	// 只用 OIDC 登入的帳號沒有人知道密碼
+	hash, err := unusablePassword()
+	if err != nil {
+		t.Fatalf("unusablePassword() 發生錯誤: %v", err)
+	}
+	user := createTestUser(t, env.userRepo, &models.User{Email: "oidc@example.com", Password: hash})
+
+	result, err := env.service.RequestDeletion(user.ID, "")
+	if err != nil {
+		t.Fatalf("RequestDeletion() 發生錯誤: %v", err)
+	}
+	if !result.ConfirmationSent || result.ScheduledAt != nil || user.IsPendingDeletion() {
+		t.Fatalf("應只寄出確認信，尚未申請: %+v", result)
+	}
+
+	msg := env.mailer.Last()
+	if msg == nil || msg.To != "oidc@example.com" || msg.Subject != "確認刪除帳號" {
+		t.Fatalf("應寄出確認刪除帳號信件，got %+v", msg)
+	}
+	var link *url.URL
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if strings.HasPrefix(line, "https://api.example.com/api/me/deletion/confirm/") {
+			link, _ = url.Parse(line)
+		}
+	}
+	if link == nil {
+		t.Fatalf("信件中找不到確認連結: %s", msg.Body)
+	}
+	if err := utils.ValidateSignedURL(link); err != nil {
+		t.Fatalf("確認連結簽章無效: %v", err)
+	}
+
+	if _, err := env.service.ConfirmDeletion(user.ID, utils.HashToken("other@example.com")); !errors.Is(err, ErrInvalidDeletionLink) {
+		t.Errorf("竄改的連結應回傳 ErrInvalidDeletionLink，got %v", err)
+	}
+
+	parts := strings.Split(link.Path, "/")
+	result, err = env.service.ConfirmDeletion(user.ID, parts[len(parts)-1])
+	if err != nil {
+		t.Fatalf("ConfirmDeletion() 發生錯誤: %v", err)
+	}
+	if result.ScheduledAt == nil || !user.IsPendingDeletion() {
+		t.Errorf("點擊連結後應排定刪除: %+v", result)
+	}
+	if _, err := env.service.ConfirmDeletion(user.ID, parts[len(parts)-1]); !errors.Is(err, ErrAccountDeletionPending) {
+		t.Errorf("重複點擊應回傳 ErrAccountDeletionPending，got %v", err)
+	}
+}
+
+// TestAccountService_LastAdmin 測試不可刪除最後一位管理員（自行申請、期滿刪除與管理員刪除使用者）
+func TestAccountService_LastAdmin(t *testing.T) {
+	env := newAccountTestEnv(t)
+	config.GlobalConfig.Account.DeletionGraceDays = 0
+	admin, _ := env.createUser(t, "admin@example.com")
+	env.roleRepo.userRoles[admin.ID] = []string{models.RoleUser, models.RoleAdmin}
+
+	if _, err := env.service.RequestDeletion(admin.ID, testPassword); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("RequestDeletion() 應回傳 ErrLastAdmin，got %v", err)
+	}
+	if _, err := env.service.RequestDeletion(admin.ID, ""); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("沒有密碼時也應回傳 ErrLastAdmin，got %v", err)
+	}
+	if err := env.userService().DeleteUser(admin.ID); !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("DeleteUser() 應回傳 ErrLastAdmin，got %v", err)
+	}
+
+	// 寬限期內其他管理員被移除，期滿時也不會刪除
+	past := time.Now().Add(-time.Minute)
+	admin.DeletionScheduledAt = &past
+	if erased, err := env.service.PurgeDue(); erased != 0 || !errors.Is(err, ErrLastAdmin) {
+		t.Errorf("PurgeDue() 不應刪除最後一位管理員，got %d, %v", erased, err)
+	}
+	admin.DeletionScheduledAt = nil
+	if _, err := env.userRepo.FindByID(admin.ID); err != nil {
+		t.Fatal("最後一位管理員不應被刪除")
+	}
+
+	// 還有其他管理員時可以刪除
+	other, _ := env.createUser(t, "admin2@example.com")
+	env.roleRepo.userRoles[other.ID] = []string{models.RoleAdmin}
+	if err := env.userService().DeleteUser(admin.ID); err != nil {
+		t.Fatalf("DeleteUser() 發生錯誤: %v", err)
+	}
+	if count, _ := env.roleRepo.CountUsersWithRole(models.RoleAdmin); count != 1 {
+		t.Errorf("應剩下一位管理員，got %d", count)
+	}
+}
@@ -2,8 +2,12 @@ package services
 
 import (
 	"errors"
+	"time"
 
+	"github.com/google/uuid"
 	"my-api/app/models"
+	"my-api/app/pkg/password"
+	"my-api/app/pkg/revocation"
 	"my-api/app/repositories"
 	"my-api/app/requests"
 	"my-api/app/responses"
@@ -11,33 +15,80 @@ import (
 	"my-api/config"
 )
 
+var (
+	// ErrInvalidCredentials - 帳號或密碼錯誤（不區分 Email 不存在或密碼錯誤）
+	ErrInvalidCredentials = errors.New("帳號或密碼錯誤")
+	// ErrInvalidRefreshToken - Refresh Token 不存在、已過期或已撤銷
+	ErrInvalidRefreshToken = errors.New("無效或已過期的 Refresh Token")
+	// ErrRefreshTokenReused - 已使用過的 Refresh Token 被再次使用（可能遭竊）
+	ErrRefreshTokenReused = errors.New("Refresh Token 已被使用，已撤銷此登入的所有 Token")
+	// ErrTokenRevoked - Access Token 已被撤銷（登出）
+	ErrTokenRevoked = errors.New("Token 已被撤銷")
+)
+
 // AuthService - 認證業務邏輯層介面
 type AuthService interface {
-	Register(req *requests.RegisterRequest) (*responses.AuthResponse, error)
-	Login(req *requests.LoginRequest) (*responses.AuthResponse, error)
+	Register(req *requests.RegisterRequest, client ClientInfo) (*responses.AuthResponse, error)
+	Login(req *requests.LoginRequest, client ClientInfo) (*responses.LoginResponse, error)
+	TwoFactorLogin(req *requests.TwoFactorChallengeRequest, client ClientInfo) (*responses.AuthResponse, error)
+	Refresh(req *requests.RefreshTokenRequest) (*responses.AuthResponse, error)
+	Authenticate(tokenString string) (*utils.JWTClaims, error)
+	Logout(claims *utils.JWTClaims, req *requests.LogoutRequest) error
+	LogoutAll(userID uint) error
+	LogoutOtherSessions(userID uint, currentSessionID string) error
+	CompleteLogin(user *models.User, client ClientInfo) (*responses.LoginResponse, error)
 	GetCurrentUser(userID uint) (*responses.UserResponse, error)
+	UnlockUser(userID uint) (*models.User, error)
 }
 
 // authService - 實作 AuthService 介面
 type authService struct {
-	userRepo repositories.UserRepository
+	userRepo         repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	roleRepo         repositories.RoleRepository
+	revocations      revocation.Store
+	verification     EmailVerificationService
+	loginThrottle    LoginThrottle
+	twoFactor        TwoFactorService
+	sessions         SessionService
 }
 
 // NewAuthService - 建立新的 AuthService 實例
-func NewAuthService(userRepo repositories.UserRepository) AuthService {
+func NewAuthService(
+	userRepo repositories.UserRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	roleRepo repositories.RoleRepository,
+	revocations revocation.Store,
+	verification EmailVerificationService,
+	loginThrottle LoginThrottle,
+	twoFactor TwoFactorService,
+	sessions SessionService,
+) AuthService {
 	return &authService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		roleRepo:         roleRepo,
+		revocations:      revocations,
+		verification:     verification,
+		loginThrottle:    loginThrottle,
+		twoFactor:        twoFactor,
+		sessions:         sessions,
 	}
 }
 
 // Register - 使用者註冊
-func (s *authService) Register(req *requests.RegisterRequest) (*responses.AuthResponse, error) {
+func (s *authService) Register(req *requests.RegisterRequest, client ClientInfo) (*responses.AuthResponse, error) {
 	// 檢查 Email 是否已存在
 	existingUser, _ := s.userRepo.FindByEmail(req.Email)
 	if existingUser != nil {
 		return nil, errors.New("電子郵件已被使用")
 	}
 
+	// 檢查密碼規則（長度、字元種類、不可包含個人資料）
+	if err := password.Default().Validate(req.Password, password.User{Name: req.Name, Email: req.Email}); err != nil {
+		return nil, err
+	}
+
 	// 加密密碼
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -56,37 +107,217 @@ func (s *authService) Register(req *requests.RegisterRequest) (*responses.AuthRe
 		return nil, err
 	}
 
-	// 產生 JWT Token
-	token, err := utils.GenerateToken(user.ID, user.Email)
-	if err != nil {
-		return nil, errors.New("Token 產生失敗")
+	// 指派預設角色
+	if err := assignDefaultRoles(s.roleRepo, user); err != nil {
+		return nil, err
 	}
 
-	expiresIn := config.GlobalConfig.JWT.ExpiryHours * 3600
-	return responses.NewAuthResponse(token, expiresIn, user), nil
+	// 寄送驗證信；寄送失敗不影響註冊，使用者可以透過 POST /api/email/resend 重新寄送
+	_ = s.verification.SendVerificationEmail(user)
+
+	// 產生 Access Token + Refresh Token
+	return s.issueTokens(user, "", client)
 }
 
 // Login - 使用者登入
-func (s *authService) Login(req *requests.LoginRequest) (*responses.AuthResponse, error) {
+// 失敗次數過多時回傳 *LockoutError（Controller 回應 429 + Retry-After）
+// 已啟用兩步驟驗證時不發 Token，改回傳 challenge token
+func (s *authService) Login(req *requests.LoginRequest, client ClientInfo) (*responses.LoginResponse, error) {
+	// 鎖定期間直接拒絕，不檢查密碼
+	if err := s.loginThrottle.Check(req.Email, client); err != nil {
+		return nil, err
+	}
+
 	// 查詢使用者
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
-		return nil, errors.New("帳號或密碼錯誤")
+		return nil, s.loginFailed(req.Email, client)
 	}
 
 	// 驗證密碼
 	if !utils.CheckPassword(req.Password, user.Password) {
-		return nil, errors.New("帳號或密碼錯誤")
+		s.sessions.RecordFailedLogin(user.ID, client)
+		return nil, s.loginFailed(req.Email, client)
 	}
 
-	// 產生 JWT Token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	s.loginThrottle.Succeeded(req.Email)
+
+	return s.CompleteLogin(user, client)
+}
+
+// CompleteLogin - 身分已確認（密碼或 OpenID Connect）後發出 Token
+// 已啟用兩步驟驗證時不發 Token，改回傳 challenge token
+func (s *authService) CompleteLogin(user *models.User, client ClientInfo) (*responses.LoginResponse, error) {
+	if user.HasTwoFactorEnabled() {
+		challenge, err := s.twoFactor.CreateChallenge(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &responses.LoginResponse{
+			TwoFactorRequired:  true,
+			ChallengeToken:     challenge,
+			ChallengeExpiresIn: config.GlobalConfig.Auth.TwoFactorChallengeMinutes * 60,
+		}, nil
+	}
+
+	// 產生 Access Token + Refresh Token
+	tokens, err := s.issueTokens(user, "", client)
 	if err != nil {
-		return nil, errors.New("Token 產生失敗")
+		return nil, err
+	}
+	return &responses.LoginResponse{AuthResponse: tokens}, nil
+}
+
+// TwoFactorLogin - 以登入時取得的 challenge token + 驗證碼（或復原碼）換取 Token
+func (s *authService) TwoFactorLogin(req *requests.TwoFactorChallengeRequest, client ClientInfo) (*responses.AuthResponse, error) {
+	user, err := s.twoFactor.VerifyChallenge(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, "", client)
+}
+
+// Refresh - 使用 Refresh Token 換發新的 Token（每次都會輪替 Refresh Token）
+//
+// 重複使用偵測：
+// 已經換發過的 Refresh Token 再次出現，代表有人持有舊 Token（可能被竊取），
+// 此時撤銷整個 Token Family，合法使用者與攻擊者都必須重新登入。
+func (s *authService) Refresh(req *requests.RefreshTokenRequest) (*responses.AuthResponse, error) {
+	stored, err := s.refreshTokenRepo.FindByHash(utils.HashToken(req.RefreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
 	}
 
-	expiresIn := config.GlobalConfig.JWT.ExpiryHours * 3600
-	return responses.NewAuthResponse(token, expiresIn, user), nil
+	if stored.UsedAt != nil {
+		_ = s.sessions.End(stored.FamilyID)
+		return nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// 條件式更新，併發使用同一個 Token 時只有一個請求能成功
+	marked, err := s.refreshTokenRepo.MarkUsed(stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		_ = s.sessions.End(stored.FamilyID)
+		return nil, ErrRefreshTokenReused
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil {
+		_ = s.sessions.End(stored.FamilyID)
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return s.issueTokens(user, stored.FamilyID, ClientInfo{})
+}
+
+// Authenticate - 驗證 Access Token（簽名、過期時間、撤銷清單）
+// 由 AuthMiddleware 呼叫
+func (s *authService) Authenticate(tokenString string) (*utils.JWTClaims, error) {
+	claims, err := utils.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// 單一 Token 是否已登出
+	revoked, err := s.revocations.IsRevoked(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	// 使用者是否執行過「登出所有裝置」
+	cutoff, err := s.revocations.UserRevokedAt(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.IssuedAt != nil && revocation.IssuedBeforeCutoff(claims.IssuedAt.Time, cutoff) {
+		return nil, ErrTokenRevoked
+	}
+
+	// 登入裝置是否已被登出
+	if claims.SessionID != "" {
+		if err := s.sessions.Check(claims.SessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	return claims, nil
+}
+
+// Logout - 登出目前的 Token
+// Access Token 的 jti 加入撤銷清單直到原本的過期時間，並結束 Token 所屬的工作階段（sid）；
+// 若一併提供 Refresh Token，該 Refresh Token 所屬的工作階段也會結束
+func (s *authService) Logout(claims *utils.JWTClaims, req *requests.LogoutRequest) error {
+	expiresAt := time.Now().Add(time.Duration(config.GlobalConfig.JWT.AccessExpiryMinutes) * time.Minute)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+
+	if err := s.revocations.Revoke(claims.ID, expiresAt); err != nil {
+		return errors.New("登出失敗")
+	}
+
+	if claims.SessionID != "" {
+		if err := s.sessions.End(claims.SessionID); err != nil {
+			return errors.New("登出失敗")
+		}
+	}
+
+	if req != nil && req.RefreshToken != "" {
+		stored, err := s.refreshTokenRepo.FindByHash(utils.HashToken(req.RefreshToken))
+		if err == nil && stored.UserID == claims.UserID && stored.FamilyID != claims.SessionID {
+			if err := s.sessions.End(stored.FamilyID); err != nil {
+				return errors.New("登出失敗")
+			}
+		}
+	}
+
+	return nil
+}
+
+// LogoutAll - 登出所有裝置
+// 撤銷使用者目前為止簽發的所有 Access Token 與 Refresh Token
+func (s *authService) LogoutAll(userID uint) error {
+	// 撤銷紀錄只需保留到最後一個 Access Token 過期為止
+	ttl := time.Duration(config.GlobalConfig.JWT.AccessExpiryMinutes) * time.Minute
+	if err := s.revocations.RevokeUser(userID, time.Now(), ttl); err != nil {
+		return errors.New("登出失敗")
+	}
+
+	if err := s.refreshTokenRepo.RevokeAllForUser(userID); err != nil {
+		return errors.New("登出失敗")
+	}
+
+	if err := s.sessions.EndAll(userID); err != nil {
+		return errors.New("登出失敗")
+	}
+
+	return nil
+}
+
+// LogoutOtherSessions - 登出目前裝置以外的所有裝置（變更密碼後呼叫）
+// currentSessionID 為空（例如以 API Key 呼叫）時等同登出所有裝置
+func (s *authService) LogoutOtherSessions(userID uint, currentSessionID string) error {
+	if currentSessionID == "" {
+		return s.LogoutAll(userID)
+	}
+	if err := s.sessions.EndOthers(userID, currentSessionID); err != nil {
+		return errors.New("登出失敗")
+	}
+	return nil
 }
 
 // GetCurrentUser - 取得當前用戶資訊
@@ -98,3 +329,92 @@ func (s *authService) GetCurrentUser(userID uint) (*responses.UserResponse, erro
 
 	return responses.NewUserResponse(user), nil
 }
+
+// UnlockUser - 解除帳號的登入鎖定（管理員使用），回傳被解鎖的使用者
+func (s *authService) UnlockUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("使用者不存在")
+	}
+
+	if err := s.loginThrottle.Unlock(user.Email); err != nil {
+		return nil, errors.New("解除鎖定失敗")
+	}
+
+	return user, nil
+}
+
+// loginFailed - 記錄登入失敗
+// 這次失敗觸發鎖定時回傳 *LockoutError，否則一律回傳 ErrInvalidCredentials
+func (s *authService) loginFailed(email string, client ClientInfo) error {
+	var lockout *LockoutError
+	if err := s.loginThrottle.Failed(email, client); errors.As(err, &lockout) {
+		return lockout
+	}
+	return ErrInvalidCredentials
+}
+
+// assignDefaultRoles - 新註冊使用者指派 user 角色
+// 註冊與 OIDC 登入一律不會取得 admin，第一位管理員由 AdminSeeder 指派
+func assignDefaultRoles(roleRepo repositories.RoleRepository, user *models.User) error {
+	roles, err := roleRepo.FindByNames([]string{models.RoleUser})
+	if err != nil {
+		return errors.New("指派角色失敗")
+	}
+	if err := roleRepo.SyncUserRoles(user.ID, roles); err != nil {
+		return errors.New("指派角色失敗")
+	}
+
+	user.Roles = roles
+	return nil
+}
+
+// issueTokens - 產生 Access Token 與 Refresh Token
+// familyID 為空字串時代表新的登入，會建立新的 Token Family 與工作階段（記錄 client 的 IP、User-Agent）；
+// 否則延長既有的工作階段
+func (s *authService) issueTokens(user *models.User, familyID string, client ClientInfo) (*responses.AuthResponse, error) {
+	cfg := config.GlobalConfig.JWT
+
+	roles, err := s.roleRepo.RoleNamesForUser(user.ID)
+	if err != nil {
+		return nil, errors.New("Token 產生失敗")
+	}
+
+	refreshToken, err := utils.GenerateRandomToken()
+	if err != nil {
+		return nil, errors.New("Token 產生失敗")
+	}
+
+	expiresAt := time.Now().Add(time.Duration(cfg.RefreshExpiryHours) * time.Hour)
+	if familyID == "" {
+		familyID = uuid.New().String()
+		if err := s.sessions.Start(user, familyID, expiresAt, client); err != nil {
+			return nil, err
+		}
+	} else if err := s.sessions.Extend(familyID, expiresAt); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := utils.GenerateToken(user.ID, user.Email, roles, familyID)
+	if err != nil {
+		return nil, errors.New("Token 產生失敗")
+	}
+
+	record := &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.refreshTokenRepo.Create(record); err != nil {
+		return nil, errors.New("Token 產生失敗")
+	}
+
+	return responses.NewAuthResponse(
+		accessToken,
+		cfg.AccessExpiryMinutes*60,
+		refreshToken,
+		cfg.RefreshExpiryHours*3600,
+		user,
+	), nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/pkg/password"
+	"my-api/app/requests"
+	"my-api/app/utils"
+)
+
+// ============================================================================
+// Mock Repository
+// ============================================================================
+// mockRefreshTokenRepository 實作 RefreshTokenRepository interface
+type mockRefreshTokenRepository struct {
+	tokens map[uint]*models.RefreshToken
+	nextID uint
+}
+
+// newMockRefreshTokenRepository 建立新的 mock repository
+func newMockRefreshTokenRepository() *mockRefreshTokenRepository {
+	return &mockRefreshTokenRepository{
+		tokens: make(map[uint]*models.RefreshToken),
+		nextID: 1,
+	}
+}
+
+func (m *mockRefreshTokenRepository) Create(token *models.RefreshToken) error {
+	token.ID = m.nextID
+	m.nextID++
+	m.tokens[token.ID] = token
+	return nil
+}
+
+func (m *mockRefreshTokenRepository) FindByHash(hash string) (*models.RefreshToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockRefreshTokenRepository) MarkUsed(id uint) (bool, error) {
+	token, ok := m.tokens[id]
+	if !ok || token.UsedAt != nil || token.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return true, nil
+}
+
+func (m *mockRefreshTokenRepository) RevokeFamily(familyID string) error {
+	now := time.Now()
+	for _, token := range m.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *mockRefreshTokenRepository) RevokeAllForUser(userID uint) error {
+	now := time.Now()
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// newTestAuthService 建立測試用 AuthService，並預先建立一個可登入的使用者
+func newTestAuthService(t *testing.T) (AuthService, *mockRefreshTokenRepository) {
+	service, refreshRepo, _ := newTestAuthServiceWithRoles(t)
+	return service, refreshRepo
+}
+
+// newTestAuthServiceWithRoles 同 newTestAuthService，另外回傳角色 mock repository
+func newTestAuthServiceWithRoles(t *testing.T) (AuthService, *mockRefreshTokenRepository, *mockRoleRepository) {
+	t.Helper()
+	setupTestConfig()
+
+	deps := newTestServices(newMockUserRepository())
+	createTestUser(t, deps.userRepo, &models.User{Email: "auth@example.com"})
+	return deps.authService(), deps.refreshRepo, deps.roleRepo
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestAuthService_Login 測試登入會同時回傳 Access Token 與 Refresh Token
+func TestAuthService_Login(t *testing.T) {
+	service, refreshRepo := newTestAuthService(t)
+
+	resp, err := service.Login(&requests.LoginRequest{Email: "auth@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" {
+		t.Fatal("應同時回傳 Access Token 與 Refresh Token")
+	}
+	if resp.Token != resp.AccessToken {
+		t.Error("舊版的 token 欄位應與 access_token 相同")
+	}
+	if resp.ExpiresIn != 15*60 {
+		t.Errorf("ExpiresIn 不符，got %d, want %d", resp.ExpiresIn, 15*60)
+	}
+	if resp.RefreshExpiresIn != 720*3600 {
+		t.Errorf("RefreshExpiresIn 不符，got %d, want %d", resp.RefreshExpiresIn, 720*3600)
+	}
+
+	// 資料庫只能存雜湊
+	for _, token := range refreshRepo.tokens {
+		if token.TokenHash == resp.RefreshToken {
+			t.Error("Refresh Token 不應以明文儲存")
+		}
+	}
+}
+
+// TestAuthService_Register 測試註冊只會指派 user 角色並寫入 JWT，系統中沒有管理員時也不會取得 admin
+func TestAuthService_Register(t *testing.T) {
+	service, _, roleRepo := newTestAuthServiceWithRoles(t)
+
+	register := func(email string) []string {
+		resp, err := service.Register(&requests.RegisterRequest{Name: "新使用者", Email: email, Password: testPassword}, testClient)
+		if err != nil {
+			t.Fatalf("註冊失敗: %v", err)
+		}
+		claims, err := utils.ValidateToken(resp.AccessToken)
+		if err != nil {
+			t.Fatalf("Token 無效: %v", err)
+		}
+		return claims.Roles
+	}
+
+	for _, email := range []string{"first@example.com", "second@example.com"} {
+		if roles := register(email); containsString(roles, models.RoleAdmin) || !containsString(roles, models.RoleUser) {
+			t.Errorf("%s 註冊後只應擁有 user 角色，got %v", email, roles)
+		}
+	}
+
+	if count, _ := roleRepo.CountUsersWithRole(models.RoleAdmin); count != 0 {
+		t.Errorf("註冊不應產生管理員，got %d", count)
+	}
+}
+
+// TestAuthService_Register_PasswordPolicy 測試註冊時套用密碼規則
+func TestAuthService_Register_PasswordPolicy(t *testing.T) {
+	service, _ := newTestAuthService(t)
+
+	tests := []struct {
+		name     string
+		password string
+	}{
+		{name: "長度不足", password: "short"},
+		{name: "包含名稱", password: "xx-newcomer-xx"},
+		{name: "包含 Email 帳號", password: "policy.user!!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Register(&requests.RegisterRequest{Name: "Newcomer", Email: "policy.user@example.com", Password: tt.password}, testClient)
+			var policyErr *password.PolicyError
+			if !errors.As(err, &policyErr) {
+				t.Errorf("預期 *password.PolicyError，got %v", err)
+			}
+		})
+	}
+}
+
+// TestAuthService_Refresh 測試 Refresh Token 輪替與重複使用偵測
+func TestAuthService_Refresh(t *testing.T) {
+	service, refreshRepo := newTestAuthService(t)
+
+	login, err := service.Login(&requests.LoginRequest{Email: "auth@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+
+	// 第一次刷新：成功並取得新的 Refresh Token
+	rotated, err := service.Refresh(&requests.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if err != nil {
+		t.Fatalf("刷新失敗: %v", err)
+	}
+	if rotated.RefreshToken == login.RefreshToken {
+		t.Error("刷新後應該輪替出新的 Refresh Token")
+	}
+
+	// 再次使用舊的 Refresh Token：視為重複使用
+	_, err = service.Refresh(&requests.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("預期 ErrRefreshTokenReused，got %v", err)
+	}
+
+	// 整個 Family 都應被撤銷，連新的 Refresh Token 也不能用
+	for _, token := range refreshRepo.tokens {
+		if token.RevokedAt == nil {
+			t.Errorf("Refresh Token #%d 應該已被撤銷", token.ID)
+		}
+	}
+	_, err = service.Refresh(&requests.RefreshTokenRequest{RefreshToken: rotated.RefreshToken})
+	if !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("預期 ErrInvalidRefreshToken，got %v", err)
+	}
+}
+
+// TestAuthService_Refresh_Invalid 測試無效或過期的 Refresh Token
+func TestAuthService_Refresh_Invalid(t *testing.T) {
+	service, refreshRepo := newTestAuthService(t)
+
+	login, _ := service.Login(&requests.LoginRequest{Email: "auth@example.com", Password: "password123"}, testClient)
+
+	// 讓 Token 過期
+	for _, token := range refreshRepo.tokens {
+		token.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "不存在的 Token", token: "not-a-real-token"},
+		{name: "已過期的 Token", token: login.RefreshToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Refresh(&requests.RefreshTokenRequest{RefreshToken: tt.token})
+			if !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("預期 ErrInvalidRefreshToken，got %v", err)
+			}
+		})
+	}
+}
+
+// TestAuthService_Logout 測試登出後 Access Token 與 Refresh Token 都失效
+func TestAuthService_Logout(t *testing.T) {
+	service, _ := newTestAuthService(t)
+
+	login, err := service.Login(&requests.LoginRequest{Email: "auth@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+
+	claims, err := service.Authenticate(login.AccessToken)
+	if err != nil {
+		t.Fatalf("登出前 Token 應該有效: %v", err)
+	}
+	if claims.ID == "" {
+		t.Fatal("Token 應該帶有 jti")
+	}
+
+	if err := service.Logout(claims, &requests.LogoutRequest{RefreshToken: login.RefreshToken}); err != nil {
+		t.Fatalf("登出失敗: %v", err)
+	}
+
+	if _, err := service.Authenticate(login.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("登出後預期 ErrTokenRevoked，got %v", err)
+	}
+	if _, err := service.Refresh(&requests.RefreshTokenRequest{RefreshToken: login.RefreshToken}); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("登出後 Refresh Token 應失效，got %v", err)
+	}
+}
+
+// TestAuthService_LogoutAll 測試登出所有裝置
+func TestAuthService_LogoutAll(t *testing.T) {
+	service, _ := newTestAuthService(t)
+
+	req := &requests.LoginRequest{Email: "auth@example.com", Password: "password123"}
+	first, _ := service.Login(req, testClient)
+	second, _ := service.Login(req, testClient)
+
+	claims, err := service.Authenticate(first.AccessToken)
+	if err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+	if err := service.LogoutAll(claims.UserID); err != nil {
+		t.Fatalf("登出所有裝置失敗: %v", err)
+	}
+
+	// 與撤銷時間點同一秒簽發的 Token 也要失效（iat 只精確到秒）
+	for _, login := range []*struct{ access, refresh string }{
+		{first.AccessToken, first.RefreshToken},
+		{second.AccessToken, second.RefreshToken},
+	} {
+		if _, err := service.Authenticate(login.access); !errors.Is(err, ErrTokenRevoked) {
+			t.Errorf("預期 ErrTokenRevoked，got %v", err)
+		}
+		if _, err := service.Refresh(&requests.RefreshTokenRequest{RefreshToken: login.refresh}); err == nil {
+			t.Error("Refresh Token 應該已被撤銷")
+		}
+	}
+
+	// 重新登入拿到的 Token 不受影響
+	again, err := service.Login(req, testClient)
+	if err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+	if _, err := service.Authenticate(again.AccessToken); err != nil {
+		t.Errorf("重新登入的 Token 應該有效: %v", err)
+	}
+}
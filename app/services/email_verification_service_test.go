@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"my-api/app/models"
+	"my-api/app/pkg/mail"
+	"my-api/app/requests"
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// setupVerificationConfig 設定 Email 驗證相關配置
+func setupVerificationConfig() {
+	setupTestConfig()
+	config.GlobalConfig.App = config.AppConfig{URL: "https://api.example.com", Key: "test-app-key"}
+	config.GlobalConfig.Auth.VerificationExpiryMinutes = 60
+}
+
+// verificationLinkFromMail 從信件中取出驗證連結
+func verificationLinkFromMail(t *testing.T, msg *mail.Message) *url.URL {
+	t.Helper()
+	if msg == nil {
+		t.Fatal("應該寄出驗證信")
+	}
+
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if strings.HasPrefix(line, "https://api.example.com/api/email/verify/") {
+			link, err := url.Parse(line)
+			if err != nil {
+				t.Fatalf("連結格式錯誤: %v", err)
+			}
+			return link
+		}
+	}
+	t.Fatalf("信件中找不到驗證連結: %s", msg.Body)
+	return nil
+}
+
+// hashFromLink 取出連結 path 最後一段的 Email 雜湊
+func hashFromLink(link *url.URL) string {
+	parts := strings.Split(link.Path, "/")
+	return parts[len(parts)-1]
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestEmailVerification_Register 測試註冊後寄出簽章連結，點擊後完成驗證
+func TestEmailVerification_Register(t *testing.T) {
+	setupVerificationConfig()
+
+	deps := newTestServices(newMockUserRepository())
+	userRepo, mailer, verification := deps.userRepo, deps.mailer, deps.verification
+	auth := deps.authService()
+
+	resp, err := auth.Register(&requests.RegisterRequest{Name: "新使用者", Email: "verify@example.com", Password: "password123", PasswordConfirm: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("註冊失敗: %v", err)
+	}
+	if resp.User.EmailVerifiedAt != nil {
+		t.Error("剛註冊的使用者不應該是已驗證")
+	}
+
+	link := verificationLinkFromMail(t, mailer.Last())
+	if err := utils.ValidateSignedURL(link); err != nil {
+		t.Fatalf("驗證連結簽章無效: %v", err)
+	}
+
+	// 竄改 hash 的連結
+	if err := verification.Verify(resp.User.ID, utils.HashToken("other@example.com")); !errors.Is(err, ErrInvalidVerificationLink) {
+		t.Errorf("預期 ErrInvalidVerificationLink，got %v", err)
+	}
+
+	if err := verification.Verify(resp.User.ID, hashFromLink(link)); err != nil {
+		t.Fatalf("Verify() 發生錯誤: %v", err)
+	}
+	user, _ := userRepo.FindByID(resp.User.ID)
+	if !user.HasVerifiedEmail() {
+		t.Error("點擊連結後應該完成驗證")
+	}
+
+	// 已驗證後不需要重新寄送
+	if err := verification.Resend(user.ID); !errors.Is(err, ErrEmailAlreadyVerified) {
+		t.Errorf("預期 ErrEmailAlreadyVerified，got %v", err)
+	}
+}
+
+// TestEmailVerification_EmailChange 測試變更 Email 後需要重新驗證，舊連結失效
+func TestEmailVerification_EmailChange(t *testing.T) {
+	setupVerificationConfig()
+
+	deps := newTestServices(newMockUserRepository())
+	userRepo, mailer, verification := deps.userRepo, deps.mailer, deps.verification
+	service := deps.userService()
+
+	user := &models.User{Name: "張三", Email: "old@example.com"}
+	userRepo.Create(user)
+	verification.SendVerificationEmail(user)
+	oldLink := verificationLinkFromMail(t, mailer.Last())
+	verification.Verify(user.ID, hashFromLink(oldLink))
+
+	// 只改名字不會寄信
+	sent := len(mailer.Messages())
+	if _, err := service.UpdateUser(user.ID, &requests.UpdateUserRequest{Name: "張三豐"}); err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+	if len(mailer.Messages()) != sent {
+		t.Error("Email 沒有變更時不應寄送驗證信")
+	}
+
+	resp, err := service.UpdateUser(user.ID, &requests.UpdateUserRequest{Email: "new@example.com"})
+	if err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+	if resp.EmailVerifiedAt != nil {
+		t.Error("變更 Email 後應該變回未驗證")
+	}
+
+	msg := mailer.Last()
+	if msg.To != "new@example.com" {
+		t.Errorf("驗證信應寄到新的 Email，got %s", msg.To)
+	}
+
+	// 舊 Email 的連結不能驗證新 Email
+	if err := verification.Verify(user.ID, hashFromLink(oldLink)); !errors.Is(err, ErrInvalidVerificationLink) {
+		t.Errorf("預期 ErrInvalidVerificationLink，got %v", err)
+	}
+	if err := verification.Verify(user.ID, hashFromLink(verificationLinkFromMail(t, msg))); err != nil {
+		t.Errorf("新連結應該可以完成驗證: %v", err)
+	}
+}
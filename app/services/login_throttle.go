@@ -0,0 +1,183 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"my-api/app/pkg/logger"
+	"my-api/app/pkg/throttle"
+	"my-api/config"
+)
+
+// ClientInfo - 發出請求的用戶端資訊（由 Controller 從 gin.Context 取得）
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// LockoutError - 登入嘗試次數過多，暫時鎖定
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+// Error - 實作 error 介面
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("登入嘗試次數過多，請於 %d 秒後再試", e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds - 無條件進位的剩餘秒數（Retry-After header 使用）
+func (e *LockoutError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// LoginThrottle - 登入失敗次數限制
+//
+// 同時以 Email 與 IP 兩個維度計數：
+//   - Email：保護單一帳號不被猜密碼
+//   - IP：限制同一來源對多個帳號的嘗試（門檻較高，避免 NAT 後的使用者互相影響）
+//
+// 登入成功只清除 Email 的計數；IP 的計數等它自然過期，
+// 避免攻擊者用自己的帳號登入來重置 IP 計數
+type LoginThrottle interface {
+	Check(email string, client ClientInfo) error
+	Failed(email string, client ClientInfo) error
+	Succeeded(email string)
+	Unlock(email string) error
+}
+
+// loginThrottle - 實作 LoginThrottle 介面
+type loginThrottle struct {
+	byEmail *throttle.Limiter
+	byIP    *throttle.Limiter
+	log     *logger.Logger
+}
+
+// NewLoginThrottle - 建立新的 LoginThrottle 實例
+// log 為 nil 時不記錄安全性日誌（測試用）
+func NewLoginThrottle(store throttle.Store, log *logger.Logger) LoginThrottle {
+	cfg := config.GlobalConfig.Auth
+
+	policy := func(maxAttempts int) throttle.Policy {
+		return throttle.Policy{
+			MaxAttempts: maxAttempts,
+			BaseLockout: time.Duration(cfg.LoginLockoutSeconds) * time.Second,
+			MaxLockout:  time.Duration(cfg.LoginMaxLockoutSeconds) * time.Second,
+			Decay:       time.Duration(cfg.LoginDecayMinutes) * time.Minute,
+		}
+	}
+
+	return &loginThrottle{
+		byEmail: throttle.NewLimiter(store, "login:email", policy(cfg.LoginMaxAttemptsPerEmail)),
+		byIP:    throttle.NewLimiter(store, "login:ip", policy(cfg.LoginMaxAttemptsPerIP)),
+		log:     log,
+	}
+}
+
+// Check - 登入前檢查是否鎖定中，鎖定時回傳 *LockoutError
+func (t *loginThrottle) Check(email string, client ClientInfo) error {
+	email = normalizeEmail(email)
+
+	byEmail, err := t.byEmail.RetryAfter(email)
+	if err != nil {
+		return err
+	}
+	byIP, err := t.byIP.RetryAfter(client.IP)
+	if err != nil {
+		return err
+	}
+
+	retryAfter := max(byEmail, byIP)
+	if retryAfter <= 0 {
+		return nil
+	}
+
+	lockout := &LockoutError{RetryAfter: retryAfter}
+	t.securityLog("warn", "鎖定期間嘗試登入", map[string]interface{}{
+		"event":       "login_blocked",
+		"email":       email,
+		"ip":          client.IP,
+		"user_agent":  client.UserAgent,
+		"retry_after": lockout.RetryAfterSeconds(),
+	})
+	return lockout
+}
+
+// Failed - 記錄一次登入失敗；這次失敗觸發鎖定時回傳 *LockoutError
+func (t *loginThrottle) Failed(email string, client ClientInfo) error {
+	email = normalizeEmail(email)
+
+	emailAttempts, emailLockout, err := t.byEmail.Fail(email)
+	if err != nil {
+		return err
+	}
+	ipAttempts, ipLockout, err := t.byIP.Fail(client.IP)
+	if err != nil {
+		return err
+	}
+
+	t.securityLog("warn", "登入失敗", map[string]interface{}{
+		"event":          "login_failed",
+		"email":          email,
+		"ip":             client.IP,
+		"user_agent":     client.UserAgent,
+		"email_attempts": emailAttempts,
+		"ip_attempts":    ipAttempts,
+	})
+
+	lockout := max(emailLockout, ipLockout)
+	if lockout <= 0 {
+		return nil
+	}
+
+	t.securityLog("error", "登入失敗次數過多，暫時鎖定", map[string]interface{}{
+		"event":          "login_locked",
+		"email":          email,
+		"ip":             client.IP,
+		"email_attempts": emailAttempts,
+		"ip_attempts":    ipAttempts,
+		"email_lockout":  int(emailLockout.Seconds()),
+		"ip_lockout":     int(ipLockout.Seconds()),
+	})
+	return &LockoutError{RetryAfter: lockout}
+}
+
+// Succeeded - 登入成功，清除該 Email 的失敗紀錄
+func (t *loginThrottle) Succeeded(email string) {
+	email = normalizeEmail(email)
+
+	if err := t.byEmail.Clear(email); err != nil {
+		t.securityLog("error", "清除登入失敗紀錄失敗", map[string]interface{}{
+			"email": email,
+			"error": err.Error(),
+		})
+	}
+}
+
+// Unlock - 管理員解除帳號鎖定（IP 的鎖定不受影響）
+func (t *loginThrottle) Unlock(email string) error {
+	return t.byEmail.Clear(normalizeEmail(email))
+}
+
+// securityLog - 記錄安全性事件（統一帶上 channel=security，方便篩選）
+func (t *loginThrottle) securityLog(level, message string, fields map[string]interface{}) {
+	if t.log == nil {
+		return
+	}
+
+	log := t.log.WithContext(map[string]interface{}{"channel": "security"})
+	switch level {
+	case "error":
+		log.Error(message, fields)
+	case "warn":
+		log.Warning(message, fields)
+	default:
+		log.Info(message, fields)
+	}
+}
+
+// normalizeEmail - Email 不分大小寫計數
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/pkg/throttle"
+	"my-api/app/requests"
+	"my-api/config"
+)
+
+// setupThrottleConfig 設定登入鎖定規則：Email 3 次、IP 5 次
+func setupThrottleConfig() {
+	setupTestConfig()
+	config.GlobalConfig.Auth = config.AuthConfig{
+		LoginMaxAttemptsPerEmail: 3,
+		LoginMaxAttemptsPerIP:    5,
+		LoginLockoutSeconds:      60,
+		LoginMaxLockoutSeconds:   300,
+		LoginDecayMinutes:        15,
+	}
+}
+
+// newThrottledAuthService 建立啟用登入鎖定的 AuthService
+func newThrottledAuthService(t *testing.T) AuthService {
+	t.Helper()
+	setupThrottleConfig()
+
+	deps := newTestServices(newMockUserRepository())
+	for _, email := range []string{"victim@example.com", "other@example.com"} {
+		createTestUser(t, deps.userRepo, &models.User{Email: email})
+	}
+	return deps.authService()
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestAuthService_Login_Lockout 測試同一 Email 連續失敗後鎖定，管理員可解鎖
+func TestAuthService_Login_Lockout(t *testing.T) {
+	service := newThrottledAuthService(t)
+	wrong := &requests.LoginRequest{Email: "victim@example.com", Password: "wrong-password"}
+	right := &requests.LoginRequest{Email: "victim@example.com", Password: "password123"}
+
+	// 前兩次只是帳密錯誤
+	for i := 0; i < 2; i++ {
+		if _, err := service.Login(wrong, testClient); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("第 %d 次預期 ErrInvalidCredentials，got %v", i+1, err)
+		}
+	}
+
+	// 第三次觸發鎖定（Email 不分大小寫計數）
+	_, err := service.Login(&requests.LoginRequest{Email: "Victim@Example.com", Password: "wrong-password"}, testClient)
+	var lockout *LockoutError
+	if !errors.As(err, &lockout) {
+		t.Fatalf("預期 LockoutError，got %v", err)
+	}
+	if lockout.RetryAfterSeconds() != 60 {
+		t.Errorf("第一次鎖定應為 60 秒，got %d", lockout.RetryAfterSeconds())
+	}
+
+	// 鎖定期間正確密碼也不能登入
+	if _, err := service.Login(right, testClient); !errors.As(err, &lockout) {
+		t.Errorf("鎖定期間預期 LockoutError，got %v", err)
+	}
+
+	// 其他帳號不受影響
+	if _, err := service.Login(&requests.LoginRequest{Email: "other@example.com", Password: "password123"}, testClient); err != nil {
+		t.Errorf("其他帳號不應被鎖定: %v", err)
+	}
+
+	// 管理員解鎖後可以登入
+	if _, err := service.UnlockUser(1); err != nil {
+		t.Fatalf("UnlockUser() 發生錯誤: %v", err)
+	}
+	if _, err := service.Login(right, testClient); err != nil {
+		t.Errorf("解鎖後應該可以登入: %v", err)
+	}
+}
+
+// TestAuthService_Login_LockoutByIP 測試同一 IP 對多個帳號的嘗試
+func TestAuthService_Login_LockoutByIP(t *testing.T) {
+	service := newThrottledAuthService(t)
+
+	// 每個 Email 只失敗一次，不會觸發 Email 鎖定
+	var err error
+	for i := 0; i < 5; i++ {
+		_, err = service.Login(&requests.LoginRequest{Email: "guess" + string(rune('a'+i)) + "@example.com", Password: "x"}, testClient)
+	}
+	var lockout *LockoutError
+	if !errors.As(err, &lockout) {
+		t.Fatalf("同一 IP 第 5 次失敗預期 LockoutError，got %v", err)
+	}
+
+	// 同一 IP 的正確帳密也被擋下，其他 IP 不受影響
+	valid := &requests.LoginRequest{Email: "victim@example.com", Password: "password123"}
+	if _, err := service.Login(valid, testClient); !errors.As(err, &lockout) {
+		t.Errorf("鎖定中的 IP 預期 LockoutError，got %v", err)
+	}
+	if _, err := service.Login(valid, ClientInfo{IP: "198.51.100.7"}); err != nil {
+		t.Errorf("其他 IP 不應被鎖定: %v", err)
+	}
+}
+
+// TestLimiter_ExponentialBackoff 測試鎖定時間指數成長並有上限
+func TestLimiter_ExponentialBackoff(t *testing.T) {
+	limiter := throttle.NewLimiter(throttle.NewMemoryStore(), "test", throttle.Policy{
+		MaxAttempts: 3,
+		BaseLockout: time.Minute,
+		MaxLockout:  5 * time.Minute,
+		Decay:       15 * time.Minute,
+	})
+
+	want := []time.Duration{0, 0, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, expected := range want {
+		attempts, lockout, err := limiter.Fail("key")
+		if err != nil {
+			t.Fatalf("Fail() 發生錯誤: %v", err)
+		}
+		if attempts != i+1 || lockout != expected {
+			t.Errorf("第 %d 次失敗：got (%d, %v), want (%d, %v)", i+1, attempts, lockout, i+1, expected)
+		}
+	}
+
+	retryAfter, _ := limiter.RetryAfter("key")
+	if retryAfter <= 4*time.Minute || retryAfter > 5*time.Minute {
+		t.Errorf("剩餘鎖定時間不符，got %v", retryAfter)
+	}
+
+	limiter.Clear("key")
+	if retryAfter, _ := limiter.RetryAfter("key"); retryAfter != 0 {
+		t.Errorf("Clear() 後不應鎖定，got %v", retryAfter)
+	}
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/pkg/mail"
+	"my-api/app/pkg/password"
+	"my-api/app/repositories"
+	"my-api/app/requests"
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// ErrInvalidPasswordResetToken - 重設密碼 Token 不存在、已過期或已使用
+var ErrInvalidPasswordResetToken = errors.New("無效或已過期的重設密碼連結")
+
+// PasswordService - 忘記密碼 / 重設密碼 / 變更密碼業務邏輯層介面
+type PasswordService interface {
+	ForgotPassword(req *requests.ForgotPasswordRequest) error
+	ResetPassword(req *requests.ResetPasswordRequest) error
+	ChangePassword(userID uint, currentSessionID string, req *requests.ChangePasswordRequest) error
+	SendSetPasswordLink(user *models.User) error
+}
+
+// passwordService - 實作 PasswordService 介面
+type passwordService struct {
+	userRepo    repositories.UserRepository
+	resetRepo   repositories.PasswordResetTokenRepository
+	tokenRepo   repositories.PersonalAccessTokenRepository
+	authService AuthService
+	mailer      mail.Mailer
+}
+
+// NewPasswordService - 建立新的 PasswordService 實例
+func NewPasswordService(
+	userRepo repositories.UserRepository,
+	resetRepo repositories.PasswordResetTokenRepository,
+	tokenRepo repositories.PersonalAccessTokenRepository,
+	authService AuthService,
+	mailer mail.Mailer,
+) PasswordService {
+	return &passwordService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		tokenRepo:   tokenRepo,
+		authService: authService,
+		mailer:      mailer,
+	}
+}
+
+// ForgotPassword - 寄送重設密碼連結
+// Email 不存在時同樣回傳成功，避免被用來探測哪些 Email 有註冊
+func (s *passwordService) ForgotPassword(req *requests.ForgotPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		return nil
+	}
+
+	cfg := config.GlobalConfig.Auth
+	link, err := s.createLink(user, time.Duration(cfg.PasswordResetExpiryMinutes)*time.Minute)
+	if err != nil {
+		return errors.New("寄送重設密碼信件失敗")
+	}
+
+	msg := &mail.Message{
+		To:      user.Email,
+		Subject: "重設密碼",
+		Body: fmt.Sprintf(
+			"%s 您好：\n\n我們收到了重設密碼的申請，請在 %d 分鐘內點擊以下連結設定新密碼：\n\n%s\n\n如果您沒有申請重設密碼，請忽略這封信。\n",
+			user.Name, cfg.PasswordResetExpiryMinutes, link,
+		),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		return errors.New("寄送重設密碼信件失敗")
+	}
+
+	return nil
+}
+
+// SendSetPasswordLink - 寄送設定密碼連結（管理員建立的帳號沒有密碼，由使用者自行設定）
+// 連結與重設密碼相同（PASSWORD_RESET_URL + POST /api/password/reset），有效期為 PASSWORD_SETUP_EXPIRY_HOURS
+func (s *passwordService) SendSetPasswordLink(user *models.User) error {
+	cfg := config.GlobalConfig.Auth
+	link, err := s.createLink(user, time.Duration(cfg.PasswordSetupExpiryHours)*time.Hour)
+	if err != nil {
+		return errors.New("寄送設定密碼信件失敗")
+	}
+
+	msg := &mail.Message{
+		To:      user.Email,
+		Subject: "設定您的密碼",
+		Body: fmt.Sprintf(
+			"%s 您好：\n\n管理員已為您建立帳號，請在 %d 小時內點擊以下連結設定密碼：\n\n%s\n\n連結過期後，可以在登入頁使用「忘記密碼」重新取得。\n",
+			user.Name, cfg.PasswordSetupExpiryHours, link,
+		),
+	}
+	if err := s.mailer.Send(msg); err != nil {
+		return errors.New("寄送設定密碼信件失敗")
+	}
+
+	return nil
+}
+
+// ResetPassword - 使用重設密碼 Token 設定新密碼
+// 成功後 Token 失效，並撤銷使用者所有已登入的 Token（登出所有裝置）
+func (s *passwordService) ResetPassword(req *requests.ResetPasswordRequest) error {
+	stored, err := s.resetRepo.FindByHash(utils.HashToken(req.Token))
+	if err != nil {
+		return ErrInvalidPasswordResetToken
+	}
+
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidPasswordResetToken
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil {
+		return ErrInvalidPasswordResetToken
+	}
+
+	// 密碼不符合規則時不消耗 Token，使用者可以換個密碼再試
+	if err := password.Default().Validate(req.Password, passwordUser(user)); err != nil {
+		return err
+	}
+
+	// 條件式更新，同一個 Token 併發使用時只有一個請求能成功
+	marked, err := s.resetRepo.MarkUsed(stored.ID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return ErrInvalidPasswordResetToken
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return errors.New("密碼加密失敗")
+	}
+
+	user.Password = hashedPassword
+	// 能收到信件中的連結，代表擁有這個 Email
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("重設密碼失敗")
+	}
+
+	// 舊密碼可能已外洩，所有裝置都要重新登入；
+	// 帳號被盜用期間可能被建立 API Token，也一併撤銷
+	if err := s.authService.LogoutAll(user.ID); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.DeleteByUser(user.ID); err != nil {
+		return errors.New("撤銷 API Token 失敗")
+	}
+
+	return nil
+}
+
+// ChangePassword - 已登入的使用者變更密碼（需輸入目前的密碼）
+// 成功後登出其他所有裝置，目前的裝置（currentSessionID）保持登入
+func (s *passwordService) ChangePassword(userID uint, currentSessionID string, req *requests.ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("使用者不存在")
+	}
+
+	if !utils.CheckPassword(req.CurrentPassword, user.Password) {
+		return ErrIncorrectPassword
+	}
+	if req.Password == req.CurrentPassword {
+		return &password.PolicyError{Messages: []string{"新密碼不可與目前的密碼相同"}}
+	}
+	if err := password.Default().Validate(req.Password, passwordUser(user)); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return errors.New("密碼加密失敗")
+	}
+
+	user.Password = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("變更密碼失敗")
+	}
+
+	return s.authService.LogoutOtherSessions(user.ID, currentSessionID)
+}
+
+// createLink - 建立重設 / 設定密碼 Token，回傳信件中的連結
+// 之前寄出的連結全部失效
+func (s *passwordService) createLink(user *models.User, expiry time.Duration) (string, error) {
+	if err := s.resetRepo.DeleteForUser(user.ID); err != nil {
+		return "", err
+	}
+
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	record := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(expiry),
+	}
+	if err := s.resetRepo.Create(record); err != nil {
+		return "", err
+	}
+
+	return config.GlobalConfig.Auth.PasswordResetURL + "?token=" + url.QueryEscape(token), nil
+}
+
+// passwordUser - 密碼規則檢查「不可包含個人資料」時使用的資料
+func passwordUser(user *models.User) password.User {
+	return password.User{Name: user.Name, Email: user.Email}
+}
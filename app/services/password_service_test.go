@@ -0,0 +1,329 @@
+package services
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/pkg/mail"
+	"my-api/app/pkg/password"
+	"my-api/app/requests"
+	"my-api/config"
+)
+
+// ============================================================================
+// Mock Repository
+// ============================================================================
+// mockPasswordResetTokenRepository 實作 PasswordResetTokenRepository interface
+type mockPasswordResetTokenRepository struct {
+	tokens map[uint]*models.PasswordResetToken
+	nextID uint
+}
+
+// newMockPasswordResetTokenRepository 建立新的 mock repository
+func newMockPasswordResetTokenRepository() *mockPasswordResetTokenRepository {
+	return &mockPasswordResetTokenRepository{
+		tokens: make(map[uint]*models.PasswordResetToken),
+		nextID: 1,
+	}
+}
+
+func (m *mockPasswordResetTokenRepository) Create(token *models.PasswordResetToken) error {
+	token.ID = m.nextID
+	m.nextID++
+	m.tokens[token.ID] = token
+	return nil
+}
+
+func (m *mockPasswordResetTokenRepository) FindByHash(hash string) (*models.PasswordResetToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockPasswordResetTokenRepository) MarkUsed(id uint) (bool, error) {
+	token, ok := m.tokens[id]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	return true, nil
+}
+
+func (m *mockPasswordResetTokenRepository) DeleteForUser(userID uint) error {
+	for id, token := range m.tokens {
+		if token.UserID == userID {
+			delete(m.tokens, id)
+		}
+	}
+	return nil
+}
+
+// passwordTestEnv 重設密碼測試所需的依賴
+type passwordTestEnv struct {
+	*testServices
+	service PasswordService
+	auth    AuthService
+}
+
+// newPasswordTestEnv 建立測試環境，並預先建立一個可登入的使用者
+func newPasswordTestEnv(t *testing.T) *passwordTestEnv {
+	t.Helper()
+	setupTestConfig()
+	config.GlobalConfig.Auth = config.AuthConfig{
+		PasswordResetURL:           "https://app.example.com/reset-password",
+		PasswordResetExpiryMinutes: 60,
+		PasswordSetupExpiryHours:   72,
+	}
+
+	deps := newTestServices(newMockUserRepository())
+	createTestUser(t, deps.userRepo, &models.User{Email: "reset@example.com"})
+	return &passwordTestEnv{testServices: deps, service: deps.passwordService(), auth: deps.authService()}
+}
+
+// tokenFromMail 從信件中的連結取出 Token
+func tokenFromMail(t *testing.T, msg *mail.Message) string {
+	t.Helper()
+	if msg == nil {
+		t.Fatal("應該寄出重設密碼信件")
+	}
+
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if strings.HasPrefix(line, "https://app.example.com/reset-password?") {
+			link, err := url.Parse(line)
+			if err != nil {
+				t.Fatalf("連結格式錯誤: %v", err)
+			}
+			return link.Query().Get("token")
+		}
+	}
+	t.Fatalf("信件中找不到重設連結: %s", msg.Body)
+	return ""
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestPasswordService_ResetFlow 測試完整的忘記密碼 → 重設密碼流程
+func TestPasswordService_ResetFlow(t *testing.T) {
+	env := newPasswordTestEnv(t)
+
+	login, err := env.auth.Login(&requests.LoginRequest{Email: "reset@example.com", Password: "password123"}, testClient)
+	if err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+
+	// 帳號被盜用期間建立的 API Token
+	env.tokenRepo.Create(&models.PersonalAccessToken{UserID: login.User.ID, Name: "CI", TokenHash: "hash"})
+
+	if err := env.service.ForgotPassword(&requests.ForgotPasswordRequest{Email: "reset@example.com"}); err != nil {
+		t.Fatalf("ForgotPassword() 發生錯誤: %v", err)
+	}
+	token := tokenFromMail(t, env.mailer.Last())
+
+	// 資料庫只能存雜湊
+	for _, stored := range env.resetRepo.tokens {
+		if stored.TokenHash == token {
+			t.Error("重設密碼 Token 不應以明文儲存")
+		}
+	}
+
+	reset := &requests.ResetPasswordRequest{Token: token, Password: "new-password", PasswordConfirm: "new-password"}
+	if err := env.service.ResetPassword(reset); err != nil {
+		t.Fatalf("ResetPassword() 發生錯誤: %v", err)
+	}
+
+	// 新密碼可以登入，舊密碼不行
+	if _, err := env.auth.Login(&requests.LoginRequest{Email: "reset@example.com", Password: "new-password"}, testClient); err != nil {
+		t.Errorf("新密碼應該可以登入: %v", err)
+	}
+	if _, err := env.auth.Login(&requests.LoginRequest{Email: "reset@example.com", Password: "password123"}, testClient); err == nil {
+		t.Error("舊密碼不應該可以登入")
+	}
+
+	// 重設前的登入全部失效
+	if _, err := env.auth.Authenticate(login.AccessToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("預期 ErrTokenRevoked，got %v", err)
+	}
+	if _, err := env.auth.Refresh(&requests.RefreshTokenRequest{RefreshToken: login.RefreshToken}); err == nil {
+		t.Error("重設前的 Refresh Token 應該失效")
+	}
+	if tokens, _ := env.tokenRepo.FindByUser(login.User.ID); len(tokens) != 0 {
+		t.Errorf("重設密碼後 API Token 應全部撤銷，剩下 %d 個", len(tokens))
+	}
+
+	// Token 只能使用一次
+	if err := env.service.ResetPassword(reset); !errors.Is(err, ErrInvalidPasswordResetToken) {
+		t.Errorf("預期 ErrInvalidPasswordResetToken，got %v", err)
+	}
+}
+
+// TestPasswordService_ForgotPassword_UnknownEmail 測試不存在的 Email 不寄信也不回傳錯誤
+func TestPasswordService_ForgotPassword_UnknownEmail(t *testing.T) {
+	env := newPasswordTestEnv(t)
+
+	if err := env.service.ForgotPassword(&requests.ForgotPasswordRequest{Email: "nobody@example.com"}); err != nil {
+		t.Fatalf("不存在的 Email 不應回傳錯誤: %v", err)
+	}
+	if len(env.mailer.Messages()) != 0 {
+		t.Error("不存在的 Email 不應寄信")
+	}
+}
+
+// TestPasswordService_ResetPassword_Invalid 測試過期或被取代的 Token
+func TestPasswordService_ResetPassword_Invalid(t *testing.T) {
+	env := newPasswordTestEnv(t)
+	forgot := &requests.ForgotPasswordRequest{Email: "reset@example.com"}
+
+	// 重新申請後，舊連結失效
+	env.service.ForgotPassword(forgot)
+	first := tokenFromMail(t, env.mailer.Last())
+	env.service.ForgotPassword(forgot)
+	second := tokenFromMail(t, env.mailer.Last())
+
+	// 讓最新的 Token 過期
+	for _, stored := range env.resetRepo.tokens {
+		stored.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "不存在的 Token", token: "not-a-real-token"},
+		{name: "已被取代的 Token", token: first},
+		{name: "已過期的 Token", token: second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := env.service.ResetPassword(&requests.ResetPasswordRequest{
+				Token:           tt.token,
+				Password:        "new-password",
+				PasswordConfirm: "new-password",
+			})
+			if !errors.Is(err, ErrInvalidPasswordResetToken) {
+				t.Errorf("預期 ErrInvalidPasswordResetToken，got %v", err)
+			}
+		})
+	}
+}
+
+// TestPasswordService_ResetPassword_Policy 測試新密碼不符合規則時不消耗 Token
+func TestPasswordService_ResetPassword_Policy(t *testing.T) {
+	env := newPasswordTestEnv(t)
+
+	env.service.ForgotPassword(&requests.ForgotPasswordRequest{Email: "reset@example.com"})
+	token := tokenFromMail(t, env.mailer.Last())
+
+	err := env.service.ResetPassword(&requests.ResetPasswordRequest{Token: token, Password: "short", PasswordConfirm: "short"})
+	var policyErr *password.PolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("預期 *password.PolicyError，got %v", err)
+	}
+
+	if err := env.service.ResetPassword(&requests.ResetPasswordRequest{Token: token, Password: "new-password", PasswordConfirm: "new-password"}); err != nil {
+		t.Errorf("Token 應該還能使用: %v", err)
+	}
+}
+
+// TestPasswordService_ChangePassword 測試變更密碼：需要目前的密碼，成功後只保留目前的裝置
+func TestPasswordService_ChangePassword(t *testing.T) {
+	env := newPasswordTestEnv(t)
+
+	login := func() string {
+		resp, err := env.auth.Login(&requests.LoginRequest{Email: "reset@example.com", Password: "password123"}, testClient)
+		if err != nil {
+			t.Fatalf("Login() 發生錯誤: %v", err)
+		}
+		return resp.AccessToken
+	}
+	currentToken := login()
+	otherToken := login()
+
+	current, err := env.auth.Authenticate(currentToken)
+	if err != nil {
+		t.Fatalf("Authenticate() 發生錯誤: %v", err)
+	}
+
+	change := func(currentPassword, newPassword string) error {
+		return env.service.ChangePassword(current.UserID, current.SessionID, &requests.ChangePasswordRequest{
+			CurrentPassword: currentPassword,
+			Password:        newPassword,
+			PasswordConfirm: newPassword,
+		})
+	}
+
+	if err := change("wrong-password", "new-password"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("預期 ErrIncorrectPassword，got %v", err)
+	}
+
+	var policyErr *password.PolicyError
+	if err := change("password123", "password123"); !errors.As(err, &policyErr) {
+		t.Errorf("新密碼與目前的密碼相同應該失敗，got %v", err)
+	}
+	if err := change("password123", "short"); !errors.As(err, &policyErr) {
+		t.Errorf("不符合密碼規則應該失敗，got %v", err)
+	}
+
+	if err := change("password123", "new-password"); err != nil {
+		t.Fatalf("ChangePassword() 發生錯誤: %v", err)
+	}
+
+	if _, err := env.auth.Login(&requests.LoginRequest{Email: "reset@example.com", Password: "new-password"}, testClient); err != nil {
+		t.Errorf("新密碼應該可以登入: %v", err)
+	}
+
+	// 目前的裝置保持登入，其他裝置被登出
+	if _, err := env.auth.Authenticate(currentToken); err != nil {
+		t.Errorf("目前的裝置不應被登出: %v", err)
+	}
+	if _, err := env.auth.Authenticate(otherToken); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("其他裝置應該被登出，got %v", err)
+	}
+}
+
+// TestPasswordService_SetPasswordLink 測試管理員建立的帳號需透過信件設定密碼才能登入
+func TestPasswordService_SetPasswordLink(t *testing.T) {
+	env := newPasswordTestEnv(t)
+	users := env.userService()
+
+	created, err := users.CreateUser(&requests.CreateUserRequest{Name: "王小明", Email: "invited@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser() 發生錯誤: %v", err)
+	}
+
+	msg := env.mailer.Last()
+	if msg == nil || msg.To != "invited@example.com" || msg.Subject != "設定您的密碼" {
+		t.Fatalf("應該寄出設定密碼信件，got %+v", msg)
+	}
+	for _, stored := range env.resetRepo.tokens {
+		if remaining := time.Until(stored.ExpiresAt); remaining < 71*time.Hour {
+			t.Errorf("設定密碼連結應依 PasswordSetupExpiryHours 設定有效期，got %s", remaining)
+		}
+	}
+
+	if err := env.service.ResetPassword(&requests.ResetPasswordRequest{
+		Token:           tokenFromMail(t, msg),
+		Password:        "my-first-password",
+		PasswordConfirm: "my-first-password",
+	}); err != nil {
+		t.Fatalf("ResetPassword() 發生錯誤: %v", err)
+	}
+
+	if _, err := env.auth.Login(&requests.LoginRequest{Email: "invited@example.com", Password: "my-first-password"}, testClient); err != nil {
+		t.Errorf("設定密碼後應該可以登入: %v", err)
+	}
+	if user, _ := env.userRepo.FindByID(created.ID); user.EmailVerifiedAt == nil {
+		t.Error("透過信件連結設定密碼後，Email 應視為已驗證")
+	}
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/repositories"
+	"my-api/app/requests"
+	"my-api/app/responses"
+	"my-api/app/utils"
+)
+
+// PersonalAccessTokenPrefix - 個人存取 Token 的前綴
+// AuthMiddleware 以前綴區分 Authorization: Bearer 後面是 JWT 還是 API Token
+const PersonalAccessTokenPrefix = "pat_"
+
+// lastUsedInterval - last_used_at 最多多久更新一次
+const lastUsedInterval = time.Minute
+
+var (
+	// ErrInvalidAccessToken - API Token 不存在或已過期
+	ErrInvalidAccessToken = errors.New("無效或已過期的 API Token")
+	// ErrInvalidTokenScope - 不存在的 Scope，或使用者本身沒有該權限
+	ErrInvalidTokenScope = errors.New("無效的 Token Scope")
+	// ErrAccessTokenNotFound - 要撤銷的 Token 不存在（或不屬於該使用者）
+	ErrAccessTokenNotFound = errors.New("Token 不存在")
+)
+
+// tokenOnlyScopes - 不對應權限、Token 專用的 Scope
+var tokenOnlyScopes = []string{models.ScopeAll, models.ScopePostsRead, models.ScopePostsWrite}
+
+// PersonalAccessTokenService - 個人存取 Token 業務邏輯層介面
+type PersonalAccessTokenService interface {
+	Create(userID uint, req *requests.CreatePersonalAccessTokenRequest) (*responses.NewAccessTokenResponse, error)
+	List(userID uint) ([]responses.PersonalAccessTokenResponse, error)
+	Revoke(userID, id uint) error
+	RevokeAll(userID uint) error
+	Authenticate(plainTextToken string) (*models.PersonalAccessToken, error)
+}
+
+// personalAccessTokenService - 實作 PersonalAccessTokenService 介面
+type personalAccessTokenService struct {
+	tokenRepo   repositories.PersonalAccessTokenRepository
+	userRepo    repositories.UserRepository
+	roleService RoleService
+}
+
+// NewPersonalAccessTokenService - 建立新的 PersonalAccessTokenService 實例
+func NewPersonalAccessTokenService(
+	tokenRepo repositories.PersonalAccessTokenRepository,
+	userRepo repositories.UserRepository,
+	roleService RoleService,
+) PersonalAccessTokenService {
+	return &personalAccessTokenService{
+		tokenRepo:   tokenRepo,
+		userRepo:    userRepo,
+		roleService: roleService,
+	}
+}
+
+// Create - 建立個人存取 Token
+// Scope 只能是 Token 專用的 Scope 或使用者目前擁有的權限，不能藉由 Token 取得額外權限
+func (s *personalAccessTokenService) Create(userID uint, req *requests.CreatePersonalAccessTokenRequest) (*responses.NewAccessTokenResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("使用者不存在")
+	}
+
+	permissions, err := s.roleService.PermissionsForRoles(user.RoleNames())
+	if err != nil {
+		return nil, errors.New("取得權限失敗")
+	}
+
+	scopes := uniqueStrings(req.Scopes)
+	for _, scope := range scopes {
+		if !containsString(tokenOnlyScopes, scope) && !containsString(permissions, scope) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTokenScope, scope)
+		}
+	}
+
+	random, err := utils.GenerateRandomToken()
+	if err != nil {
+		return nil, errors.New("產生 Token 失敗")
+	}
+	plainTextToken := PersonalAccessTokenPrefix + random
+
+	token := &models.PersonalAccessToken{
+		UserID:    user.ID,
+		Name:      req.Name,
+		TokenHash: utils.HashToken(plainTextToken),
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.tokenRepo.Create(token); err != nil {
+		return nil, errors.New("建立 Token 失敗")
+	}
+
+	return &responses.NewAccessTokenResponse{
+		PlainTextToken: plainTextToken,
+		AccessToken:    responses.NewPersonalAccessTokenResponse(token),
+	}, nil
+}
+
+// List - 取得使用者的所有 Token
+func (s *personalAccessTokenService) List(userID uint) ([]responses.PersonalAccessTokenResponse, error) {
+	tokens, err := s.tokenRepo.FindByUser(userID)
+	if err != nil {
+		return nil, errors.New("取得 Token 列表失敗")
+	}
+
+	result := make([]responses.PersonalAccessTokenResponse, 0, len(tokens))
+	for i := range tokens {
+		result = append(result, *responses.NewPersonalAccessTokenResponse(&tokens[i]))
+	}
+	return result, nil
+}
+
+// Revoke - 撤銷（刪除）使用者自己的 Token
+func (s *personalAccessTokenService) Revoke(userID, id uint) error {
+	deleted, err := s.tokenRepo.Delete(userID, id)
+	if err != nil {
+		return errors.New("撤銷 Token 失敗")
+	}
+	if !deleted {
+		return ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+// RevokeAll - 撤銷使用者所有的 Token（登出所有裝置時可選擇一併撤銷）
+func (s *personalAccessTokenService) RevokeAll(userID uint) error {
+	if err := s.tokenRepo.DeleteByUser(userID); err != nil {
+		return errors.New("撤銷 Token 失敗")
+	}
+	return nil
+}
+
+// Authenticate - 驗證 API Token，回傳 Token（含使用者與角色）
+func (s *personalAccessTokenService) Authenticate(plainTextToken string) (*models.PersonalAccessToken, error) {
+	if !strings.HasPrefix(plainTextToken, PersonalAccessTokenPrefix) {
+		return nil, ErrInvalidAccessToken
+	}
+
+	token, err := s.tokenRepo.FindByHash(utils.HashToken(plainTextToken))
+	if err != nil || token.User == nil {
+		return nil, ErrInvalidAccessToken
+	}
+	if token.IsExpired() {
+		return nil, ErrInvalidAccessToken
+	}
+
+	// last_used_at 只是參考資訊，更新失敗不影響這次請求
+	now := time.Now()
+	_ = s.tokenRepo.TouchLastUsed(token.ID, now, lastUsedInterval)
+
+	return token, nil
+}
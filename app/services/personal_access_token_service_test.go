@@ -0,0 +1,262 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/requests"
+	"my-api/app/responses"
+)
+
+// ============================================================================
+// Mock Repository
+// ============================================================================
+// mockPersonalAccessTokenRepository 實作 PersonalAccessTokenRepository interface
+type mockPersonalAccessTokenRepository struct {
+	tokens   map[uint]*models.PersonalAccessToken
+	userRepo *mockUserRepository
+	nextID   uint
+}
+
+// newMockPersonalAccessTokenRepository 建立新的 mock repository
+func newMockPersonalAccessTokenRepository(userRepo *mockUserRepository) *mockPersonalAccessTokenRepository {
+	return &mockPersonalAccessTokenRepository{
+		tokens:   make(map[uint]*models.PersonalAccessToken),
+		userRepo: userRepo,
+		nextID:   1,
+	}
+}
+
+func (m *mockPersonalAccessTokenRepository) Create(token *models.PersonalAccessToken) error {
+	token.ID = m.nextID
+	token.CreatedAt = time.Now()
+	m.nextID++
+	m.tokens[token.ID] = token
+	return nil
+}
+
+func (m *mockPersonalAccessTokenRepository) FindByHash(hash string) (*models.PersonalAccessToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			copied.User, _ = m.userRepo.FindByID(token.UserID)
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockPersonalAccessTokenRepository) FindByUser(userID uint) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	for _, token := range m.tokens {
+		if token.UserID == userID {
+			tokens = append(tokens, *token)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *mockPersonalAccessTokenRepository) Delete(userID, id uint) (bool, error) {
+	token, ok := m.tokens[id]
+	if !ok || token.UserID != userID {
+		return false, nil
+	}
+	delete(m.tokens, id)
+	return true, nil
+}
+
+func (m *mockPersonalAccessTokenRepository) TouchLastUsed(id uint, usedAt time.Time, interval time.Duration) error {
+	token, ok := m.tokens[id]
+	if !ok {
+		return nil
+	}
+	if token.LastUsedAt == nil || token.LastUsedAt.Before(usedAt.Add(-interval)) {
+		token.LastUsedAt = &usedAt
+	}
+	return nil
+}
+
+func (m *mockPersonalAccessTokenRepository) DeleteByUser(userID uint) error {
+	for id, token := range m.tokens {
+		if token.UserID == userID {
+			delete(m.tokens, id)
+		}
+	}
+	return nil
+}
+
+// accessTokenTestEnv 個人存取 Token 測試所需的依賴
+type accessTokenTestEnv struct {
+	service   PersonalAccessTokenService
+	tokenRepo *mockPersonalAccessTokenRepository
+	user      *models.User // 一般使用者（user 角色，沒有任何權限）
+	admin     *models.User // 管理員（admin 角色，擁有全部權限）
+}
+
+// newAccessTokenTestEnv 建立測試環境，預先建立一般使用者與管理員
+func newAccessTokenTestEnv() *accessTokenTestEnv {
+	deps := newTestServices(newMockUserRepository())
+
+	user := &models.User{Name: "一般使用者", Email: "user@example.com", Roles: []models.Role{{Name: models.RoleUser}}}
+	admin := &models.User{Name: "管理員", Email: "admin@example.com", Roles: []models.Role{{Name: models.RoleAdmin}}}
+	deps.userRepo.Create(user)
+	deps.userRepo.Create(admin)
+
+	return &accessTokenTestEnv{
+		service:   NewPersonalAccessTokenService(deps.tokenRepo, deps.userRepo, NewRoleService(deps.roleRepo, deps.userRepo)),
+		tokenRepo: deps.tokenRepo,
+		user:      user,
+		admin:     admin,
+	}
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestPersonalAccessTokenService_CreateAndAuthenticate 測試建立 Token 後可以用來驗證
+func TestPersonalAccessTokenService_CreateAndAuthenticate(t *testing.T) {
+	env := newAccessTokenTestEnv()
+
+	created, err := env.service.Create(env.user.ID, &requests.CreatePersonalAccessTokenRequest{
+		Name:   "CI",
+		Scopes: []string{"posts.read", "posts.read"},
+	})
+	if err != nil {
+		t.Fatalf("Create() 發生錯誤: %v", err)
+	}
+
+	if !strings.HasPrefix(created.PlainTextToken, PersonalAccessTokenPrefix) {
+		t.Errorf("Token 應該以 %s 開頭: %s", PersonalAccessTokenPrefix, created.PlainTextToken)
+	}
+	if len(created.AccessToken.Scopes) != 1 {
+		t.Errorf("重複的 Scope 應該合併: %v", created.AccessToken.Scopes)
+	}
+
+	// 資料庫只能存雜湊
+	stored := env.tokenRepo.tokens[created.AccessToken.ID]
+	if stored.TokenHash == created.PlainTextToken || strings.Contains(stored.TokenHash, PersonalAccessTokenPrefix) {
+		t.Error("Token 不應以明文儲存")
+	}
+
+	token, err := env.service.Authenticate(created.PlainTextToken)
+	if err != nil {
+		t.Fatalf("Authenticate() 發生錯誤: %v", err)
+	}
+	if token.UserID != env.user.ID || token.User == nil {
+		t.Errorf("Token 應該屬於使用者 %d: %+v", env.user.ID, token)
+	}
+	if !token.Can("posts.read") || token.Can("posts.write") {
+		t.Errorf("Scope 判斷錯誤: %v", token.Scopes)
+	}
+	if stored.LastUsedAt == nil {
+		t.Error("驗證成功後應該更新 last_used_at")
+	}
+}
+
+// TestPersonalAccessTokenService_Scopes 測試 Scope 不能超過使用者本身的權限
+func TestPersonalAccessTokenService_Scopes(t *testing.T) {
+	env := newAccessTokenTestEnv()
+
+	tests := []struct {
+		name    string
+		userID  uint
+		scopes  []string
+		wantErr bool
+	}{
+		{name: "Token 專用 Scope", userID: env.user.ID, scopes: []string{"posts.read", "posts.write"}},
+		{name: "全部權限", userID: env.user.ID, scopes: []string{"*"}},
+		{name: "管理員的權限", userID: env.admin.ID, scopes: []string{"users.view", "roles.manage"}},
+		{name: "一般使用者沒有的權限", userID: env.user.ID, scopes: []string{"users.view"}, wantErr: true},
+		{name: "不存在的 Scope", userID: env.admin.ID, scopes: []string{"servers.reboot"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.service.Create(tt.userID, &requests.CreatePersonalAccessTokenRequest{Name: tt.name, Scopes: tt.scopes})
+			if tt.wantErr && !errors.Is(err, ErrInvalidTokenScope) {
+				t.Errorf("預期 ErrInvalidTokenScope，got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("不預期的錯誤: %v", err)
+			}
+		})
+	}
+}
+
+// TestPersonalAccessTokenService_Authenticate_Invalid 測試無效、過期與已撤銷的 Token
+func TestPersonalAccessTokenService_Authenticate_Invalid(t *testing.T) {
+	env := newAccessTokenTestEnv()
+
+	expiresAt := time.Now().Add(time.Hour)
+	expired, _ := env.service.Create(env.user.ID, &requests.CreatePersonalAccessTokenRequest{Name: "expired", Scopes: []string{"*"}, ExpiresAt: &expiresAt})
+	past := time.Now().Add(-time.Minute)
+	env.tokenRepo.tokens[expired.AccessToken.ID].ExpiresAt = &past
+
+	revoked, _ := env.service.Create(env.user.ID, &requests.CreatePersonalAccessTokenRequest{Name: "revoked", Scopes: []string{"*"}})
+	if err := env.service.Revoke(env.user.ID, revoked.AccessToken.ID); err != nil {
+		t.Fatalf("Revoke() 發生錯誤: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "不存在的 Token", token: PersonalAccessTokenPrefix + "not-a-real-token"},
+		{name: "沒有前綴", token: strings.TrimPrefix(expired.PlainTextToken, PersonalAccessTokenPrefix)},
+		{name: "已過期", token: expired.PlainTextToken},
+		{name: "已撤銷", token: revoked.PlainTextToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := env.service.Authenticate(tt.token); !errors.Is(err, ErrInvalidAccessToken) {
+				t.Errorf("預期 ErrInvalidAccessToken，got %v", err)
+			}
+		})
+	}
+}
+
+// TestPersonalAccessTokenService_Revoke_OtherUser 測試不能撤銷別人的 Token
+func TestPersonalAccessTokenService_Revoke_OtherUser(t *testing.T) {
+	env := newAccessTokenTestEnv()
+
+	created, _ := env.service.Create(env.user.ID, &requests.CreatePersonalAccessTokenRequest{Name: "CI", Scopes: []string{"*"}})
+
+	if err := env.service.Revoke(env.admin.ID, created.AccessToken.ID); !errors.Is(err, ErrAccessTokenNotFound) {
+		t.Errorf("預期 ErrAccessTokenNotFound，got %v", err)
+	}
+	if _, err := env.service.Authenticate(created.PlainTextToken); err != nil {
+		t.Errorf("Token 不應被其他使用者撤銷: %v", err)
+	}
+
+	tokens, _ := env.service.List(env.admin.ID)
+	if len(tokens) != 0 {
+		t.Errorf("不應列出其他使用者的 Token: %+v", tokens)
+	}
+}
+
+// TestPersonalAccessTokenService_RevokeAll 測試撤銷使用者所有的 Token，不影響其他使用者
+func TestPersonalAccessTokenService_RevokeAll(t *testing.T) {
+	env := newAccessTokenTestEnv()
+
+	first, _ := env.service.Create(env.user.ID, &requests.CreatePersonalAccessTokenRequest{Name: "CI", Scopes: []string{"*"}})
+	second, _ := env.service.Create(env.user.ID, &requests.CreatePersonalAccessTokenRequest{Name: "Deploy", Scopes: []string{"posts.read"}})
+	other, _ := env.service.Create(env.admin.ID, &requests.CreatePersonalAccessTokenRequest{Name: "CI", Scopes: []string{"*"}})
+
+	if err := env.service.RevokeAll(env.user.ID); err != nil {
+		t.Fatalf("RevokeAll() 發生錯誤: %v", err)
+	}
+
+	for _, created := range []*responses.NewAccessTokenResponse{first, second} {
+		if _, err := env.service.Authenticate(created.PlainTextToken); !errors.Is(err, ErrInvalidAccessToken) {
+			t.Errorf("預期 ErrInvalidAccessToken，got %v", err)
+		}
+	}
+	if _, err := env.service.Authenticate(other.PlainTextToken); err != nil {
+		t.Errorf("其他使用者的 Token 不應被撤銷: %v", err)
+	}
+}
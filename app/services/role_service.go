@@ -0,0 +1,324 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/repositories"
+	"my-api/app/requests"
+	"my-api/app/responses"
+)
+
+// 角色權限快取的有效時間
+// 多台機器部署時，其他機器上的角色異動最多延遲這麼久才生效
+const permissionCacheTTL = time.Minute
+
+// RoleService - 角色權限業務邏輯層介面
+type RoleService interface {
+	GetAllRoles() ([]responses.RoleResponse, error)
+	GetRoleByID(id uint) (*responses.RoleResponse, error)
+	CreateRole(req *requests.CreateRoleRequest) (*responses.RoleResponse, error)
+	UpdateRole(id uint, req *requests.UpdateRoleRequest) (*responses.RoleResponse, error)
+	DeleteRole(id uint) error
+	SyncRolePermissions(id uint, req *requests.SyncPermissionsRequest) (*responses.RoleResponse, error)
+	GetAllPermissions() ([]responses.PermissionResponse, error)
+	SyncUserRoles(userID uint, req *requests.SyncRolesRequest) ([]string, error)
+	PermissionsForRoles(roleNames []string) ([]string, error)
+}
+
+// roleService - 實作 RoleService 介面
+type roleService struct {
+	roleRepo repositories.RoleRepository
+	userRepo repositories.UserRepository
+
+	// 角色 → 權限名稱 快取（AuthMiddleware 每個請求都會查詢）
+	cacheMu sync.RWMutex
+	cache   map[string]cachedPermissions
+}
+
+type cachedPermissions struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+// NewRoleService - 建立新的 RoleService 實例
+func NewRoleService(roleRepo repositories.RoleRepository, userRepo repositories.UserRepository) RoleService {
+	return &roleService{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		cache:    make(map[string]cachedPermissions),
+	}
+}
+
+// GetAllRoles - 取得所有角色
+func (s *roleService) GetAllRoles() ([]responses.RoleResponse, error) {
+	roles, err := s.roleRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	roleResponses := make([]responses.RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		roleResponses = append(roleResponses, *responses.NewRoleResponse(&role))
+	}
+
+	return roleResponses, nil
+}
+
+// GetRoleByID - 根據 ID 取得角色
+func (s *roleService) GetRoleByID(id uint) (*responses.RoleResponse, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("角色不存在")
+	}
+
+	return responses.NewRoleResponse(role), nil
+}
+
+// CreateRole - 新增角色
+func (s *roleService) CreateRole(req *requests.CreateRoleRequest) (*responses.RoleResponse, error) {
+	existing, _ := s.roleRepo.FindByName(req.Name)
+	if existing != nil {
+		return nil, errors.New("角色名稱已存在")
+	}
+
+	permissions, err := s.findPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+
+	s.flushCache()
+	return responses.NewRoleResponse(role), nil
+}
+
+// UpdateRole - 更新角色
+func (s *roleService) UpdateRole(id uint, req *requests.UpdateRoleRequest) (*responses.RoleResponse, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("角色不存在")
+	}
+
+	if req.Name != "" && req.Name != role.Name {
+		if isBuiltinRole(role.Name) {
+			return nil, errors.New("內建角色不可改名")
+		}
+		existing, _ := s.roleRepo.FindByName(req.Name)
+		if existing != nil {
+			return nil, errors.New("角色名稱已存在")
+		}
+		role.Name = req.Name
+	}
+	if req.Description != "" {
+		role.Description = req.Description
+	}
+
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, err
+	}
+
+	s.flushCache()
+	return responses.NewRoleResponse(role), nil
+}
+
+// DeleteRole - 刪除角色
+func (s *roleService) DeleteRole(id uint) error {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return errors.New("角色不存在")
+	}
+
+	if isBuiltinRole(role.Name) {
+		return errors.New("內建角色不可刪除")
+	}
+
+	if err := s.roleRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.flushCache()
+	return nil
+}
+
+// SyncRolePermissions - 設定角色的權限（整批取代）
+func (s *roleService) SyncRolePermissions(id uint, req *requests.SyncPermissionsRequest) (*responses.RoleResponse, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("角色不存在")
+	}
+
+	permissions, err := s.findPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.roleRepo.SyncPermissions(role, permissions); err != nil {
+		return nil, err
+	}
+	role.Permissions = permissions
+
+	s.flushCache()
+	return responses.NewRoleResponse(role), nil
+}
+
+// GetAllPermissions - 取得所有權限
+func (s *roleService) GetAllPermissions() ([]responses.PermissionResponse, error) {
+	permissions, err := s.roleRepo.FindAllPermissions()
+	if err != nil {
+		return nil, err
+	}
+
+	permissionResponses := make([]responses.PermissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		permissionResponses = append(permissionResponses, *responses.NewPermissionResponse(&permission))
+	}
+
+	return permissionResponses, nil
+}
+
+// SyncUserRoles - 設定使用者的角色（整批取代）
+// 新角色會在使用者下次取得 Token（登入或刷新）時寫入 JWT
+func (s *roleService) SyncUserRoles(userID uint, req *requests.SyncRolesRequest) ([]string, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, errors.New("使用者不存在")
+	}
+
+	roles, err := s.roleRepo.FindByNames(req.Roles)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) != len(uniqueStrings(req.Roles)) {
+		return nil, errors.New("包含不存在的角色")
+	}
+
+	// 避免把最後一位管理員降級，導致沒有人能管理角色
+	if !containsString(req.Roles, models.RoleAdmin) {
+		last, err := isLastAdmin(s.roleRepo, userID)
+		if err != nil {
+			return nil, err
+		}
+		if last {
+			return nil, errors.New("不可移除最後一位管理員的 admin 角色")
+		}
+	}
+
+	if err := s.roleRepo.SyncUserRoles(userID, roles); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// isLastAdmin - 使用者是否為系統中唯一的管理員（降級或刪除前檢查）
+func isLastAdmin(roleRepo repositories.RoleRepository, userID uint) (bool, error) {
+	current, err := roleRepo.RoleNamesForUser(userID)
+	if err != nil {
+		return false, err
+	}
+	if !containsString(current, models.RoleAdmin) {
+		return false, nil
+	}
+	count, err := roleRepo.CountUsersWithRole(models.RoleAdmin)
+	if err != nil {
+		return false, err
+	}
+	return count <= 1, nil
+}
+
+// PermissionsForRoles - 取得角色合併後的權限名稱（含快取）
+func (s *roleService) PermissionsForRoles(roleNames []string) ([]string, error) {
+	var permissions []string
+	var missing []string
+
+	s.cacheMu.RLock()
+	now := time.Now()
+	for _, name := range roleNames {
+		cached, ok := s.cache[name]
+		if !ok || now.After(cached.expiresAt) {
+			missing = append(missing, name)
+			continue
+		}
+		permissions = append(permissions, cached.permissions...)
+	}
+	s.cacheMu.RUnlock()
+
+	for _, name := range missing {
+		names, err := s.roleRepo.PermissionNamesForRoles([]string{name})
+		if err != nil {
+			return nil, err
+		}
+
+		s.cacheMu.Lock()
+		s.cache[name] = cachedPermissions{permissions: names, expiresAt: time.Now().Add(permissionCacheTTL)}
+		s.cacheMu.Unlock()
+
+		permissions = append(permissions, names...)
+	}
+
+	return uniqueStrings(permissions), nil
+}
+
+// findPermissions - 根據名稱取得權限，名稱不存在時回傳錯誤
+func (s *roleService) findPermissions(names []string) ([]models.Permission, error) {
+	permissions, err := s.roleRepo.FindPermissionsByNames(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(permissions) != len(uniqueStrings(names)) {
+		return nil, errors.New("包含不存在的權限")
+	}
+	return permissions, nil
+}
+
+// flushCache - 角色或權限異動後清除快取
+func (s *roleService) flushCache() {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	s.cache = make(map[string]cachedPermissions)
+}
+
+// isBuiltinRole - 是否為系統內建角色
+func isBuiltinRole(name string) bool {
+	return name == models.RoleAdmin || name == models.RoleUser
+}
+
+// uniqueStrings - 去除重複字串並排序
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// containsString - 檢查字串是否在列表中
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"my-api/app/models"
+	"my-api/app/requests"
+)
+
+// ============================================================================
+// Mock Repository
+// ============================================================================
+// mockRoleRepository 實作 RoleRepository interface
+type mockRoleRepository struct {
+	roles       map[uint]*models.Role
+	permissions map[string]*models.Permission
+	userRoles   map[uint][]string // userID → 角色名稱
+	nextID      uint
+	queries     int // PermissionNamesForRoles 被呼叫的次數（驗證快取）
+}
+
+// newMockRoleRepository 建立 mock repository，預先寫入與 migration 相同的預設資料
+func newMockRoleRepository() *mockRoleRepository {
+	m := &mockRoleRepository{
+		roles:       make(map[uint]*models.Role),
+		permissions: make(map[string]*models.Permission),
+		userRoles:   make(map[uint][]string),
+		nextID:      1,
+	}
+
+	var all []models.Permission
+	for i, name := range []string{"users.view", "users.create", "users.update", "users.delete", "roles.manage", "posts.manage"} {
+		permission := &models.Permission{ID: uint(i + 1), Name: name}
+		m.permissions[name] = permission
+		all = append(all, *permission)
+	}
+	m.Create(&models.Role{Name: models.RoleAdmin, Permissions: all})
+	m.Create(&models.Role{Name: models.RoleUser})
+
+	return m
+}
+
+func (m *mockRoleRepository) Create(role *models.Role) error {
+	role.ID = m.nextID
+	m.nextID++
+	m.roles[role.ID] = role
+	return nil
+}
+
+func (m *mockRoleRepository) FindAll() ([]models.Role, error) {
+	var roles []models.Role
+	for _, role := range m.roles {
+		roles = append(roles, *role)
+	}
+	return roles, nil
+}
+
+func (m *mockRoleRepository) FindByID(id uint) (*models.Role, error) {
+	if role, ok := m.roles[id]; ok {
+		return role, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockRoleRepository) FindByName(name string) (*models.Role, error) {
+	for _, role := range m.roles {
+		if role.Name == name {
+			return role, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockRoleRepository) FindByNames(names []string) ([]models.Role, error) {
+	var roles []models.Role
+	for _, name := range uniqueStrings(names) {
+		if role, err := m.FindByName(name); err == nil {
+			roles = append(roles, *role)
+		}
+	}
+	return roles, nil
+}
+
+func (m *mockRoleRepository) Update(role *models.Role) error {
+	m.roles[role.ID] = role
+	return nil
+}
+
+func (m *mockRoleRepository) Delete(id uint) error {
+	delete(m.roles, id)
+	return nil
+}
+
+func (m *mockRoleRepository) SyncPermissions(role *models.Role, permissions []models.Permission) error {
+	m.roles[role.ID].Permissions = permissions
+	return nil
+}
+
+func (m *mockRoleRepository) FindAllPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	for _, permission := range m.permissions {
+		permissions = append(permissions, *permission)
+	}
+	return permissions, nil
+}
+
+func (m *mockRoleRepository) FindPermissionsByNames(names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	for _, name := range uniqueStrings(names) {
+		if permission, ok := m.permissions[name]; ok {
+			permissions = append(permissions, *permission)
+		}
+	}
+	return permissions, nil
+}
+
+func (m *mockRoleRepository) PermissionNamesForRoles(roleNames []string) ([]string, error) {
+	m.queries++
+	var names []string
+	for _, roleName := range roleNames {
+		if role, err := m.FindByName(roleName); err == nil {
+			for _, permission := range role.Permissions {
+				names = append(names, permission.Name)
+			}
+		}
+	}
+	return uniqueStrings(names), nil
+}
+
+func (m *mockRoleRepository) RoleNamesForUser(userID uint) ([]string, error) {
+	return uniqueStrings(m.userRoles[userID]), nil
+}
+
+func (m *mockRoleRepository) SyncUserRoles(userID uint, roles []models.Role) error {
+	var names []string
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	m.userRoles[userID] = names
+	return nil
+}
+
+func (m *mockRoleRepository) CountUsersWithRole(roleName string) (int64, error) {
+	var count int64
+	for _, names := range m.userRoles {
+		if containsString(names, roleName) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestRoleService_PermissionsForRoles 測試角色權限合併與快取
+func TestRoleService_PermissionsForRoles(t *testing.T) {
+	roleRepo := newMockRoleRepository()
+	service := NewRoleService(roleRepo, newMockUserRepository())
+
+	got, err := service.PermissionsForRoles([]string{models.RoleUser, models.RoleAdmin})
+	if err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+	want := []string{"posts.manage", "roles.manage", "users.create", "users.delete", "users.update", "users.view"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("權限不符，got %v, want %v", got, want)
+	}
+
+	// 第二次查詢應命中快取
+	queries := roleRepo.queries
+	service.PermissionsForRoles([]string{models.RoleAdmin})
+	if roleRepo.queries != queries {
+		t.Error("第二次查詢應該使用快取")
+	}
+
+	// 角色權限異動後快取失效
+	admin, _ := roleRepo.FindByName(models.RoleAdmin)
+	if _, err := service.SyncRolePermissions(admin.ID, &requests.SyncPermissionsRequest{Permissions: []string{"users.view"}}); err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+	got, _ = service.PermissionsForRoles([]string{models.RoleAdmin})
+	if !reflect.DeepEqual(got, []string{"users.view"}) {
+		t.Errorf("權限異動後應重新查詢，got %v", got)
+	}
+}
+
+// TestRoleService_CreateRole 測試新增角色
+func TestRoleService_CreateRole(t *testing.T) {
+	service := NewRoleService(newMockRoleRepository(), newMockUserRepository())
+
+	tests := []struct {
+		name    string
+		req     *requests.CreateRoleRequest
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "成功新增角色",
+			req:     &requests.CreateRoleRequest{Name: "editor", Permissions: []string{"users.view"}},
+			wantErr: false,
+		},
+		{
+			name:    "角色名稱重複",
+			req:     &requests.CreateRoleRequest{Name: "editor"},
+			wantErr: true,
+			errMsg:  "角色名稱已存在",
+		},
+		{
+			name:    "權限不存在",
+			req:     &requests.CreateRoleRequest{Name: "auditor", Permissions: []string{"logs.view"}},
+			wantErr: true,
+			errMsg:  "包含不存在的權限",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.CreateRole(tt.req)
+
+			if tt.wantErr {
+				if err == nil || err.Error() != tt.errMsg {
+					t.Errorf("錯誤訊息不符，got %v, want %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("不預期的錯誤: %v", err)
+			}
+			if !reflect.DeepEqual(resp.Permissions, tt.req.Permissions) {
+				t.Errorf("權限不符，got %v, want %v", resp.Permissions, tt.req.Permissions)
+			}
+		})
+	}
+}
+
+// TestRoleService_SyncUserRoles 測試不可移除最後一位管理員
+func TestRoleService_SyncUserRoles(t *testing.T) {
+	roleRepo := newMockRoleRepository()
+	userRepo := newMockUserRepository()
+	service := NewRoleService(roleRepo, userRepo)
+
+	userRepo.Create(&models.User{Name: "管理員", Email: "admin@example.com"})
+	userRepo.Create(&models.User{Name: "一般使用者", Email: "user@example.com"})
+	roleRepo.userRoles[1] = []string{models.RoleAdmin, models.RoleUser}
+	roleRepo.userRoles[2] = []string{models.RoleUser}
+
+	// 唯一的管理員不能被降級
+	_, err := service.SyncUserRoles(1, &requests.SyncRolesRequest{Roles: []string{models.RoleUser}})
+	if err == nil {
+		t.Fatal("預期應該發生錯誤，但沒有")
+	}
+
+	// 先把第二位使用者升為管理員，再降級第一位
+	if _, err := service.SyncUserRoles(2, &requests.SyncRolesRequest{Roles: []string{models.RoleAdmin}}); err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+	roles, err := service.SyncUserRoles(1, &requests.SyncRolesRequest{Roles: []string{models.RoleUser}})
+	if err != nil {
+		t.Fatalf("不預期的錯誤: %v", err)
+	}
+	if !reflect.DeepEqual(roles, []string{models.RoleUser}) {
+		t.Errorf("角色不符，got %v", roles)
+	}
+
+	// 不存在的角色
+	if _, err := service.SyncUserRoles(2, &requests.SyncRolesRequest{Roles: []string{"ghost"}}); err == nil {
+		t.Error("不存在的角色應該回傳錯誤")
+	}
+}
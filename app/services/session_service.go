@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/pkg/revocation"
+	"my-api/app/repositories"
+	"my-api/app/responses"
+	"my-api/config"
+)
+
+const (
+	// lastSeenInterval - sessions.last_seen_at 最多多久更新一次
+	lastSeenInterval = time.Minute
+	// loginHistoryLimit - 登入紀錄最多回傳幾筆
+	loginHistoryLimit = 50
+	// sessionRevocationPrefix - 撤銷清單中工作階段的 key 前綴（與 jti 區隔）
+	sessionRevocationPrefix = "session:"
+	// maxUserAgentLength - User-Agent 最多保存的字元數（對應欄位長度）
+	maxUserAgentLength = 255
+)
+
+// ErrSessionNotFound - 工作階段不存在、已登出或不屬於該使用者
+var ErrSessionNotFound = errors.New("登入裝置不存在")
+
+// SessionService - 登入工作階段（裝置）業務邏輯層介面
+//
+// 工作階段以 Refresh Token 的 FamilyID 識別，Access Token 的 sid 也是同一個值。
+// 撤銷時除了標記資料庫，也把 sid 加入撤銷清單，AuthMiddleware 不需要查資料庫就能拒絕。
+type SessionService interface {
+	Start(user *models.User, familyID string, expiresAt time.Time, client ClientInfo) error
+	Extend(familyID string, expiresAt time.Time) error
+	Check(familyID string) error
+	RecordFailedLogin(userID uint, client ClientInfo)
+	List(userID uint, currentFamilyID string) ([]responses.SessionResponse, error)
+	Revoke(userID, id uint) error
+	End(familyID string) error
+	EndAll(userID uint) error
+	EndOthers(userID uint, keepFamilyID string) error
+	History(userID uint) ([]responses.LoginHistoryResponse, error)
+}
+
+// sessionService - 實作 SessionService 介面
+type sessionService struct {
+	sessionRepo      repositories.SessionRepository
+	historyRepo      repositories.LoginHistoryRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	revocations      revocation.Store
+}
+
+// NewSessionService - 建立新的 SessionService 實例
+func NewSessionService(
+	sessionRepo repositories.SessionRepository,
+	historyRepo repositories.LoginHistoryRepository,
+	refreshTokenRepo repositories.RefreshTokenRepository,
+	revocations revocation.Store,
+) SessionService {
+	return &sessionService{
+		sessionRepo:      sessionRepo,
+		historyRepo:      historyRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		revocations:      revocations,
+	}
+}
+
+// Start - 登入成功，建立工作階段並寫入登入紀錄
+func (s *sessionService) Start(user *models.User, familyID string, expiresAt time.Time, client ClientInfo) error {
+	now := time.Now()
+	session := &models.Session{
+		UserID:     user.ID,
+		FamilyID:   familyID,
+		IPAddress:  client.IP,
+		UserAgent:  truncateRunes(client.UserAgent, maxUserAgentLength),
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return errors.New("建立登入裝置失敗")
+	}
+
+	// 登入紀錄只是參考資訊，寫入失敗不影響登入
+	_ = s.historyRepo.Create(&models.LoginHistory{
+		UserID:     user.ID,
+		SessionID:  &session.ID,
+		Successful: true,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+	})
+	return nil
+}
+
+// Extend - 刷新 Token 時延長工作階段
+func (s *sessionService) Extend(familyID string, expiresAt time.Time) error {
+	if err := s.sessionRepo.Extend(familyID, expiresAt); err != nil {
+		return errors.New("更新登入裝置失敗")
+	}
+	return nil
+}
+
+// Check - 檢查工作階段是否已被撤銷，並更新最後使用時間（AuthService.Authenticate 呼叫）
+func (s *sessionService) Check(familyID string) error {
+	revoked, err := s.revocations.IsRevoked(sessionRevocationPrefix + familyID)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return ErrTokenRevoked
+	}
+
+	// last_seen_at 只是參考資訊，更新失敗不影響這次請求
+	_ = s.sessionRepo.TouchLastSeen(familyID, time.Now(), lastSeenInterval)
+	return nil
+}
+
+// RecordFailedLogin - 記錄密碼錯誤的登入嘗試
+func (s *sessionService) RecordFailedLogin(userID uint, client ClientInfo) {
+	_ = s.historyRepo.Create(&models.LoginHistory{
+		UserID:     userID,
+		Successful: false,
+		IPAddress:  client.IP,
+		UserAgent:  truncateRunes(client.UserAgent, maxUserAgentLength),
+	})
+}
+
+// List - 取得使用者目前登入中的裝置，currentFamilyID 對應的那筆標記為 current
+func (s *sessionService) List(userID uint, currentFamilyID string) ([]responses.SessionResponse, error) {
+	sessions, err := s.sessionRepo.FindActiveByUser(userID)
+	if err != nil {
+		return nil, errors.New("取得登入裝置失敗")
+	}
+
+	result := make([]responses.SessionResponse, 0, len(sessions))
+	for i := range sessions {
+		current := currentFamilyID != "" && sessions[i].FamilyID == currentFamilyID
+		result = append(result, *responses.NewSessionResponse(&sessions[i], current))
+	}
+	return result, nil
+}
+
+// Revoke - 登出使用者自己的某個裝置
+func (s *sessionService) Revoke(userID, id uint) error {
+	session, err := s.sessionRepo.FindByID(userID, id)
+	if err != nil || !session.IsActive() {
+		return ErrSessionNotFound
+	}
+	return s.End(session.FamilyID)
+}
+
+// End - 結束工作階段：Access Token 加入撤銷清單，Refresh Token 與工作階段標記撤銷
+func (s *sessionService) End(familyID string) error {
+	// 撤銷紀錄只需保留到最後一個 Access Token 過期為止
+	ttl := time.Duration(config.GlobalConfig.JWT.AccessExpiryMinutes) * time.Minute
+	if err := s.revocations.Revoke(sessionRevocationPrefix+familyID, time.Now().Add(ttl)); err != nil {
+		return errors.New("登出裝置失敗")
+	}
+	if err := s.refreshTokenRepo.RevokeFamily(familyID); err != nil {
+		return errors.New("登出裝置失敗")
+	}
+	if err := s.sessionRepo.Revoke(familyID); err != nil {
+		return errors.New("登出裝置失敗")
+	}
+	return nil
+}
+
+// EndAll - 結束使用者的所有工作階段
+// 每個工作階段的 sid 都加入撤銷清單：使用者層級的撤銷時間點截斷到秒（保護之後重新登入的 Token），
+// 同一秒內稍早簽發的 Access Token 要靠 sid 拒絕
+func (s *sessionService) EndAll(userID uint) error {
+	sessions, err := s.sessionRepo.FindActiveByUser(userID)
+	if err != nil {
+		return errors.New("登出裝置失敗")
+	}
+
+	ttl := time.Duration(config.GlobalConfig.JWT.AccessExpiryMinutes) * time.Minute
+	for _, session := range sessions {
+		if err := s.revocations.Revoke(sessionRevocationPrefix+session.FamilyID, time.Now().Add(ttl)); err != nil {
+			return errors.New("登出裝置失敗")
+		}
+	}
+
+	if err := s.sessionRepo.RevokeAllForUser(userID); err != nil {
+		return errors.New("登出裝置失敗")
+	}
+	return nil
+}
+
+// EndOthers - 結束使用者除了 keepFamilyID 以外的所有工作階段
+func (s *sessionService) EndOthers(userID uint, keepFamilyID string) error {
+	sessions, err := s.sessionRepo.FindActiveByUser(userID)
+	if err != nil {
+		return errors.New("登出裝置失敗")
+	}
+
+	for _, session := range sessions {
+		if session.FamilyID == keepFamilyID {
+			continue
+		}
+		if err := s.End(session.FamilyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// History - 取得使用者最近的登入紀錄
+func (s *sessionService) History(userID uint) ([]responses.LoginHistoryResponse, error) {
+	histories, err := s.historyRepo.FindRecentByUser(userID, loginHistoryLimit)
+	if err != nil {
+		return nil, errors.New("取得登入紀錄失敗")
+	}
+
+	result := make([]responses.LoginHistoryResponse, 0, len(histories))
+	for i := range histories {
+		result = append(result, *responses.NewLoginHistoryResponse(&histories[i]))
+	}
+	return result, nil
+}
+
+// truncateRunes - 截斷字串到最多 n 個字元（不切斷多位元組字元）
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
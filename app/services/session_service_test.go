@@ -0,0 +1,321 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/requests"
+)
+
+// ============================================================================
+// Mock Repository
+// ============================================================================
+
+// mockSessionRepository 實作 SessionRepository interface
+type mockSessionRepository struct {
+	sessions map[uint]*models.Session
+	nextID   uint
+}
+
+func newMockSessionRepository() *mockSessionRepository {
+	return &mockSessionRepository{sessions: make(map[uint]*models.Session), nextID: 1}
+}
+
+func (m *mockSessionRepository) Create(session *models.Session) error {
+	session.ID = m.nextID
+	session.CreatedAt = time.Now()
+	m.nextID++
+	m.sessions[session.ID] = session
+	return nil
+}
+
+func (m *mockSessionRepository) FindByID(userID, id uint) (*models.Session, error) {
+	if session, ok := m.sessions[id]; ok && session.UserID == userID {
+		copied := *session
+		return &copied, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockSessionRepository) FindActiveByUser(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	for _, session := range m.sessions {
+		if session.UserID == userID && session.IsActive() {
+			sessions = append(sessions, *session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+	return sessions, nil
+}
+
+func (m *mockSessionRepository) FindByUser(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	for _, session := range m.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, *session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID > sessions[j].ID })
+	return sessions, nil
+}
+
+func (m *mockSessionRepository) findByFamily(familyID string) *models.Session {
+	for _, session := range m.sessions {
+		if session.FamilyID == familyID {
+			return session
+		}
+	}
+	return nil
+}
+
+func (m *mockSessionRepository) Extend(familyID string, expiresAt time.Time) error {
+	if session := m.findByFamily(familyID); session != nil && session.RevokedAt == nil {
+		session.ExpiresAt = expiresAt
+		session.LastSeenAt = time.Now()
+	}
+	return nil
+}
+
+func (m *mockSessionRepository) TouchLastSeen(familyID string, seenAt time.Time, interval time.Duration) error {
+	if session := m.findByFamily(familyID); session != nil && session.LastSeenAt.Before(seenAt.Add(-interval)) {
+		session.LastSeenAt = seenAt
+	}
+	return nil
+}
+
+func (m *mockSessionRepository) Revoke(familyID string) error {
+	if session := m.findByFamily(familyID); session != nil && session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+	}
+	return nil
+}
+
+func (m *mockSessionRepository) RevokeAllForUser(userID uint) error {
+	now := time.Now()
+	for _, session := range m.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *mockSessionRepository) DeleteByUser(userID uint) error {
+	for id, session := range m.sessions {
+		if session.UserID == userID {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+// mockLoginHistoryRepository 實作 LoginHistoryRepository interface
+type mockLoginHistoryRepository struct {
+	histories []models.LoginHistory
+}
+
+func (m *mockLoginHistoryRepository) Create(history *models.LoginHistory) error {
+	history.ID = uint(len(m.histories) + 1)
+	history.CreatedAt = time.Now()
+	m.histories = append(m.histories, *history)
+	return nil
+}
+
+func (m *mockLoginHistoryRepository) FindRecentByUser(userID uint, limit int) ([]models.LoginHistory, error) {
+	var histories []models.LoginHistory
+	for i := len(m.histories) - 1; i >= 0 && len(histories) < limit; i-- {
+		if m.histories[i].UserID == userID {
+			histories = append(histories, m.histories[i])
+		}
+	}
+	return histories, nil
+}
+
+func (m *mockLoginHistoryRepository) FindByUser(userID uint) ([]models.LoginHistory, error) {
+	return m.FindRecentByUser(userID, len(m.histories))
+}
+
+func (m *mockLoginHistoryRepository) DeleteByUser(userID uint) error {
+	var kept []models.LoginHistory
+	for _, history := range m.histories {
+		if history.UserID != userID {
+			kept = append(kept, history)
+		}
+	}
+	m.histories = kept
+	return nil
+}
+
+// sessionTestEnv 登入裝置測試所需的依賴
+type sessionTestEnv struct {
+	auth        AuthService
+	sessions    SessionService
+	sessionRepo *mockSessionRepository
+	userID      uint
+}
+
+// newSessionTestEnv 建立測試環境，並預先建立一個可登入的使用者
+func newSessionTestEnv(t *testing.T) *sessionTestEnv {
+	t.Helper()
+	setupTestConfig()
+
+	deps := newTestServices(newMockUserRepository())
+	user := createTestUser(t, deps.userRepo, &models.User{Email: "session@example.com"})
+	return &sessionTestEnv{auth: deps.authService(), sessions: deps.sessions, sessionRepo: deps.sessionRepo, userID: user.ID}
+}
+
+// login 從指定的裝置登入
+func (env *sessionTestEnv) login(t *testing.T, client ClientInfo) (accessToken, refreshToken string) {
+	t.Helper()
+	resp, err := env.auth.Login(&requests.LoginRequest{Email: "session@example.com", Password: testPassword}, client)
+	if err != nil {
+		t.Fatalf("Login() 發生錯誤: %v", err)
+	}
+	return resp.AccessToken, resp.RefreshToken
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestSessionService_Login 測試登入會建立工作階段與登入紀錄
+func TestSessionService_Login(t *testing.T) {
+	env := newSessionTestEnv(t)
+
+	laptop := ClientInfo{IP: "203.0.113.10", UserAgent: "Laptop"}
+	phone := ClientInfo{IP: "198.51.100.7", UserAgent: "Phone"}
+	accessToken, _ := env.login(t, laptop)
+	env.login(t, phone)
+
+	// 密碼錯誤也要留下紀錄
+	if _, err := env.auth.Login(&requests.LoginRequest{Email: "session@example.com", Password: "wrong"}, phone); err == nil {
+		t.Fatal("密碼錯誤應該登入失敗")
+	}
+
+	claims, err := env.auth.Authenticate(accessToken)
+	if err != nil {
+		t.Fatalf("Authenticate() 發生錯誤: %v", err)
+	}
+	if claims.SessionID == "" {
+		t.Fatal("Access Token 應該帶有 sid")
+	}
+
+	sessions, err := env.sessions.List(env.userID, claims.SessionID)
+	if err != nil {
+		t.Fatalf("List() 發生錯誤: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("應該有 2 個登入裝置，got %d", len(sessions))
+	}
+	if sessions[0].UserAgent != "Laptop" || sessions[0].IPAddress != "203.0.113.10" || !sessions[0].Current {
+		t.Errorf("第一個裝置資料不符: %+v", sessions[0])
+	}
+	if sessions[1].Current {
+		t.Error("只有目前的裝置應該標記 current")
+	}
+
+	history, err := env.sessions.History(env.userID)
+	if err != nil {
+		t.Fatalf("History() 發生錯誤: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("應該有 3 筆登入紀錄，got %d", len(history))
+	}
+	if history[0].Successful || history[0].SessionID != nil {
+		t.Errorf("最新一筆應該是失敗的登入: %+v", history[0])
+	}
+	if !history[1].Successful || history[1].SessionID == nil {
+		t.Errorf("成功的登入應該對應到工作階段: %+v", history[1])
+	}
+}
+
+// TestSessionService_Revoke 測試登出某個裝置後，該裝置的 Token 立即失效，其他裝置不受影響
+func TestSessionService_Revoke(t *testing.T) {
+	env := newSessionTestEnv(t)
+
+	lostAccess, lostRefresh := env.login(t, ClientInfo{IP: "203.0.113.10", UserAgent: "Lost laptop"})
+	keptAccess, _ := env.login(t, ClientInfo{IP: "198.51.100.7", UserAgent: "Phone"})
+
+	sessions, _ := env.sessions.List(env.userID, "")
+	lost := sessions[0].ID
+
+	// 其他使用者不能登出別人的裝置
+	if err := env.sessions.Revoke(env.userID+1, lost); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("預期 ErrSessionNotFound，got %v", err)
+	}
+
+	if err := env.sessions.Revoke(env.userID, lost); err != nil {
+		t.Fatalf("Revoke() 發生錯誤: %v", err)
+	}
+
+	if _, err := env.auth.Authenticate(lostAccess); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("已登出裝置的 Access Token 應該失效，got %v", err)
+	}
+	if _, err := env.auth.Refresh(&requests.RefreshTokenRequest{RefreshToken: lostRefresh}); err == nil {
+		t.Error("已登出裝置的 Refresh Token 應該失效")
+	}
+	if _, err := env.auth.Authenticate(keptAccess); err != nil {
+		t.Errorf("其他裝置不應受影響: %v", err)
+	}
+
+	remaining, _ := env.sessions.List(env.userID, "")
+	if len(remaining) != 1 || remaining[0].ID == lost {
+		t.Errorf("已登出的裝置不應出現在列表: %+v", remaining)
+	}
+
+	if err := env.sessions.Revoke(env.userID, lost); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("重複登出應回傳 ErrSessionNotFound，got %v", err)
+	}
+}
+
+// TestSessionService_Refresh 測試刷新 Token 沿用同一個工作階段
+func TestSessionService_Refresh(t *testing.T) {
+	env := newSessionTestEnv(t)
+
+	accessToken, refreshToken := env.login(t, testClient)
+	before, _ := env.auth.Authenticate(accessToken)
+
+	resp, err := env.auth.Refresh(&requests.RefreshTokenRequest{RefreshToken: refreshToken})
+	if err != nil {
+		t.Fatalf("Refresh() 發生錯誤: %v", err)
+	}
+	after, err := env.auth.Authenticate(resp.AccessToken)
+	if err != nil {
+		t.Fatalf("Authenticate() 發生錯誤: %v", err)
+	}
+
+	if before.SessionID != after.SessionID {
+		t.Errorf("刷新後 sid 應該相同: %s != %s", before.SessionID, after.SessionID)
+	}
+	if sessions, _ := env.sessions.List(env.userID, ""); len(sessions) != 1 {
+		t.Errorf("刷新不應建立新的工作階段，got %d", len(sessions))
+	}
+}
+
+// TestSessionService_Logout 測試登出與登出所有裝置會結束工作階段
+func TestSessionService_Logout(t *testing.T) {
+	env := newSessionTestEnv(t)
+
+	accessToken, _ := env.login(t, testClient)
+	env.login(t, testClient)
+	env.login(t, testClient)
+
+	claims, _ := env.auth.Authenticate(accessToken)
+	if err := env.auth.Logout(claims, &requests.LogoutRequest{}); err != nil {
+		t.Fatalf("Logout() 發生錯誤: %v", err)
+	}
+	if sessions, _ := env.sessions.List(env.userID, ""); len(sessions) != 2 {
+		t.Errorf("登出後應剩下 2 個裝置，got %d", len(sessions))
+	}
+
+	if err := env.auth.LogoutAll(env.userID); err != nil {
+		t.Fatalf("LogoutAll() 發生錯誤: %v", err)
+	}
+	if sessions, _ := env.sessions.List(env.userID, ""); len(sessions) != 0 {
+		t.Errorf("登出所有裝置後不應有工作階段，got %d", len(sessions))
+	}
+}
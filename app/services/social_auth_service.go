@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/pkg/oidc"
+	"my-api/app/repositories"
+	"my-api/app/requests"
+	"my-api/app/responses"
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// maxNameLength - users.name 的欄位長度
+const maxNameLength = 100
+
+var (
+	// ErrUnknownOIDCProvider - 沒有設定這個 IdP
+	ErrUnknownOIDCProvider = errors.New("不支援的登入方式")
+	// ErrInvalidOIDCState - state 不符、已過期或不是從這個瀏覽器發起的登入
+	ErrInvalidOIDCState = errors.New("登入請求已逾時或無效，請重新登入")
+	// ErrOIDCLoginCancelled - 使用者在 IdP 拒絕授權
+	ErrOIDCLoginCancelled = errors.New("已取消登入")
+	// ErrOIDCLoginFailed - 換取 Token 或驗證 ID Token 失敗（詳細原因只記錄在日誌）
+	ErrOIDCLoginFailed = errors.New("第三方登入失敗")
+	// ErrOIDCEmailRequired - IdP 沒有提供 Email，無法建立或連結帳號
+	ErrOIDCEmailRequired = errors.New("登入服務未提供電子郵件，無法登入")
+	// ErrOIDCEmailTaken - Email 已被其他帳號使用，且無法確認是同一人
+	ErrOIDCEmailTaken = errors.New("此電子郵件已註冊，請使用原本的方式登入")
+	// ErrOIDCSignupDisabled - 沒有對應的帳號，且不允許自動建立
+	ErrOIDCSignupDisabled = errors.New("此帳號尚未註冊")
+)
+
+// SocialAuthService - OpenID Connect 登入業務邏輯層介面
+//
+// 流程：Redirect 產生 state、nonce、PKCE code_verifier，加密後交給 Controller 存在 Cookie，
+// 再把瀏覽器導向 IdP；IdP 導回 Callback 時比對 Cookie 中的 state，
+// 以授權碼 + code_verifier 換取 ID Token 並驗證 nonce，最後建立或連結帳號並發出 Token。
+type SocialAuthService interface {
+	Providers() []string
+	Redirect(ctx context.Context, provider string) (authURL, state string, err error)
+	Callback(ctx context.Context, provider string, req *requests.SocialCallbackRequest, state string, client ClientInfo) (*responses.LoginResponse, error)
+}
+
+// socialAuthService - 實作 SocialAuthService 介面
+type socialAuthService struct {
+	providers    map[string]*oidc.Provider
+	identityRepo repositories.IdentityRepository
+	userRepo     repositories.UserRepository
+	roleRepo     repositories.RoleRepository
+	authService  AuthService
+	verification EmailVerificationService
+}
+
+// NewSocialAuthService - 建立新的 SocialAuthService 實例
+func NewSocialAuthService(
+	providers map[string]*oidc.Provider,
+	identityRepo repositories.IdentityRepository,
+	userRepo repositories.UserRepository,
+	roleRepo repositories.RoleRepository,
+	authService AuthService,
+	verification EmailVerificationService,
+) SocialAuthService {
+	return &socialAuthService{
+		providers:    providers,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		authService:  authService,
+		verification: verification,
+	}
+}
+
+// oidcState - 導向 IdP 前產生、存在 Cookie 中（以 APP_KEY 加密）的登入狀態
+type oidcState struct {
+	Provider  string `json:"provider"`
+	State     string `json:"state"`
+	Nonce     string `json:"nonce"`
+	Verifier  string `json:"verifier"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// Providers - 已設定的 IdP 名稱
+func (s *socialAuthService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Redirect - 產生 IdP 授權網址，以及要存在 Cookie 中的加密 state
+func (s *socialAuthService) Redirect(ctx context.Context, provider string) (string, string, error) {
+	idp, ok := s.providers[provider]
+	if !ok {
+		return "", "", ErrUnknownOIDCProvider
+	}
+
+	state, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", "", errors.New("產生登入請求失敗")
+	}
+	nonce, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", "", errors.New("產生登入請求失敗")
+	}
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		return "", "", errors.New("產生登入請求失敗")
+	}
+
+	authURL, err := idp.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return "", "", err
+	}
+
+	ttl := time.Duration(config.GlobalConfig.OIDC.StateTTLMinutes) * time.Minute
+	payload, err := json.Marshal(oidcState{
+		Provider:  provider,
+		State:     state,
+		Nonce:     nonce,
+		Verifier:  verifier,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", "", errors.New("產生登入請求失敗")
+	}
+	encrypted, err := utils.Encrypt(string(payload))
+	if err != nil {
+		return "", "", errors.New("產生登入請求失敗")
+	}
+
+	return authURL, encrypted, nil
+}
+
+// Callback - IdP 導回後完成登入
+// state 為 Redirect 回傳、存在 Cookie 中的值；已啟用兩步驟驗證的帳號回傳 challenge token
+func (s *socialAuthService) Callback(ctx context.Context, provider string, req *requests.SocialCallbackRequest, state string, client ClientInfo) (*responses.LoginResponse, error) {
+	idp, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownOIDCProvider
+	}
+
+	stored, err := decodeOIDCState(state)
+	if err != nil || stored.Provider != provider || time.Now().Unix() > stored.ExpiresAt ||
+		subtle.ConstantTimeCompare([]byte(stored.State), []byte(req.State)) != 1 {
+		return nil, ErrInvalidOIDCState
+	}
+	if req.Error != "" {
+		return nil, ErrOIDCLoginCancelled
+	}
+
+	token, err := idp.Exchange(ctx, req.Code, stored.Verifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+	claims, err := idp.VerifyIDToken(ctx, token.IDToken, stored.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+
+	user, err := s.resolveUser(provider, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.CompleteLogin(user, client)
+}
+
+// resolveUser - 找出 IdP 身分對應的使用者
+// 1. 已連結過：直接使用
+// 2. Email 已註冊：IdP 確認過這個 Email（email_verified）且允許自動連結時才連結，否則拒絕，避免帳號被接管
+// 3. 沒有帳號：允許註冊時建立新帳號
+func (s *socialAuthService) resolveUser(provider string, claims *oidc.IDToken) (*models.User, error) {
+	now := time.Now()
+
+	if identity, err := s.identityRepo.FindByProviderSubject(provider, claims.Subject); err == nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: 使用者不存在", ErrOIDCLoginFailed)
+		}
+		// Email 只是參考資訊，更新失敗不影響登入
+		identity.Email = claims.Email
+		identity.LastLoginAt = now
+		_ = s.identityRepo.Update(identity)
+		return user, nil
+	}
+
+	if claims.Email == "" {
+		return nil, ErrOIDCEmailRequired
+	}
+
+	cfg := config.GlobalConfig.OIDC
+	user, err := s.userRepo.FindByEmail(claims.Email)
+	if err == nil {
+		if !bool(claims.EmailVerified) || !cfg.LinkVerifiedEmail {
+			return nil, ErrOIDCEmailTaken
+		}
+		if user.EmailVerifiedAt == nil {
+			user.EmailVerifiedAt = &now
+			if err := s.userRepo.Update(user); err != nil {
+				return nil, errors.New("連結帳號失敗")
+			}
+		}
+	} else {
+		if !cfg.AllowSignup {
+			return nil, ErrOIDCSignupDisabled
+		}
+		if user, err = s.createUser(claims); err != nil {
+			return nil, err
+		}
+	}
+
+	identity := &models.Identity{
+		UserID:      user.ID,
+		Provider:    provider,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: now,
+	}
+	if err := s.identityRepo.Create(identity); err != nil {
+		return nil, errors.New("連結帳號失敗")
+	}
+	return user, nil
+}
+
+// createUser - 以 IdP 提供的資料建立帳號
+// 沒有密碼（只能用 IdP 或忘記密碼流程設定後登入）；IdP 未確認的 Email 需再自行驗證
+func (s *socialAuthService) createUser(claims *oidc.IDToken) (*models.User, error) {
+	hashedPassword, err := unusablePassword()
+	if err != nil {
+		return nil, errors.New("建立使用者失敗")
+	}
+
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+
+	user := &models.User{
+		Name:     truncateRunes(name, maxNameLength),
+		Email:    claims.Email,
+		Password: hashedPassword,
+	}
+	if claims.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, errors.New("建立使用者失敗")
+	}
+	if err := assignDefaultRoles(s.roleRepo, user); err != nil {
+		return nil, err
+	}
+	if user.EmailVerifiedAt == nil {
+		_ = s.verification.SendVerificationEmail(user)
+	}
+
+	return user, nil
+}
+
+// decodeOIDCState - 解密 Cookie 中的登入狀態
+func decodeOIDCState(encrypted string) (*oidcState, error) {
+	if encrypted == "" {
+		return nil, ErrInvalidOIDCState
+	}
+	payload, err := utils.Decrypt(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	var state oidcState
+	if err := json.Unmarshal([]byte(payload), &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
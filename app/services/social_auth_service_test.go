@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"my-api/app/models"
+	"my-api/app/pkg/oidc"
+	"my-api/app/pkg/oidc/oidctest"
+	"my-api/app/requests"
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// ============================================================================
+// Mock Repository
+// ============================================================================
+
+// mockIdentityRepository 實作 IdentityRepository interface
+type mockIdentityRepository struct {
+	identities []*models.Identity
+}
+
+func (m *mockIdentityRepository) Create(identity *models.Identity) error {
+	for _, existing := range m.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return errors.New("duplicate entry")
+		}
+	}
+	identity.ID = uint(len(m.identities) + 1)
+	identity.CreatedAt = time.Now()
+	m.identities = append(m.identities, identity)
+	return nil
+}
+
+func (m *mockIdentityRepository) FindByProviderSubject(provider, subject string) (*models.Identity, error) {
+	for _, identity := range m.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockIdentityRepository) Update(identity *models.Identity) error {
+	for i, existing := range m.identities {
+		if existing.ID == identity.ID {
+			m.identities[i] = identity
+			return nil
+		}
+	}
+	return errors.New("record not found")
+}
+
+func (m *mockIdentityRepository) FindByUser(userID uint) ([]models.Identity, error) {
+	var identities []models.Identity
+	for _, identity := range m.identities {
+		if identity.UserID == userID {
+			identities = append(identities, *identity)
+		}
+	}
+	return identities, nil
+}
+
+func (m *mockIdentityRepository) DeleteByUser(userID uint) error {
+	var kept []*models.Identity
+	for _, identity := range m.identities {
+		if identity.UserID != userID {
+			kept = append(kept, identity)
+		}
+	}
+	m.identities = kept
+	return nil
+}
+
+// socialTestEnv OpenID Connect 登入測試所需的依賴（IdP 為 oidctest 啟動的本機伺服器）
+type socialTestEnv struct {
+	*testServices
+	idp     *oidctest.Server
+	service SocialAuthService
+	auth    AuthService
+}
+
+// newSocialTestEnv 建立測試環境，IdP 名稱為 "test"
+func newSocialTestEnv(t *testing.T) *socialTestEnv {
+	t.Helper()
+	setupTestConfig()
+	config.GlobalConfig.App = config.AppConfig{URL: "https://api.example.com", Key: "test-app-key"}
+	config.GlobalConfig.OIDC = config.OIDCConfig{StateTTLMinutes: 10, AllowSignup: true, LinkVerifiedEmail: true}
+
+	idp := oidctest.NewServer()
+	t.Cleanup(idp.Close)
+
+	deps := newTestServices(newMockUserRepository())
+	auth := deps.authService()
+	providers := map[string]*oidc.Provider{
+		"test": oidc.NewProvider(idp.Config("https://api.example.com/api/auth/oidc/test/callback")),
+	}
+
+	return &socialTestEnv{
+		testServices: deps,
+		idp:          idp,
+		service:      NewSocialAuthService(providers, deps.identityRepo, deps.userRepo, deps.roleRepo, auth, deps.verification),
+		auth:         auth,
+	}
+}
+
+// login 模擬瀏覽器：導向 IdP、在 IdP 登入、帶著 Cookie 回到 callback
+func (env *socialTestEnv) login(t *testing.T, user oidctest.User) (*requests.SocialCallbackRequest, string) {
+	t.Helper()
+	env.idp.SetUser(user)
+
+	authURL, state, err := env.service.Redirect(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Redirect() 發生錯誤: %v", err)
+	}
+	code, returnedState, err := env.idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize() 發生錯誤: %v", err)
+	}
+	return &requests.SocialCallbackRequest{Code: code, State: returnedState}, state
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestSocialAuth_Signup 測試第一次登入時建立帳號與外部身分，之後以同一個 sub 登入沿用同一個帳號
+func TestSocialAuth_Signup(t *testing.T) {
+	env := newSocialTestEnv(t)
+	idpUser := oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, Name: "Alice"}
+
+	req, state := env.login(t, idpUser)
+	resp, err := env.service.Callback(context.Background(), "test", req, state, testClient)
+	if err != nil {
+		t.Fatalf("Callback() 發生錯誤: %v", err)
+	}
+	if resp.AuthResponse == nil || resp.AccessToken == "" || resp.RefreshToken == "" {
+		t.Fatalf("應該發出 Token: %+v", resp)
+	}
+
+	user, err := env.userRepo.FindByEmail("alice@example.com")
+	if err != nil {
+		t.Fatalf("應該建立使用者: %v", err)
+	}
+	if user.Name != "Alice" || user.EmailVerifiedAt == nil {
+		t.Errorf("使用者資料不符: %+v", user)
+	}
+	// 系統中沒有管理員時也只會指派 user 角色
+	if roles, _ := env.roleRepo.RoleNamesForUser(user.ID); !containsString(roles, models.RoleUser) || containsString(roles, models.RoleAdmin) {
+		t.Errorf("新帳號只應指派 user 角色，got %v", roles)
+	}
+	if len(env.identityRepo.identities) != 1 || env.identityRepo.identities[0].UserID != user.ID {
+		t.Fatalf("應該建立外部身分: %+v", env.identityRepo.identities)
+	}
+
+	// IdP 上的 Email 變更後，仍以 sub 對應到同一個帳號
+	idpUser.Email = "alice.new@example.com"
+	req, state = env.login(t, idpUser)
+	resp, err = env.service.Callback(context.Background(), "test", req, state, testClient)
+	if err != nil {
+		t.Fatalf("Callback() 發生錯誤: %v", err)
+	}
+	claims, _ := utils.ValidateToken(resp.AccessToken)
+	if claims.UserID != user.ID {
+		t.Errorf("應該登入同一個帳號，got %d", claims.UserID)
+	}
+	if len(env.identityRepo.identities) != 1 || env.identityRepo.identities[0].Email != "alice.new@example.com" {
+		t.Errorf("不應重複建立外部身分: %+v", env.identityRepo.identities)
+	}
+}
+
+// TestSocialAuth_LinkExistingAccount 測試 Email 已註冊時，只有 IdP 確認過的 Email 才會自動連結
+func TestSocialAuth_LinkExistingAccount(t *testing.T) {
+	env := newSocialTestEnv(t)
+	existing := &models.User{Name: "Bob", Email: "bob@example.com", Password: "hash"}
+	env.userRepo.Create(existing)
+
+	// IdP 未確認 Email：可能是別人註冊了同樣的 Email，不可連結
+	req, state := env.login(t, oidctest.User{Subject: "sub-bob", Email: "bob@example.com", EmailVerified: false})
+	if _, err := env.service.Callback(context.Background(), "test", req, state, testClient); !errors.Is(err, ErrOIDCEmailTaken) {
+		t.Fatalf("預期 ErrOIDCEmailTaken，got %v", err)
+	}
+
+	req, state = env.login(t, oidctest.User{Subject: "sub-bob", Email: "bob@example.com", EmailVerified: true})
+	resp, err := env.service.Callback(context.Background(), "test", req, state, testClient)
+	if err != nil {
+		t.Fatalf("Callback() 發生錯誤: %v", err)
+	}
+	claims, _ := utils.ValidateToken(resp.AccessToken)
+	if claims.UserID != existing.ID {
+		t.Errorf("應該連結到既有帳號，got %d", claims.UserID)
+	}
+	if existing.EmailVerifiedAt == nil {
+		t.Error("IdP 確認過的 Email 應視為已驗證")
+	}
+}
+
+// TestSocialAuth_InvalidState 測試 state 不符、Cookie 遺失、逾時或不同 IdP 時拒絕登入
+func TestSocialAuth_InvalidState(t *testing.T) {
+	env := newSocialTestEnv(t)
+	idpUser := oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}
+
+	req, state := env.login(t, idpUser)
+	_, otherState := env.login(t, idpUser)
+
+	tests := []struct {
+		name     string
+		provider string
+		state    string
+		req      requests.SocialCallbackRequest
+		want     error
+	}{
+		{name: "沒有 Cookie", provider: "test", state: "", req: *req, want: ErrInvalidOIDCState},
+		{name: "其他登入請求的 Cookie", provider: "test", state: otherState, req: *req, want: ErrInvalidOIDCState},
+		{name: "Cookie 被竄改", provider: "test", state: state + "x", req: *req, want: ErrInvalidOIDCState},
+		{name: "未設定的 IdP", provider: "unknown", state: state, req: *req, want: ErrUnknownOIDCProvider},
+		{name: "使用者在 IdP 取消", provider: "test", state: state, req: requests.SocialCallbackRequest{State: req.State, Error: "access_denied"}, want: ErrOIDCLoginCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := env.service.Callback(context.Background(), tt.provider, &tt.req, tt.state, testClient); !errors.Is(err, tt.want) {
+				t.Errorf("預期 %v，got %v", tt.want, err)
+			}
+		})
+	}
+
+	// 逾時
+	config.GlobalConfig.OIDC.StateTTLMinutes = -1
+	req, state = env.login(t, idpUser)
+	if _, err := env.service.Callback(context.Background(), "test", req, state, testClient); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Errorf("逾時應回傳 ErrInvalidOIDCState，got %v", err)
+	}
+	if len(env.userRepo.users) != 0 {
+		t.Error("驗證失敗不應建立使用者")
+	}
+}
+
+// TestSocialAuth_IDTokenRejected 測試 IdP 簽發的 ID Token 無效時拒絕登入
+func TestSocialAuth_IDTokenRejected(t *testing.T) {
+	env := newSocialTestEnv(t)
+	env.idp.ModifyClaims = func(claims jwt.MapClaims) { claims["nonce"] = "replayed-nonce" }
+
+	req, state := env.login(t, oidctest.User{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true})
+	if _, err := env.service.Callback(context.Background(), "test", req, state, testClient); !errors.Is(err, ErrOIDCLoginFailed) {
+		t.Errorf("預期 ErrOIDCLoginFailed，got %v", err)
+	}
+}
+
+// TestSocialAuth_SignupDisabled 測試不允許自動建立帳號時，未連結的身分無法登入
+func TestSocialAuth_SignupDisabled(t *testing.T) {
+	env := newSocialTestEnv(t)
+	config.GlobalConfig.OIDC.AllowSignup = false
+
+	req, state := env.login(t, oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: true})
+	if _, err := env.service.Callback(context.Background(), "test", req, state, testClient); !errors.Is(err, ErrOIDCSignupDisabled) {
+		t.Errorf("預期 ErrOIDCSignupDisabled，got %v", err)
+	}
+}
+
+// TestSocialAuth_UnverifiedSignup 測試 IdP 未確認的 Email 建立帳號後需要自行驗證
+func TestSocialAuth_UnverifiedSignup(t *testing.T) {
+	env := newSocialTestEnv(t)
+	config.GlobalConfig.Auth.VerificationExpiryMinutes = 60
+
+	req, state := env.login(t, oidctest.User{Subject: "sub-1", Email: "carol@example.com", EmailVerified: false})
+	if _, err := env.service.Callback(context.Background(), "test", req, state, testClient); err != nil {
+		t.Fatalf("Callback() 發生錯誤: %v", err)
+	}
+
+	user, _ := env.userRepo.FindByEmail("carol@example.com")
+	if user.EmailVerifiedAt != nil {
+		t.Error("IdP 未確認的 Email 不應視為已驗證")
+	}
+	if user.Name != "carol" {
+		t.Errorf("沒有名稱時應使用 Email 帳號，got %q", user.Name)
+	}
+	if msg := env.mailer.Last(); msg == nil || msg.To != "carol@example.com" {
+		t.Error("應該寄出 Email 驗證信")
+	}
+}
+
+// TestSocialAuth_RedirectURL 測試授權網址帶有 PKCE 與 nonce，且 state 不會以明文出現在 Cookie
+func TestSocialAuth_RedirectURL(t *testing.T) {
+	env := newSocialTestEnv(t)
+
+	authURL, state, err := env.service.Redirect(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Redirect() 發生錯誤: %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("nonce") == "" || query.Get("state") == "" {
+		t.Errorf("授權網址缺少參數: %s", authURL)
+	}
+	if state == query.Get("state") {
+		t.Error("Cookie 中的登入狀態應該加密")
+	}
+
+	if _, _, err := env.service.Redirect(context.Background(), "unknown"); !errors.Is(err, ErrUnknownOIDCProvider) {
+		t.Errorf("預期 ErrUnknownOIDCProvider，got %v", err)
+	}
+}
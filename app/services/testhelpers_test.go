@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+
+	"my-api/app/models"
+	"my-api/app/pkg/mail"
+	"my-api/app/pkg/revocation"
+	"my-api/app/pkg/throttle"
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// testPassword 測試使用者的密碼
+const testPassword = "password123"
+
+// testClient 測試用的用戶端資訊
+var testClient = ClientInfo{IP: "203.0.113.10", UserAgent: "go-test"}
+
+// setupTestConfig 設定測試用的 JWT 配置
+func setupTestConfig() {
+	config.GlobalConfig = &config.Config{
+		JWT: config.JWTConfig{
+			Secret:              "test-secret",
+			AccessExpiryMinutes: 15,
+			RefreshExpiryHours:  720,
+		},
+	}
+}
+
+// testServices 測試用的 service 依賴，預設全部使用 mock repository 與記憶體儲存
+// 測試可以在建立 service 之前替換需要的欄位，或直接讀取 mock 檢查結果
+type testServices struct {
+	userRepo     *mockUserRepository
+	refreshRepo  *mockRefreshTokenRepository
+	roleRepo     *mockRoleRepository
+	sessionRepo  *mockSessionRepository
+	historyRepo  *mockLoginHistoryRepository
+	identityRepo *mockIdentityRepository
+	tokenRepo    *mockPersonalAccessTokenRepository
+	postRepo     *mockPostRepository
+	resetRepo    *mockPasswordResetTokenRepository
+	revocations  revocation.Store
+	mailer       *mail.ArrayMailer
+
+	verification EmailVerificationService
+	throttle     LoginThrottle
+	twoFactor    TwoFactorService
+	sessions     SessionService
+}
+
+// newTestServices 以 userRepo 建立測試用的 service 依賴
+func newTestServices(userRepo *mockUserRepository) *testServices {
+	s := &testServices{
+		userRepo:     userRepo,
+		refreshRepo:  newMockRefreshTokenRepository(),
+		roleRepo:     newMockRoleRepository(),
+		sessionRepo:  newMockSessionRepository(),
+		historyRepo:  &mockLoginHistoryRepository{},
+		identityRepo: &mockIdentityRepository{},
+		tokenRepo:    newMockPersonalAccessTokenRepository(userRepo),
+		postRepo:     newMockPostRepository(),
+		resetRepo:    newMockPasswordResetTokenRepository(),
+		revocations:  revocation.NewMemoryStore(),
+		mailer:       mail.NewArrayMailer("noreply@example.com"),
+	}
+	s.verification = NewEmailVerificationService(userRepo, s.mailer)
+	s.throttle = NewLoginThrottle(throttle.NewMemoryStore(), nil)
+	s.twoFactor = NewTwoFactorService(userRepo, throttle.NewMemoryStore())
+	s.sessions = NewSessionService(s.sessionRepo, s.historyRepo, s.refreshRepo, s.revocations)
+	return s
+}
+
+// authService 建立 AuthService
+func (s *testServices) authService() AuthService {
+	return NewAuthService(s.userRepo, s.refreshRepo, s.roleRepo, s.revocations, s.verification, s.throttle, s.twoFactor, s.sessions)
+}
+
+// passwordService 建立 PasswordService
+func (s *testServices) passwordService() PasswordService {
+	return NewPasswordService(s.userRepo, s.resetRepo, s.tokenRepo, s.authService(), s.mailer)
+}
+
+// accountService 建立 AccountService
+func (s *testServices) accountService() AccountService {
+	return NewAccountService(s.userRepo, s.postRepo, s.roleRepo, s.sessionRepo, s.historyRepo, s.identityRepo, s.tokenRepo, s.authService(), s.mailer)
+}
+
+// userService 建立 UserService
+func (s *testServices) userService() UserService {
+	return NewUserService(s.userRepo, s.verification, s.passwordService(), s.accountService())
+}
+
+// createTestUser 建立使用者，沒有指定時名稱為「測試使用者」、密碼為 testPassword
+func createTestUser(t *testing.T, userRepo *mockUserRepository, user *models.User) *models.User {
+	t.Helper()
+	if user.Name == "" {
+		user.Name = "測試使用者"
+	}
+	if user.Password == "" {
+		hash, err := utils.HashPassword(testPassword)
+		if err != nil {
+			t.Fatalf("HashPassword() 發生錯誤: %v", err)
+		}
+		user.Password = hash
+	}
+	userRepo.Create(user)
+	return user
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/pkg/throttle"
+	"my-api/app/pkg/totp"
+	"my-api/app/repositories"
+	"my-api/app/requests"
+	"my-api/app/responses"
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+const (
+	// recoveryCodeCount - 每次產生的復原碼數量
+	recoveryCodeCount = 8
+	// totpSkew - 容許前後幾個時間區間（30 秒）的時鐘誤差
+	totpSkew = 1
+	// challengeKeyPrefix - challenge token → 使用者 ID 的儲存 key
+	challengeKeyPrefix = "two-factor:challenge:"
+)
+
+var (
+	// ErrTwoFactorAlreadyEnabled - 已啟用兩步驟驗證
+	ErrTwoFactorAlreadyEnabled = errors.New("已啟用兩步驟驗證")
+	// ErrTwoFactorNotEnabled - 尚未啟用兩步驟驗證
+	ErrTwoFactorNotEnabled = errors.New("尚未啟用兩步驟驗證")
+	// ErrTwoFactorSetupRequired - 確認啟用前必須先產生金鑰
+	ErrTwoFactorSetupRequired = errors.New("請先產生兩步驟驗證金鑰")
+	// ErrInvalidTwoFactorCode - 驗證碼或復原碼錯誤
+	ErrInvalidTwoFactorCode = errors.New("驗證碼錯誤")
+	// ErrInvalidTwoFactorChallenge - challenge token 不存在或已過期
+	ErrInvalidTwoFactorChallenge = errors.New("兩步驟驗證已逾時，請重新登入")
+	// ErrIncorrectPassword - 敏感操作前再次確認的密碼錯誤
+	ErrIncorrectPassword = errors.New("密碼錯誤")
+)
+
+// TwoFactorService - 兩步驟驗證（TOTP）業務邏輯層介面
+//
+// 設定流程：Enable 產生金鑰 → 使用者掃描 QR Code → Confirm 輸入驗證碼後才正式啟用
+// 登入流程：AuthService.Login 密碼正確後呼叫 CreateChallenge，
+// 用戶端再以 challenge token + 驗證碼（或復原碼）呼叫 VerifyChallenge 換取 Token
+type TwoFactorService interface {
+	Enable(userID uint) (*responses.TwoFactorSetupResponse, error)
+	Confirm(userID uint, code string) (*responses.RecoveryCodesResponse, error)
+	Disable(userID uint, password string) error
+	RegenerateRecoveryCodes(userID uint, password string) (*responses.RecoveryCodesResponse, error)
+	CreateChallenge(userID uint) (string, error)
+	VerifyChallenge(req *requests.TwoFactorChallengeRequest) (*models.User, error)
+}
+
+// twoFactorService - 實作 TwoFactorService 介面
+type twoFactorService struct {
+	userRepo repositories.UserRepository
+	store    throttle.Store
+	attempts *throttle.Limiter
+}
+
+// NewTwoFactorService - 建立新的 TwoFactorService 實例
+// store 同時保存 challenge token（值為使用者 ID）與驗證碼錯誤次數
+func NewTwoFactorService(userRepo repositories.UserRepository, store throttle.Store) TwoFactorService {
+	cfg := config.GlobalConfig.Auth
+
+	return &twoFactorService{
+		userRepo: userRepo,
+		store:    store,
+		// 6 位數驗證碼只有一百萬種組合，錯誤次數與登入密碼共用同一套鎖定規則
+		attempts: throttle.NewLimiter(store, "two-factor", throttle.Policy{
+			MaxAttempts: cfg.LoginMaxAttemptsPerEmail,
+			BaseLockout: time.Duration(cfg.LoginLockoutSeconds) * time.Second,
+			MaxLockout:  time.Duration(cfg.LoginMaxLockoutSeconds) * time.Second,
+			Decay:       time.Duration(cfg.LoginDecayMinutes) * time.Minute,
+		}),
+	}
+}
+
+// Enable - 產生新的 TOTP 金鑰（尚未啟用，需 Confirm）
+// 重複呼叫會換一把新的金鑰，之前掃描的 QR Code 失效
+func (s *twoFactorService) Enable(userID uint) (*responses.TwoFactorSetupResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("使用者不存在")
+	}
+	if user.HasTwoFactorEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.New("產生兩步驟驗證金鑰失敗")
+	}
+	encrypted, err := utils.Encrypt(secret)
+	if err != nil {
+		return nil, errors.New("產生兩步驟驗證金鑰失敗")
+	}
+
+	user.TwoFactorSecret = encrypted
+	user.TwoFactorRecoveryCodes = nil
+	user.TwoFactorLastStep = 0
+	user.TwoFactorConfirmedAt = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("產生兩步驟驗證金鑰失敗")
+	}
+
+	return &responses.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(config.GlobalConfig.Auth.TwoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm - 輸入驗證器 App 的驗證碼，確認設定正確後正式啟用，回傳復原碼
+func (s *twoFactorService) Confirm(userID uint, code string) (*responses.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("使用者不存在")
+	}
+	if user.HasTwoFactorEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if user.TwoFactorSecret == "" {
+		return nil, ErrTwoFactorSetupRequired
+	}
+
+	step, ok := s.verifyCode(user, code)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	user.TwoFactorLastStep = step
+
+	codes, err := s.resetRecoveryCodes(user)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TwoFactorConfirmedAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("啟用兩步驟驗證失敗")
+	}
+
+	return &responses.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable - 停用兩步驟驗證（需再次輸入密碼）
+func (s *twoFactorService) Disable(userID uint, password string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("使用者不存在")
+	}
+	if !utils.CheckPassword(password, user.Password) {
+		return ErrIncorrectPassword
+	}
+	if user.TwoFactorSecret == "" {
+		return ErrTwoFactorNotEnabled
+	}
+
+	user.TwoFactorSecret = ""
+	user.TwoFactorRecoveryCodes = nil
+	user.TwoFactorLastStep = 0
+	user.TwoFactorConfirmedAt = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("停用兩步驟驗證失敗")
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes - 重新產生復原碼（需再次輸入密碼），舊的復原碼全部失效
+func (s *twoFactorService) RegenerateRecoveryCodes(userID uint, password string) (*responses.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("使用者不存在")
+	}
+	if !utils.CheckPassword(password, user.Password) {
+		return nil, ErrIncorrectPassword
+	}
+	if !user.HasTwoFactorEnabled() {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	codes, err := s.resetRecoveryCodes(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("產生復原碼失敗")
+	}
+
+	return &responses.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// CreateChallenge - 密碼驗證通過後，產生換取 Token 用的 challenge token
+func (s *twoFactorService) CreateChallenge(userID uint) (string, error) {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", errors.New("產生兩步驟驗證請求失敗")
+	}
+
+	ttl := time.Duration(config.GlobalConfig.Auth.TwoFactorChallengeMinutes) * time.Minute
+	if err := s.store.Set(challengeKeyPrefix+utils.HashToken(token), int64(userID), ttl); err != nil {
+		return "", errors.New("產生兩步驟驗證請求失敗")
+	}
+	return token, nil
+}
+
+// VerifyChallenge - 驗證 challenge token 與驗證碼（或復原碼），成功時回傳使用者
+// 錯誤太多次時回傳 *LockoutError；成功後 challenge token 失效
+func (s *twoFactorService) VerifyChallenge(req *requests.TwoFactorChallengeRequest) (*models.User, error) {
+	key := challengeKeyPrefix + utils.HashToken(req.ChallengeToken)
+
+	value, err := s.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if value == 0 {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+	userID := uint(value)
+	attemptKey := strconv.FormatUint(uint64(userID), 10)
+
+	retryAfter, err := s.attempts.RetryAfter(attemptKey)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		return nil, &LockoutError{RetryAfter: retryAfter}
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || !user.HasTwoFactorEnabled() {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+
+	var verified bool
+	if req.RecoveryCode != "" {
+		verified, err = s.useRecoveryCode(user, req.RecoveryCode)
+	} else {
+		verified, err = s.useCode(user, req.Code)
+	}
+	if err != nil {
+		return nil, errors.New("兩步驟驗證失敗")
+	}
+
+	if !verified {
+		_, lockout, err := s.attempts.Fail(attemptKey)
+		if err != nil {
+			return nil, err
+		}
+		if lockout > 0 {
+			// 鎖定後這個 challenge 也不能再用，需重新輸入密碼
+			_ = s.store.Delete(key)
+			return nil, &LockoutError{RetryAfter: lockout}
+		}
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	_ = s.store.Delete(key)
+	_ = s.attempts.Clear(attemptKey)
+	return user, nil
+}
+
+// verifyCode - 驗證 TOTP 驗證碼，回傳驗證碼的時間區間
+// 已經用過的時間區間（含更早的）一律拒絕，同一個驗證碼不能用兩次
+func (s *twoFactorService) verifyCode(user *models.User, code string) (int64, bool) {
+	secret, err := utils.Decrypt(user.TwoFactorSecret)
+	if err != nil {
+		return 0, false
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), totpSkew)
+	if !ok || step <= user.TwoFactorLastStep {
+		return 0, false
+	}
+	return step, true
+}
+
+// useCode - 驗證 TOTP 驗證碼並記錄使用的時間區間
+// 以條件式更新記錄，同一個驗證碼同時送出兩次時只有一個請求會通過
+func (s *twoFactorService) useCode(user *models.User, code string) (bool, error) {
+	step, ok := s.verifyCode(user, code)
+	if !ok {
+		return false, nil
+	}
+
+	updated, err := s.userRepo.UpdateTwoFactorLastStep(user.ID, step)
+	if err != nil || !updated {
+		return false, err
+	}
+	user.TwoFactorLastStep = step
+	return true, nil
+}
+
+// useRecoveryCode - 使用復原碼，成功時從列表中移除
+// 以條件式更新移除（復原碼仍與讀取時相同才寫入），同一個復原碼同時使用時只有一個請求會通過
+func (s *twoFactorService) useRecoveryCode(user *models.User, code string) (bool, error) {
+	hash := utils.HashToken(normalizeRecoveryCode(code))
+
+	for i, stored := range user.TwoFactorRecoveryCodes {
+		if stored != hash {
+			continue
+		}
+
+		remaining := make([]string, 0, len(user.TwoFactorRecoveryCodes)-1)
+		remaining = append(remaining, user.TwoFactorRecoveryCodes[:i]...)
+		remaining = append(remaining, user.TwoFactorRecoveryCodes[i+1:]...)
+
+		updated, err := s.userRepo.ReplaceRecoveryCodes(user.ID, user.TwoFactorRecoveryCodes, remaining)
+		if err != nil || !updated {
+			return false, err
+		}
+		user.TwoFactorRecoveryCodes = remaining
+		return true, nil
+	}
+	return false, nil
+}
+
+// resetRecoveryCodes - 產生新的復原碼，雜湊存入 user（呼叫端負責儲存），回傳明文
+func (s *twoFactorService) resetRecoveryCodes(user *models.User) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		bytes := make([]byte, 5)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, errors.New("產生復原碼失敗")
+		}
+		// 格式：xxxxx-xxxxx
+		raw := hex.EncodeToString(bytes)
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, utils.HashToken(code))
+	}
+
+	user.TwoFactorRecoveryCodes = hashes
+	return codes, nil
+}
+
+// normalizeRecoveryCode - 復原碼不分大小寫，忽略前後空白
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/pkg/throttle"
+	"my-api/app/pkg/totp"
+	"my-api/app/requests"
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// setupTwoFactorConfig 設定兩步驟驗證測試所需的 config：驗證碼錯 3 次鎖定
+func setupTwoFactorConfig() {
+	setupTestConfig()
+	config.GlobalConfig.App.Key = "test-app-key"
+	config.GlobalConfig.Auth = config.AuthConfig{
+		TwoFactorIssuer:           "My API",
+		TwoFactorChallengeMinutes: 5,
+		LoginMaxAttemptsPerEmail:  3,
+		LoginMaxAttemptsPerIP:     20,
+		LoginLockoutSeconds:       60,
+		LoginMaxLockoutSeconds:    300,
+		LoginDecayMinutes:         15,
+	}
+}
+
+// twoFactorTestEnv 兩步驟驗證測試所需的依賴
+type twoFactorTestEnv struct {
+	service  TwoFactorService
+	auth     AuthService
+	userRepo *mockUserRepository
+	user     *models.User
+}
+
+// newTwoFactorTestEnv 建立測試環境，並預先建立一個可登入的使用者（尚未啟用兩步驟驗證）
+func newTwoFactorTestEnv(t *testing.T) *twoFactorTestEnv {
+	t.Helper()
+	setupTwoFactorConfig()
+
+	deps := newTestServices(newMockUserRepository())
+	user := createTestUser(t, deps.userRepo, &models.User{Email: "2fa@example.com"})
+	return &twoFactorTestEnv{service: deps.twoFactor, auth: deps.authService(), userRepo: deps.userRepo, user: user}
+}
+
+// enroll 完成兩步驟驗證設定，回傳 TOTP 金鑰與復原碼
+func (env *twoFactorTestEnv) enroll(t *testing.T) (string, []string) {
+	t.Helper()
+
+	setup, err := env.service.Enable(env.user.ID)
+	if err != nil {
+		t.Fatalf("Enable() 發生錯誤: %v", err)
+	}
+	recovery, err := env.service.Confirm(env.user.ID, codeAt(t, setup.Secret, 0))
+	if err != nil {
+		t.Fatalf("Confirm() 發生錯誤: %v", err)
+	}
+	return setup.Secret, recovery.RecoveryCodes
+}
+
+// challenge 以正確密碼登入，回傳 challenge token
+func (env *twoFactorTestEnv) challenge(t *testing.T) string {
+	t.Helper()
+
+	login, err := env.auth.Login(&requests.LoginRequest{Email: "2fa@example.com", Password: testPassword}, testClient)
+	if err != nil {
+		t.Fatalf("Login() 發生錯誤: %v", err)
+	}
+	if !login.TwoFactorRequired || login.ChallengeToken == "" || login.AuthResponse != nil {
+		t.Fatalf("啟用兩步驟驗證後登入應該只回傳 challenge token: %+v", login)
+	}
+	return login.ChallengeToken
+}
+
+// codeAt 目前時間區間往後 offset 個區間的驗證碼
+// 設定時已用掉目前的區間，登入測試使用下一個區間（仍在容許的時鐘誤差內）
+func codeAt(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	code, err := totp.Code(secret, totp.Step(time.Now())+offset)
+	if err != nil {
+		t.Fatalf("totp.Code() 發生錯誤: %v", err)
+	}
+	return code
+}
+
+// ============================================================================
+// 測試案例
+// ============================================================================
+
+// TestTwoFactor_Enrollment 測試設定流程：金鑰加密保存、驗證碼確認後才啟用
+func TestTwoFactor_Enrollment(t *testing.T) {
+	env := newTwoFactorTestEnv(t)
+
+	setup, err := env.service.Enable(env.user.ID)
+	if err != nil {
+		t.Fatalf("Enable() 發生錯誤: %v", err)
+	}
+
+	if env.user.TwoFactorSecret == setup.Secret {
+		t.Error("TOTP 金鑰不應以明文儲存")
+	}
+	if decrypted, err := utils.Decrypt(env.user.TwoFactorSecret); err != nil || decrypted != setup.Secret {
+		t.Errorf("儲存的金鑰應該能以 APP_KEY 解密: %v", err)
+	}
+	if env.user.HasTwoFactorEnabled() {
+		t.Error("確認前不應啟用兩步驟驗證")
+	}
+
+	if _, err := env.service.Confirm(env.user.ID, "000000"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("預期 ErrInvalidTwoFactorCode，got %v", err)
+	}
+
+	recovery, err := env.service.Confirm(env.user.ID, codeAt(t, setup.Secret, 0))
+	if err != nil {
+		t.Fatalf("Confirm() 發生錯誤: %v", err)
+	}
+	if !env.user.HasTwoFactorEnabled() {
+		t.Error("確認後應該啟用兩步驟驗證")
+	}
+	if len(recovery.RecoveryCodes) != recoveryCodeCount {
+		t.Errorf("應該產生 %d 組復原碼，got %d", recoveryCodeCount, len(recovery.RecoveryCodes))
+	}
+	for _, stored := range env.user.TwoFactorRecoveryCodes {
+		for _, code := range recovery.RecoveryCodes {
+			if stored == code {
+				t.Error("復原碼不應以明文儲存")
+			}
+		}
+	}
+
+	if _, err := env.service.Enable(env.user.ID); !errors.Is(err, ErrTwoFactorAlreadyEnabled) {
+		t.Errorf("預期 ErrTwoFactorAlreadyEnabled，got %v", err)
+	}
+}
+
+// TestTwoFactor_LoginWithCode 測試登入 → challenge → 驗證碼換取 Token
+func TestTwoFactor_LoginWithCode(t *testing.T) {
+	env := newTwoFactorTestEnv(t)
+	secret, _ := env.enroll(t)
+
+	challenge := env.challenge(t)
+	code := codeAt(t, secret, 1)
+
+	tokens, err := env.auth.TwoFactorLogin(&requests.TwoFactorChallengeRequest{ChallengeToken: challenge, Code: code}, testClient)
+	if err != nil {
+		t.Fatalf("TwoFactorLogin() 發生錯誤: %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Error("兩步驟驗證成功後應該回傳 Token")
+	}
+
+	// challenge token 只能用一次
+	if _, err := env.auth.TwoFactorLogin(&requests.TwoFactorChallengeRequest{ChallengeToken: challenge, Code: code}, testClient); !errors.Is(err, ErrInvalidTwoFactorChallenge) {
+		t.Errorf("預期 ErrInvalidTwoFactorChallenge，got %v", err)
+	}
+
+	// 同一個驗證碼不能重複使用
+	again := env.challenge(t)
+	if _, err := env.auth.TwoFactorLogin(&requests.TwoFactorChallengeRequest{ChallengeToken: again, Code: code}, testClient); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("預期 ErrInvalidTwoFactorCode，got %v", err)
+	}
+}
+
+// TestTwoFactor_RecoveryCode 測試復原碼只能使用一次
+func TestTwoFactor_RecoveryCode(t *testing.T) {
+	env := newTwoFactorTestEnv(t)
+	_, recoveryCodes := env.enroll(t)
+
+	// 不分大小寫、忽略前後空白
+	req := &requests.TwoFactorChallengeRequest{ChallengeToken: env.challenge(t), RecoveryCode: "  " + recoveryCodes[0] + " "}
+	if _, err := env.auth.TwoFactorLogin(req, testClient); err != nil {
+		t.Fatalf("復原碼應該可以登入: %v", err)
+	}
+	if len(env.user.TwoFactorRecoveryCodes) != recoveryCodeCount-1 {
+		t.Errorf("使用後應剩下 %d 組復原碼，got %d", recoveryCodeCount-1, len(env.user.TwoFactorRecoveryCodes))
+	}
+
+	req = &requests.TwoFactorChallengeRequest{ChallengeToken: env.challenge(t), RecoveryCode: recoveryCodes[0]}
+	if _, err := env.auth.TwoFactorLogin(req, testClient); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Errorf("用過的復原碼應該失效，got %v", err)
+	}
+}
+
+// TestTwoFactor_Lockout 測試驗證碼錯誤太多次時鎖定，challenge 也跟著失效
+func TestTwoFactor_Lockout(t *testing.T) {
+	env := newTwoFactorTestEnv(t)
+	secret, _ := env.enroll(t)
+
+	challenge := env.challenge(t)
+	for i := 0; i < 2; i++ {
+		req := &requests.TwoFactorChallengeRequest{ChallengeToken: challenge, Code: "000000"}
+		if _, err := env.auth.TwoFactorLogin(req, testClient); !errors.Is(err, ErrInvalidTwoFactorCode) {
+			t.Fatalf("第 %d 次: 預期 ErrInvalidTwoFactorCode，got %v", i+1, err)
+		}
+	}
+
+	var lockout *LockoutError
+	if _, err := env.auth.TwoFactorLogin(&requests.TwoFactorChallengeRequest{ChallengeToken: challenge, Code: "000000"}, testClient); !errors.As(err, &lockout) {
+		t.Fatalf("預期 *LockoutError，got %v", err)
+	}
+
+	// 換一個 challenge 也還在鎖定中
+	req := &requests.TwoFactorChallengeRequest{ChallengeToken: env.challenge(t), Code: codeAt(t, secret, 1)}
+	if _, err := env.auth.TwoFactorLogin(req, testClient); !errors.As(err, &lockout) {
+		t.Errorf("鎖定期間即使驗證碼正確也應拒絕，got %v", err)
+	}
+}
+
+// TestTwoFactor_Disable 測試停用需要密碼，停用後登入直接回傳 Token
+func TestTwoFactor_Disable(t *testing.T) {
+	env := newTwoFactorTestEnv(t)
+	env.enroll(t)
+
+	if err := env.service.Disable(env.user.ID, "wrong-password"); !errors.Is(err, ErrIncorrectPassword) {
+		t.Errorf("預期 ErrIncorrectPassword，got %v", err)
+	}
+	if err := env.service.Disable(env.user.ID, "password123"); err != nil {
+		t.Fatalf("Disable() 發生錯誤: %v", err)
+	}
+
+	login, err := env.auth.Login(&requests.LoginRequest{Email: "2fa@example.com", Password: testPassword}, testClient)
+	if err != nil {
+		t.Fatalf("Login() 發生錯誤: %v", err)
+	}
+	if login.TwoFactorRequired || login.AuthResponse == nil || login.AccessToken == "" {
+		t.Errorf("停用後登入應該直接回傳 Token: %+v", login)
+	}
+}
+
+// staleUserRepository 模擬同時送出的另一個請求：FindByID 一律回傳先前讀到的使用者資料
+type staleUserRepository struct {
+	*mockUserRepository
+	snapshot models.User
+}
+
+func (r *staleUserRepository) FindByID(id uint) (*models.User, error) {
+	user := r.snapshot
+	return &user, nil
+}
+
+// TestTwoFactor_ConcurrentUse 測試兩個請求讀到同一份資料時，復原碼與驗證碼仍只能使用一次
+func TestTwoFactor_ConcurrentUse(t *testing.T) {
+	env := newTwoFactorTestEnv(t)
+	secret, recoveryCodes := env.enroll(t)
+	code := codeAt(t, secret, 1)
+
+	// 另一個請求在第一個請求寫入前就讀取了使用者
+	other := NewTwoFactorService(&staleUserRepository{mockUserRepository: env.userRepo, snapshot: *env.user}, throttle.NewMemoryStore())
+
+	tests := []struct {
+		name string
+		req  requests.TwoFactorChallengeRequest
+	}{
+		{name: "復原碼", req: requests.TwoFactorChallengeRequest{RecoveryCode: recoveryCodes[0]}},
+		{name: "驗證碼", req: requests.TwoFactorChallengeRequest{Code: code}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := tt.req, tt.req
+			first.ChallengeToken = createChallenge(t, env.service, env.user.ID)
+			second.ChallengeToken = createChallenge(t, other, env.user.ID)
+
+			if _, err := env.service.VerifyChallenge(&first); err != nil {
+				t.Fatalf("第一個請求應該通過: %v", err)
+			}
+			if _, err := other.VerifyChallenge(&second); !errors.Is(err, ErrInvalidTwoFactorCode) {
+				t.Errorf("預期 ErrInvalidTwoFactorCode，got %v", err)
+			}
+		})
+	}
+
+	if len(env.user.TwoFactorRecoveryCodes) != recoveryCodeCount-1 {
+		t.Errorf("應剩下 %d 組復原碼，got %d", recoveryCodeCount-1, len(env.user.TwoFactorRecoveryCodes))
+	}
+}
+
+// createChallenge 建立 challenge token
+func createChallenge(t *testing.T, service TwoFactorService, userID uint) string {
+	t.Helper()
+	token, err := service.CreateChallenge(userID)
+	if err != nil {
+		t.Fatalf("CreateChallenge() 發生錯誤: %v", err)
+	}
+	return token
+}
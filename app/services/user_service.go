@@ -6,6 +6,7 @@ import (
 	"my-api/app/repositories"
 	"my-api/app/requests"
 	"my-api/app/responses"
+	"my-api/app/utils"
 )
 
 // UserService - 使用者業務邏輯層介面
@@ -19,17 +20,24 @@ type UserService interface {
 
 // userService - 實作 UserService 介面
 type userService struct {
-	userRepo repositories.UserRepository
+	userRepo     repositories.UserRepository
+	verification EmailVerificationService
+	passwords    PasswordService
+	accounts     AccountService
 }
 
 // NewUserService - 建立新的 UserService 實例
-func NewUserService(userRepo repositories.UserRepository) UserService {
+func NewUserService(userRepo repositories.UserRepository, verification EmailVerificationService, passwords PasswordService, accounts AccountService) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:     userRepo,
+		verification: verification,
+		passwords:    passwords,
+		accounts:     accounts,
 	}
 }
 
 // CreateUser - 新增使用者（含業務邏輯）
+// 管理員不替使用者決定密碼：先設為無法登入的隨機密碼，再寄送設定密碼連結
 func (s *userService) CreateUser(req *requests.CreateUserRequest) (*responses.UserResponse, error) {
 	// 檢查 Email 是否已存在
 	existingUser, _ := s.userRepo.FindByEmail(req.Email)
@@ -37,17 +45,27 @@ func (s *userService) CreateUser(req *requests.CreateUserRequest) (*responses.Us
 		return nil, errors.New("電子郵件已被使用")
 	}
 
+	// 使用者必須透過設定密碼連結才能登入
+	hashedPassword, err := unusablePassword()
+	if err != nil {
+		return nil, errors.New("建立使用者失敗")
+	}
+
 	// 建立使用者
 	user := &models.User{
-		Name:  req.Name,
-		Email: req.Email,
-		Age:   req.Age,
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: hashedPassword,
+		Age:      req.Age,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, err
 	}
 
+	// 寄送失敗不影響建立，使用者可以透過 POST /api/password/forgot 重新取得連結
+	_ = s.passwords.SendSetPasswordLink(user)
+
 	// 轉換為 Response DTO
 	return responses.NewUserResponse(user), nil
 }
@@ -86,16 +104,20 @@ func (s *userService) UpdateUser(id uint, req *requests.UpdateUserRequest) (*res
 	}
 
 	// 只更新有提供的欄位
+	emailChanged := false
 	if req.Name != "" {
 		user.Name = req.Name
 	}
-	if req.Email != "" {
+	if req.Email != "" && req.Email != user.Email {
 		// 檢查新 Email 是否已被其他人使用
 		existingUser, _ := s.userRepo.FindByEmail(req.Email)
 		if existingUser != nil && existingUser.ID != id {
 			return nil, errors.New("電子郵件已被使用")
 		}
 		user.Email = req.Email
+		// 新的 Email 需要重新驗證
+		user.EmailVerifiedAt = nil
+		emailChanged = true
 	}
 	if req.Age > 0 {
 		user.Age = req.Age
@@ -105,10 +127,16 @@ func (s *userService) UpdateUser(id uint, req *requests.UpdateUserRequest) (*res
 		return nil, err
 	}
 
+	// 寄送驗證信到新的 Email；寄送失敗不影響更新，可透過 POST /api/email/resend 重新寄送
+	if emailChanged {
+		_ = s.verification.SendVerificationEmail(user)
+	}
+
 	return responses.NewUserResponse(user), nil
 }
 
 // DeleteUser - 刪除使用者
+// 與使用者自行刪除帳號期滿後相同：撤銷 Token、匿名化個人資料（沒有寬限期）
 func (s *userService) DeleteUser(id uint) error {
 	// 檢查使用者是否存在
 	_, err := s.userRepo.FindByID(id)
@@ -116,5 +144,14 @@ func (s *userService) DeleteUser(id uint) error {
 		return errors.New("使用者不存在")
 	}
 
-	return s.userRepo.Delete(id)
+	return s.accounts.Erase(id)
+}
+
+// unusablePassword - 沒有人知道的隨機密碼雜湊（帳號建立時尚未設定密碼）
+func unusablePassword() (string, error) {
+	token, err := utils.GenerateRandomToken()
+	if err != nil {
+		return "", err
+	}
+	return utils.HashPassword(token)
 }
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"my-api/app/models"
 	"my-api/app/requests"
+	"slices"
 	"testing"
+	"time"
 )
 
 // ============================================================================
@@ -58,12 +60,13 @@ func (m *mockUserRepository) Update(user *models.User) error {
 	if _, ok := m.users[user.ID]; !ok {
 		return errors.New("record not found")
 	}
-	// 更新 email 索引
-	oldUser := m.users[user.ID]
-	if oldUser.Email != user.Email {
-		delete(m.emailMap, oldUser.Email)
-		m.emailMap[user.Email] = user
+	// 更新 email 索引（user 可能就是 map 中的同一個指標，所以比對索引而不是舊資料）
+	for email, indexed := range m.emailMap {
+		if indexed.ID == user.ID && email != user.Email {
+			delete(m.emailMap, email)
+		}
 	}
+	m.emailMap[user.Email] = user
 	m.users[user.ID] = user
 	return nil
 }
@@ -86,6 +89,37 @@ func (m *mockUserRepository) FindByEmail(email string) (*models.User, error) {
 	return nil, errors.New("record not found")
 }
 
+// UpdateTwoFactorLastStep 模擬條件式更新：step 比目前記錄的新時才更新
+func (m *mockUserRepository) UpdateTwoFactorLastStep(id uint, step int64) (bool, error) {
+	user, ok := m.users[id]
+	if !ok || user.TwoFactorLastStep >= step {
+		return false, nil
+	}
+	user.TwoFactorLastStep = step
+	return true, nil
+}
+
+// ReplaceRecoveryCodes 模擬條件式更新：復原碼仍為 old 時才更新
+func (m *mockUserRepository) ReplaceRecoveryCodes(id uint, old, codes []string) (bool, error) {
+	user, ok := m.users[id]
+	if !ok || !slices.Equal(user.TwoFactorRecoveryCodes, old) {
+		return false, nil
+	}
+	user.TwoFactorRecoveryCodes = codes
+	return true, nil
+}
+
+// FindDueForDeletion 模擬查詢寬限期已過的使用者
+func (m *mockUserRepository) FindDueForDeletion(now time.Time) ([]models.User, error) {
+	var users []models.User
+	for _, user := range m.users {
+		if user.DeletionScheduledAt != nil && !user.DeletionScheduledAt.After(now) {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
 // ============================================================================
 // 測試案例
 // ============================================================================
@@ -93,7 +127,7 @@ func (m *mockUserRepository) FindByEmail(email string) (*models.User, error) {
 // TestUserService_CreateUser 測試新增使用者
 func TestUserService_CreateUser(t *testing.T) {
 	mockRepo := newMockUserRepository()
-	service := NewUserService(mockRepo)
+	service := newTestServices(mockRepo).userService()
 
 	// 測試案例
 	tests := []struct {
@@ -152,7 +186,7 @@ func TestUserService_CreateUser(t *testing.T) {
 // TestUserService_GetUserByID 測試根據 ID 取得使用者
 func TestUserService_GetUserByID(t *testing.T) {
 	mockRepo := newMockUserRepository()
-	service := NewUserService(mockRepo)
+	service := newTestServices(mockRepo).userService()
 
 	// 先新增一個使用者
 	mockRepo.Create(&models.User{
@@ -201,7 +235,7 @@ func TestUserService_GetUserByID(t *testing.T) {
 // TestUserService_UpdateUser 測試更新使用者
 func TestUserService_UpdateUser(t *testing.T) {
 	mockRepo := newMockUserRepository()
-	service := NewUserService(mockRepo)
+	service := newTestServices(mockRepo).userService()
 
 	// 先新增兩個使用者
 	mockRepo.Create(&models.User{
@@ -276,7 +310,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 // TestUserService_DeleteUser 測試刪除使用者
 func TestUserService_DeleteUser(t *testing.T) {
 	mockRepo := newMockUserRepository()
-	service := NewUserService(mockRepo)
+	service := newTestServices(mockRepo).userService()
 
 	// 先新增一個使用者
 	mockRepo.Create(&models.User{
@@ -322,7 +356,7 @@ func TestUserService_DeleteUser(t *testing.T) {
 // TestUserService_GetAllUsers 測試取得所有使用者
 func TestUserService_GetAllUsers(t *testing.T) {
 	mockRepo := newMockUserRepository()
-	service := NewUserService(mockRepo)
+	service := newTestServices(mockRepo).userService()
 
 	// 測試空列表
 	t.Run("空列表", func(t *testing.T) {
@@ -2,6 +2,7 @@ package traits
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -70,3 +71,13 @@ func RespondForbidden(c *gin.Context, message string) {
 		"message": message,
 	})
 }
+
+// RespondTooManyRequests - 請求過於頻繁回應（附帶 Retry-After header，單位秒）
+func RespondTooManyRequests(c *gin.Context, retryAfterSeconds int, message string) {
+	c.Header("Retry-After", strconv.Itoa(retryAfterSeconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"success":     false,
+		"message":     message,
+		"retry_after": retryAfterSeconds,
+	})
+}
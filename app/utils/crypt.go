@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+
+	"my-api/config"
+)
+
+// ErrDecryptionFailed - 密文格式錯誤、被竄改或金鑰不同
+var ErrDecryptionFailed = errors.New("解密失敗")
+
+// CheckAppKey - 檢查 APP_KEY 是否可以使用
+// 空值或範例值是公開的，任何人都能偽造簽章連結、解密 TOTP 金鑰
+func CheckAppKey(key string) error {
+	switch key {
+	case "":
+		return errors.New("APP_KEY 不可為空")
+	case config.PlaceholderAppKey:
+		return errors.New("APP_KEY 仍是範例值，請更換為隨機字串（例如 openssl rand -base64 32）")
+	}
+	return nil
+}
+
+// Encrypt - 以 APP_KEY 加密字串（類似 Laravel 的 Crypt::encryptString）
+// AES-256-GCM，輸出 Base64(nonce || ciphertext)；用於 TOTP 金鑰等需要還原的機密資料
+func Encrypt(plaintext string) (string, error) {
+	gcm, err := appCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt - 解密 Encrypt 產生的密文
+func Decrypt(encoded string) (string, error) {
+	gcm, err := appCipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", ErrDecryptionFailed
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrDecryptionFailed
+	}
+	return string(plaintext), nil
+}
+
+// appCipher - 由 APP_KEY 推導 256 bits 的 AES 金鑰
+// 更換 APP_KEY 後，已加密的資料（例如 TOTP 金鑰）將無法解密
+func appCipher() (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(config.GlobalConfig.App.Key))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"my-api/config"
+)
+
+// TestEncryptDecrypt 測試加密後可以還原，且每次密文不同
+func TestEncryptDecrypt(t *testing.T) {
+	config.GlobalConfig = &config.Config{App: config.AppConfig{Key: "test-app-key"}}
+
+	first, err := Encrypt("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Encrypt() 發生錯誤: %v", err)
+	}
+	second, _ := Encrypt("JBSWY3DPEHPK3PXP")
+
+	if first == second {
+		t.Error("相同明文每次加密的結果應該不同（隨機 nonce）")
+	}
+
+	plaintext, err := Decrypt(first)
+	if err != nil {
+		t.Fatalf("Decrypt() 發生錯誤: %v", err)
+	}
+	if plaintext != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Decrypt() = %s, want JBSWY3DPEHPK3PXP", plaintext)
+	}
+}
+
+// TestDecrypt_Invalid 測試竄改的密文與不同的 APP_KEY
+func TestDecrypt_Invalid(t *testing.T) {
+	config.GlobalConfig = &config.Config{App: config.AppConfig{Key: "test-app-key"}}
+	encrypted, _ := Encrypt("secret")
+
+	tampered := []byte(encrypted)
+	tampered[len(tampered)-2] ^= 1
+
+	tests := []struct {
+		name   string
+		appKey string
+		input  string
+	}{
+		{name: "不是 Base64", appKey: "test-app-key", input: "%%%"},
+		{name: "長度不足", appKey: "test-app-key", input: "AAAA"},
+		{name: "被竄改", appKey: "test-app-key", input: string(tampered)},
+		{name: "APP_KEY 不同", appKey: "another-app-key", input: encrypted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.GlobalConfig = &config.Config{App: config.AppConfig{Key: tt.appKey}}
+			if _, err := Decrypt(tt.input); !errors.Is(err, ErrDecryptionFailed) {
+				t.Errorf("預期 ErrDecryptionFailed，got %v", err)
+			}
+		})
+	}
+}
+
+// TestCheckAppKey 測試拒絕空值與範例值的 APP_KEY
+func TestCheckAppKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "空值", key: "", wantErr: true},
+		{name: "範例值", key: config.PlaceholderAppKey, wantErr: true},
+		{name: "隨機字串", key: "b3JZ6m0q1yQ8k2V9xW4tR7pL5nC1sD0e"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckAppKey(tt.key); (err != nil) != tt.wantErr {
+				t.Errorf("CheckAppKey(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,49 +5,89 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"my-api/config"
 )
 
 // JWTClaims - JWT 聲明結構
 type JWTClaims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID    uint     `json:"user_id"`
+	Email     string   `json:"email"`
+	Roles     []string `json:"roles,omitempty"` // 使用者角色名稱
+	SessionID string   `json:"sid,omitempty"`   // 登入工作階段（Refresh Token 的 FamilyID），撤銷裝置時用來拒絕 Token
 	jwt.RegisteredClaims
 }
 
 // GenerateToken - 產生 JWT Token
-func GenerateToken(userID uint, email string) (string, error) {
+// 每個 Token 都帶有唯一的 jti（RegisteredClaims.ID），登出時以 jti 加入撤銷清單；
+// header 的 kid 指出簽章金鑰，其他服務可從 /.well-known/jwks.json 取得對應公鑰驗證；
+// sessionID 寫入 sid，空字串時不寫入
+func GenerateToken(userID uint, email string, roles []string, sessionID string) (string, error) {
 	cfg := config.GlobalConfig.JWT
 
+	keys, err := CurrentKeySet()
+	if err != nil {
+		return "", err
+	}
+	key := keys.SigningKey()
+
 	claims := JWTClaims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		Roles:     roles,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID: uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(
-				time.Duration(cfg.ExpiryHours) * time.Hour,
+				time.Duration(cfg.AccessExpiryMinutes) * time.Minute,
 			)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    "my-api",
+			Issuer:    cfg.Issuer,
+			Audience:  cfg.Audience,
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(cfg.Secret))
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.sign)
 }
 
 // ValidateToken - 驗證並解析 JWT Token
+//
+// 依 header 的 kid 找驗證金鑰，且 Token 的演算法必須與該金鑰相同（避免 alg 混淆攻擊）；
+// 另外檢查 iss、aud（有設定時）與 exp，容許 JWT_LEEWAY_SECONDS 的時鐘誤差
 func ValidateToken(tokenString string) (*JWTClaims, error) {
 	cfg := config.GlobalConfig.JWT
 
+	keys, err := CurrentKeySet()
+	if err != nil {
+		return nil, err
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(keys.Methods()),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithLeeway(time.Duration(cfg.LeewaySeconds) * time.Second),
+		jwt.WithExpirationRequired(),
+	}
+	if len(cfg.Audience) > 0 {
+		options = append(options, jwt.WithAudience(cfg.Audience...))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keys.Lookup(kid)
+		if !ok {
+			return nil, errors.New("找不到對應的簽章金鑰")
+		}
 		// 驗證簽名演算法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, errors.New("無效的簽名方法")
 		}
-		return []byte(cfg.Secret), nil
-	})
+		return key.verify, nil
+	}, options...)
 
 	if err != nil {
 		return nil, err
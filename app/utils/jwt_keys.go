@@ -0,0 +1,334 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"my-api/config"
+)
+
+// hmacKeyID - HS256 金鑰的 kid（對稱金鑰不公開，也就不需要指紋）
+const hmacKeyID = "hs256"
+
+// JWTKey - 一把簽章 / 驗證金鑰
+type JWTKey struct {
+	ID     string            // kid；非對稱金鑰為公鑰的 RFC 7638 指紋，輪替後不會變動
+	Method jwt.SigningMethod // 這把金鑰使用的演算法
+	sign   interface{}       // 簽章用：HMAC secret 或私鑰（只驗證的金鑰為 nil）
+	verify interface{}       // 驗證用：HMAC secret 或公鑰
+}
+
+// KeySet - 目前的簽章金鑰 + 所有可接受的驗證金鑰
+//
+// 金鑰輪替流程：
+//  1. 產生新金鑰，公鑰先加入 JWT_PUBLIC_KEY_PATHS，讓其他服務的 JWKS 快取先拿到新公鑰
+//  2. JWT_PRIVATE_KEY_PATH 換成新私鑰，舊金鑰（公鑰或私鑰皆可）改放 JWT_PUBLIC_KEY_PATHS
+//  3. 舊 Token 全部過期後（JWT_ACCESS_EXPIRY_MINUTES），從 JWT_PUBLIC_KEY_PATHS 移除
+type KeySet struct {
+	signing *JWTKey
+	keys    map[string]*JWTKey
+	order   []string // JWKS 輸出順序：簽章金鑰在前
+}
+
+// LoadKeySet - 依設定載入金鑰
+// HS256 使用 JWT_SECRET；RS256 / ES256 / EdDSA 從 PEM 檔載入，且不再接受 HMAC 簽章的 Token
+func LoadKeySet(cfg config.JWTConfig) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*JWTKey)}
+
+	switch cfg.Algorithm {
+	case "", jwt.SigningMethodHS256.Alg():
+		if cfg.Secret == "" {
+			return nil, errors.New("JWT_SECRET 不可為空")
+		}
+		secret := []byte(cfg.Secret)
+		ks.add(&JWTKey{ID: hmacKeyID, Method: jwt.SigningMethodHS256, sign: secret, verify: secret})
+		ks.signing = ks.keys[hmacKeyID]
+
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg(), jwt.SigningMethodEdDSA.Alg():
+		if cfg.PrivateKeyPath == "" {
+			return nil, fmt.Errorf("使用 %s 時必須設定 JWT_PRIVATE_KEY_PATH", cfg.Algorithm)
+		}
+		key, err := loadPEMKey(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		if key.sign == nil {
+			return nil, fmt.Errorf("%s 不是私鑰", cfg.PrivateKeyPath)
+		}
+		if key.Method.Alg() != cfg.Algorithm {
+			return nil, fmt.Errorf("%s 是 %s 金鑰，與 JWT_ALGORITHM=%s 不符", cfg.PrivateKeyPath, key.Method.Alg(), cfg.Algorithm)
+		}
+		ks.add(key)
+		ks.signing = key
+
+	default:
+		return nil, fmt.Errorf("不支援的 JWT_ALGORITHM: %s", cfg.Algorithm)
+	}
+
+	for _, path := range cfg.PublicKeyPaths {
+		key, err := loadPEMKey(path)
+		if err != nil {
+			return nil, err
+		}
+		// 驗證金鑰即使給的是私鑰也只拿來驗證
+		key.sign = nil
+		ks.add(key)
+	}
+
+	return ks, nil
+}
+
+// add - 加入金鑰（同一把金鑰重複設定時以先加入的為準）
+func (ks *KeySet) add(key *JWTKey) {
+	if _, exists := ks.keys[key.ID]; exists {
+		return
+	}
+	ks.keys[key.ID] = key
+	ks.order = append(ks.order, key.ID)
+}
+
+// SigningKey - 目前用來簽發 Token 的金鑰
+func (ks *KeySet) SigningKey() *JWTKey {
+	return ks.signing
+}
+
+// Lookup - 依 kid 取得驗證金鑰
+func (ks *KeySet) Lookup(kid string) (*JWTKey, bool) {
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// Methods - 所有金鑰使用的演算法（驗證 Token 時的白名單）
+func (ks *KeySet) Methods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, kid := range ks.order {
+		alg := ks.keys[kid].Method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWK - JSON Web Key（RFC 7517），只包含公開資訊
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // EC / OKP 曲線
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS - JSON Web Key Set（GET /.well-known/jwks.json 的回應）
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS - 所有非對稱驗證金鑰的公鑰；HS256 的 secret 不會公開
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, kid := range ks.order {
+		key := ks.keys[kid]
+		jwk, err := publicJWK(key.verify)
+		if err != nil {
+			continue
+		}
+		jwk.Kid = key.ID
+		jwk.Use = "sig"
+		jwk.Alg = key.Method.Alg()
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+var (
+	activeKeySetMu sync.RWMutex
+	activeKeySet   *KeySet
+)
+
+// SetKeySet - 設定 GenerateToken / ValidateToken 使用的金鑰（bootstrap.InitJWT 於啟動時呼叫）
+func SetKeySet(ks *KeySet) {
+	activeKeySetMu.Lock()
+	defer activeKeySetMu.Unlock()
+	activeKeySet = ks
+}
+
+// CurrentKeySet - 目前使用的金鑰
+// 尚未呼叫 SetKeySet 時（測試、指令列工具）每次依 config.GlobalConfig 重新載入
+func CurrentKeySet() (*KeySet, error) {
+	activeKeySetMu.RLock()
+	ks := activeKeySet
+	activeKeySetMu.RUnlock()
+
+	if ks != nil {
+		return ks, nil
+	}
+	return LoadKeySet(config.GlobalConfig.JWT)
+}
+
+// loadPEMKey - 從 PEM 檔載入金鑰，支援 PKCS#1、PKCS#8、SEC 1 私鑰，PKIX 公鑰與 X.509 憑證
+func loadPEMKey(path string) (*JWTKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("讀取金鑰檔 %s 失敗: %v", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s 不是 PEM 格式", path)
+	}
+
+	var private crypto.Signer
+	var public crypto.PublicKey
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, parseErr := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if parseErr != nil {
+			err = parseErr
+			break
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			err = errors.New("不支援的私鑰類型")
+			break
+		}
+		private = signer
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		private, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			public = cert.PublicKey
+		}
+	default:
+		err = fmt.Errorf("不支援的 PEM 類型 %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析金鑰檔 %s 失敗: %v", path, err)
+	}
+
+	if private != nil {
+		public = private.Public()
+	}
+
+	method, err := signingMethodFor(public)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	kid, err := thumbprint(public)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	key := &JWTKey{ID: kid, Method: method, verify: public}
+	if private != nil {
+		key.sign = private
+	}
+	return key, nil
+}
+
+// signingMethodFor - 依公鑰類型決定演算法
+func signingMethodFor(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < 2048 {
+			return nil, errors.New("RSA 金鑰長度至少需要 2048 bits")
+		}
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return nil, errors.New("ES256 只支援 P-256 曲線")
+		}
+		return jwt.SigningMethodES256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("不支援的金鑰類型 %T", public)
+	}
+}
+
+// publicJWK - 公鑰轉成 JWK（只填入金鑰本身的欄位）
+func publicJWK(public interface{}) (JWK, error) {
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   b64(pub.N.Bytes()),
+			E:   b64(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return JWK{}, err
+		}
+		// 未壓縮格式：0x04 || X || Y
+		point := ecdhKey.Bytes()[1:]
+		size := len(point) / 2
+		return JWK{
+			Kty: "EC",
+			Crv: pub.Curve.Params().Name,
+			X:   b64(point[:size]),
+			Y:   b64(point[size:]),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: b64(pub)}, nil
+	default:
+		return JWK{}, fmt.Errorf("無法公開的金鑰類型 %T", public)
+	}
+}
+
+// thumbprint - RFC 7638 JWK 指紋，作為 kid 使用
+// 只取必要欄位、依字母排序後做 SHA-256，同一把公鑰在任何地方算出來都一樣
+func thumbprint(public crypto.PublicKey) (string, error) {
+	jwk, err := publicJWK(public)
+	if err != nil {
+		return "", err
+	}
+
+	var members map[string]string
+	switch jwk.Kty {
+	case "RSA":
+		members = map[string]string{"e": jwk.E, "kty": jwk.Kty, "n": jwk.N}
+	case "EC":
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X, "y": jwk.Y}
+	default:
+		members = map[string]string{"crv": jwk.Crv, "kty": jwk.Kty, "x": jwk.X}
+	}
+
+	// encoding/json 輸出 map 時會依 key 排序，且不含空白
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
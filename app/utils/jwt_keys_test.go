@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"my-api/config"
+)
+
+// writeKeyPair 產生金鑰並寫入 PEM 檔，回傳 私鑰路徑、公鑰路徑
+func writeKeyPair(t *testing.T, alg string) (string, string) {
+	t.Helper()
+
+	var private crypto.Signer
+	var err error
+	switch alg {
+	case "RS256":
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ES256":
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "EdDSA":
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatalf("產生 %s 金鑰失敗: %v", alg, err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() 發生錯誤: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey() 發生錯誤: %v", err)
+	}
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "private.pem")
+	publicPath := filepath.Join(dir, "public.pem")
+	os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600)
+	os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0644)
+	return privatePath, publicPath
+}
+
+// setupJWTConfig 設定 JWT 測試用的 config（未呼叫 SetKeySet，每次依 config 載入金鑰）
+func setupJWTConfig(jwtConfig config.JWTConfig) {
+	if jwtConfig.Secret == "" {
+		jwtConfig.Secret = "test-secret"
+	}
+	if jwtConfig.Issuer == "" {
+		jwtConfig.Issuer = "my-api"
+	}
+	jwtConfig.AccessExpiryMinutes = 15
+	config.GlobalConfig = &config.Config{JWT: jwtConfig}
+}
+
+// signClaims 以目前的簽章金鑰簽發自訂 claims 的 Token
+func signClaims(t *testing.T, claims JWTClaims) string {
+	t.Helper()
+	keys, err := CurrentKeySet()
+	if err != nil {
+		t.Fatalf("CurrentKeySet() 發生錯誤: %v", err)
+	}
+	key := keys.SigningKey()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.sign)
+	if err != nil {
+		t.Fatalf("簽發 Token 失敗: %v", err)
+	}
+	return signed
+}
+
+// TestJWT_AsymmetricAlgorithms 測試 RS256 / ES256 / EdDSA 簽發與驗證，kid 與 JWKS 一致
+func TestJWT_AsymmetricAlgorithms(t *testing.T) {
+	for _, alg := range []string{"RS256", "ES256", "EdDSA"} {
+		t.Run(alg, func(t *testing.T) {
+			privatePath, _ := writeKeyPair(t, alg)
+			setupJWTConfig(config.JWTConfig{Algorithm: alg, PrivateKeyPath: privatePath})
+
+			signed, err := GenerateToken(1, "jwt@example.com", []string{"user"}, "")
+			if err != nil {
+				t.Fatalf("GenerateToken() 發生錯誤: %v", err)
+			}
+
+			claims, err := ValidateToken(signed)
+			if err != nil {
+				t.Fatalf("ValidateToken() 發生錯誤: %v", err)
+			}
+			if claims.UserID != 1 || claims.Email != "jwt@example.com" {
+				t.Errorf("claims 不符: %+v", claims)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(signed, &JWTClaims{})
+			if err != nil {
+				t.Fatalf("ParseUnverified() 發生錯誤: %v", err)
+			}
+			if parsed.Method.Alg() != alg {
+				t.Errorf("alg = %s, want %s", parsed.Method.Alg(), alg)
+			}
+
+			keys, _ := CurrentKeySet()
+			jwks := keys.JWKS()
+			if len(jwks.Keys) != 1 {
+				t.Fatalf("JWKS 應該有 1 把金鑰，got %d", len(jwks.Keys))
+			}
+			if parsed.Header["kid"] != jwks.Keys[0].Kid || jwks.Keys[0].Alg != alg || jwks.Keys[0].Use != "sig" {
+				t.Errorf("kid / JWK 不符: header=%v jwk=%+v", parsed.Header["kid"], jwks.Keys[0])
+			}
+		})
+	}
+}
+
+// TestJWT_KeyRotation 測試金鑰輪替：舊 Token 在舊金鑰移除前仍然有效
+func TestJWT_KeyRotation(t *testing.T) {
+	oldPrivate, oldPublic := writeKeyPair(t, "RS256")
+	newPrivate, _ := writeKeyPair(t, "ES256")
+
+	setupJWTConfig(config.JWTConfig{Algorithm: "RS256", PrivateKeyPath: oldPrivate})
+	oldToken, err := GenerateToken(1, "rotate@example.com", nil, "")
+	if err != nil {
+		t.Fatalf("GenerateToken() 發生錯誤: %v", err)
+	}
+
+	// 換上新私鑰，舊公鑰保留給驗證
+	setupJWTConfig(config.JWTConfig{Algorithm: "ES256", PrivateKeyPath: newPrivate, PublicKeyPaths: []string{oldPublic}})
+	newToken, err := GenerateToken(1, "rotate@example.com", nil, "")
+	if err != nil {
+		t.Fatalf("GenerateToken() 發生錯誤: %v", err)
+	}
+
+	for name, token := range map[string]string{"舊金鑰": oldToken, "新金鑰": newToken} {
+		if _, err := ValidateToken(token); err != nil {
+			t.Errorf("%s簽發的 Token 應該有效: %v", name, err)
+		}
+	}
+
+	keys, _ := CurrentKeySet()
+	jwks := keys.JWKS()
+	if len(jwks.Keys) != 2 || jwks.Keys[0].Alg != "ES256" || jwks.Keys[1].Alg != "RS256" {
+		t.Errorf("JWKS 應該依序包含新、舊金鑰: %+v", jwks.Keys)
+	}
+
+	// 舊金鑰移除後，舊 Token 失效
+	setupJWTConfig(config.JWTConfig{Algorithm: "ES256", PrivateKeyPath: newPrivate})
+	if _, err := ValidateToken(oldToken); err == nil {
+		t.Error("舊金鑰移除後，舊 Token 應該失效")
+	}
+	if _, err := ValidateToken(newToken); err != nil {
+		t.Errorf("新 Token 應該有效: %v", err)
+	}
+}
+
+// TestJWT_RegisteredClaimsValidation 測試 iss、aud 與時鐘誤差
+func TestJWT_RegisteredClaimsValidation(t *testing.T) {
+	privatePath, _ := writeKeyPair(t, "EdDSA")
+	base := config.JWTConfig{Algorithm: "EdDSA", PrivateKeyPath: privatePath, Audience: []string{"api"}}
+
+	claimsWith := func(issuer string, audience []string, expiresAt time.Time) JWTClaims {
+		return JWTClaims{UserID: 1, RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Audience:  audience,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		}}
+	}
+	valid := time.Now().Add(time.Minute)
+	justExpired := time.Now().Add(-5 * time.Second)
+
+	tests := []struct {
+		name    string
+		leeway  int
+		claims  JWTClaims
+		wantErr bool
+	}{
+		{name: "正確的 iss / aud", claims: claimsWith("my-api", []string{"api"}, valid)},
+		{name: "其中一個 aud 符合", claims: claimsWith("my-api", []string{"other", "api"}, valid)},
+		{name: "錯誤的 iss", claims: claimsWith("evil", []string{"api"}, valid), wantErr: true},
+		{name: "錯誤的 aud", claims: claimsWith("my-api", []string{"other"}, valid), wantErr: true},
+		{name: "缺少 aud", claims: claimsWith("my-api", nil, valid), wantErr: true},
+		{name: "剛過期且無容許誤差", claims: claimsWith("my-api", []string{"api"}, justExpired), wantErr: true},
+		{name: "剛過期但在容許誤差內", leeway: 30, claims: claimsWith("my-api", []string{"api"}, justExpired)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			cfg.LeewaySeconds = tt.leeway
+			setupJWTConfig(cfg)
+
+			_, err := ValidateToken(signClaims(t, tt.claims))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestJWT_RejectsAlgorithmConfusion 測試改用非對稱金鑰後，不再接受 HMAC 簽章的 Token
+func TestJWT_RejectsAlgorithmConfusion(t *testing.T) {
+	setupJWTConfig(config.JWTConfig{})
+	hmacToken, err := GenerateToken(1, "hmac@example.com", nil, "")
+	if err != nil {
+		t.Fatalf("GenerateToken() 發生錯誤: %v", err)
+	}
+	if _, err := ValidateToken(hmacToken); err != nil {
+		t.Fatalf("HS256 Token 應該有效: %v", err)
+	}
+
+	keys, _ := CurrentKeySet()
+	if len(keys.JWKS().Keys) != 0 {
+		t.Error("HS256 的 secret 不應出現在 JWKS")
+	}
+
+	privatePath, _ := writeKeyPair(t, "RS256")
+	setupJWTConfig(config.JWTConfig{Algorithm: "RS256", PrivateKeyPath: privatePath})
+	if _, err := ValidateToken(hmacToken); err == nil {
+		t.Error("使用 RS256 時不應接受 HMAC 簽章的 Token")
+	}
+}
+
+// TestLoadKeySet_Invalid 測試錯誤的金鑰設定
+func TestLoadKeySet_Invalid(t *testing.T) {
+	rsaPrivate, rsaPublic := writeKeyPair(t, "RS256")
+
+	tests := []struct {
+		name string
+		cfg  config.JWTConfig
+	}{
+		{name: "不支援的演算法", cfg: config.JWTConfig{Algorithm: "none"}},
+		{name: "缺少私鑰路徑", cfg: config.JWTConfig{Algorithm: "RS256"}},
+		{name: "演算法與金鑰不符", cfg: config.JWTConfig{Algorithm: "ES256", PrivateKeyPath: rsaPrivate}},
+		{name: "簽章金鑰不是私鑰", cfg: config.JWTConfig{Algorithm: "RS256", PrivateKeyPath: rsaPublic}},
+		{name: "驗證金鑰不存在", cfg: config.JWTConfig{Algorithm: "RS256", PrivateKeyPath: rsaPrivate, PublicKeyPaths: []string{"missing.pem"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadKeySet(tt.cfg); err == nil {
+				t.Error("預期錯誤，但成功載入")
+			}
+		})
+	}
+}
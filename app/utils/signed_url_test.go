@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"my-api/config"
+)
+
+// TestSignedURL 測試簽章連結的產生與驗證
+func TestSignedURL(t *testing.T) {
+	config.GlobalConfig = &config.Config{App: config.AppConfig{Key: "test-app-key"}}
+
+	signed, err := SignURL("https://api.example.com/api/email/verify/1/abc", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SignURL() 發生錯誤: %v", err)
+	}
+
+	parse := func(raw string) *url.URL {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("url.Parse() 發生錯誤: %v", err)
+		}
+		return u
+	}
+
+	// 原始連結有效，換 host 也有效（簽章不含 host）
+	if err := ValidateSignedURL(parse(signed)); err != nil {
+		t.Errorf("簽章連結應該有效: %v", err)
+	}
+	moved := parse(signed)
+	moved.Host = "internal:8080"
+	if err := ValidateSignedURL(moved); err != nil {
+		t.Errorf("換 host 後簽章連結應該仍然有效: %v", err)
+	}
+
+	// 竄改 path
+	tampered := parse(signed)
+	tampered.Path = "/api/email/verify/2/abc"
+	if err := ValidateSignedURL(tampered); err != ErrInvalidSignature {
+		t.Errorf("竄改 path 預期 ErrInvalidSignature，got %v", err)
+	}
+
+	// 竄改過期時間
+	extended := parse(signed)
+	query := extended.Query()
+	query.Set("expires", "9999999999")
+	extended.RawQuery = query.Encode()
+	if err := ValidateSignedURL(extended); err != ErrInvalidSignature {
+		t.Errorf("竄改 expires 預期 ErrInvalidSignature，got %v", err)
+	}
+
+	// 不同金鑰
+	config.GlobalConfig.App.Key = "another-key"
+	if err := ValidateSignedURL(parse(signed)); err != ErrInvalidSignature {
+		t.Errorf("不同金鑰預期 ErrInvalidSignature，got %v", err)
+	}
+	config.GlobalConfig.App.Key = "test-app-key"
+
+	// 已過期
+	expired, _ := SignURL("https://api.example.com/api/email/verify/1/abc", time.Now().Add(-time.Minute))
+	if err := ValidateSignedURL(parse(expired)); err != ErrSignatureExpired {
+		t.Errorf("過期連結預期 ErrSignatureExpired，got %v", err)
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateRandomToken - 產生高強度隨機字串（用於 Refresh Token 等不透明憑證）
+// 32 bytes 隨機數，以 URL-safe Base64 編碼輸出
+func GenerateRandomToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// HashToken - 計算憑證的 SHA-256 雜湊（資料庫只存雜湊，不存明文）
+// 隨機憑證本身熵值足夠，不需要 bcrypt 這類慢雜湊
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
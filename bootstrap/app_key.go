@@ -0,0 +1,23 @@
+package bootstrap
+
+import (
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// InitAppKey 檢查 APP_KEY（必須在 InitLogger 之後）
+// 正式環境使用空值或範例值時直接中止啟動，其他環境只記錄警告
+func InitAppKey() {
+	cfg := config.GlobalConfig.App
+
+	if err := utils.CheckAppKey(cfg.Key); err != nil {
+		if cfg.Env == "production" {
+			Log.Fatal("APP_KEY 設定錯誤", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+		Log.Warning("APP_KEY 設定不安全，正式環境將無法啟動", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
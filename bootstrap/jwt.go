@@ -0,0 +1,26 @@
+package bootstrap
+
+import (
+	"my-api/app/utils"
+	"my-api/config"
+)
+
+// InitJWT 載入 JWT 簽章 / 驗證金鑰（必須在 InitLogger 之後）
+// 金鑰檔有誤時直接中止啟動，避免簽發出其他服務無法驗證的 Token
+func InitJWT() {
+	cfg := config.GlobalConfig.JWT
+
+	keys, err := utils.LoadKeySet(cfg)
+	if err != nil {
+		Log.Fatal("JWT 金鑰載入失敗", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	utils.SetKeySet(keys)
+
+	Log.Info("JWT 金鑰載入成功", map[string]interface{}{
+		"algorithm":         keys.SigningKey().Method.Alg(),
+		"kid":               keys.SigningKey().ID,
+		"verification_keys": len(keys.JWKS().Keys),
+	})
+}
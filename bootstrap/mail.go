@@ -0,0 +1,26 @@
+package bootstrap
+
+import (
+	"my-api/app/pkg/mail"
+	"my-api/config"
+)
+
+// Mailer 全域 Mailer 實例
+var Mailer mail.Mailer
+
+// InitMailer 依 MAIL_DRIVER 初始化 Mailer（必須在 InitLogger 之後）
+func InitMailer() {
+	cfg := config.GlobalConfig.Mail
+
+	mailer, err := mail.New(cfg, Log)
+	if err != nil {
+		Log.Fatal("Mailer 初始化失敗", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+	Mailer = mailer
+
+	Log.Info("Mailer 初始化成功", map[string]interface{}{
+		"driver": cfg.Driver,
+	})
+}
@@ -0,0 +1,34 @@
+package bootstrap
+
+import (
+	"my-api/app/pkg/password"
+	"my-api/config"
+)
+
+// InitPasswordPolicy 依設定建立密碼規則（註冊、變更密碼、重設密碼共用）
+func InitPasswordPolicy() {
+	cfg := config.GlobalConfig.Auth
+
+	policy := password.Min(cfg.PasswordMinLength)
+	if cfg.PasswordRequireMixedCase {
+		policy.MixedCase()
+	}
+	if cfg.PasswordRequireNumbers {
+		policy.Numbers()
+	}
+	if cfg.PasswordRequireSymbols {
+		policy.Symbols()
+	}
+	if cfg.PasswordRejectUserInfo {
+		policy.NotContainingUserInfo()
+	}
+	password.SetDefault(policy)
+
+	Log.Info("密碼規則設定完成", map[string]interface{}{
+		"min_length":       cfg.PasswordMinLength,
+		"mixed_case":       cfg.PasswordRequireMixedCase,
+		"numbers":          cfg.PasswordRequireNumbers,
+		"symbols":          cfg.PasswordRequireSymbols,
+		"reject_user_info": cfg.PasswordRejectUserInfo,
+	})
+}
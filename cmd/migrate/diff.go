@@ -0,0 +1,432 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"my-api/config"
+	"my-api/database"
+)
+
+// schemaDiffReport - schema:diff --json 的輸出格式
+type schemaDiffReport struct {
+	Dialect     string               `json:"dialect"`
+	Tables      []database.TableDiff `json:"tables"`
+	Differences int                  `json:"differences"` // 需要修正的差異
+	Extras      int                  `json:"extras"`      // 資料庫多出 model 沒有的欄位、索引與外鍵（僅供參考）
+}
+
+// runSchemaDiff - schema:diff 命令：比對 GORM models 與資料庫，有需要修正的差異時 exit code 為 1
+func runSchemaDiff(args []string) {
+	diffCmd := flag.NewFlagSet("schema:diff", flag.ExitOnError)
+	asJSON := diffCmd.Bool("json", false, "以 JSON 格式輸出")
+	makeSync := diffCmd.Bool("make", false, "產生讓資料庫與 models 一致的 migration")
+	name := diffCmd.String("name", "sync_schema_with_models", "--make 產生的 migration 名稱")
+	diffCmd.Parse(args)
+
+	tables, err := database.DiffSchema(database.Models...)
+	if err != nil {
+		log.Fatal("❌ 無法比對資料庫結構:", err)
+	}
+
+	report := schemaDiffReport{Dialect: config.GlobalConfig.Database.Type, Tables: tables}
+	for _, table := range tables {
+		for _, d := range table.Differences {
+			if d.Extra() {
+				report.Extras++
+			} else {
+				report.Differences++
+			}
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatal("❌ 無法輸出差異:", err)
+		}
+	} else {
+		printSchemaDiff(report)
+	}
+
+	if *makeSync {
+		if report.Differences == 0 {
+			fmt.Fprintln(os.Stderr, "✓ 沒有需要修正的差異，不會建立 migration")
+		} else {
+			filename, err := makeSyncMigration(migrationsDir, *name, tables)
+			if err != nil {
+				log.Fatal("❌ ", err)
+			}
+			fmt.Fprintf(os.Stderr, "✅ 已建立 Migration: %s（執行前請確認內容，可以先用 migrate --pretend 檢查 SQL）\n", filename)
+		}
+	}
+
+	if report.Differences > 0 {
+		os.Exit(1)
+	}
+}
+
+func printSchemaDiff(report schemaDiffReport) {
+	fmt.Printf("🔍 比對 GORM models 與資料庫結構（%s）:\n", report.Dialect)
+	fmt.Println()
+
+	for _, table := range report.Tables {
+		fmt.Printf("  %s（%s）\n", table.Table, table.Model)
+		if len(table.Differences) == 0 {
+			fmt.Println("    ✅ 一致")
+			continue
+		}
+		for _, d := range table.Differences {
+			fmt.Println("    " + describeDifference(d))
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("共 %d 個資料表，需要修正的差異 %d 個", len(report.Tables), report.Differences)
+	if report.Extras > 0 {
+		fmt.Printf("，資料庫多出 model 沒有的項目 %d 個（僅供參考）", report.Extras)
+	}
+	fmt.Println()
+
+	if report.Differences > 0 {
+		fmt.Println("執行 schema:diff --make 產生讓資料庫與 models 一致的 migration，或修改 model 的 gorm tag")
+	}
+}
+
+// describeDifference - 例如 ⚠️  欄位 name：資料庫為 varchar(255) NOT NULL，model 為 varchar(100) NOT NULL
+func describeDifference(d database.Difference) string {
+	switch d.Kind {
+	case database.DiffMissingTable:
+		return "⚠️  資料表不存在"
+	case database.DiffMissingColumn:
+		return fmt.Sprintf("⚠️  缺少欄位 %s：%s", d.Name, d.Expected)
+	case database.DiffColumnChanged:
+		return fmt.Sprintf("⚠️  欄位 %s：資料庫為 %s，model 為 %s", d.Name, d.Actual, d.Expected)
+	case database.DiffExtraColumn:
+		return fmt.Sprintf("ℹ️  model 沒有的欄位 %s：%s", d.Name, d.Actual)
+	case database.DiffMissingIndex:
+		return fmt.Sprintf("⚠️  缺少索引 %s %s", d.Name, d.Expected)
+	case database.DiffIndexChanged:
+		return fmt.Sprintf("⚠️  索引 %s：資料庫為 %s %s，model 為 %s", d.Name, d.DatabaseIndex.Name, d.Actual, d.Expected)
+	case database.DiffExtraIndex:
+		return fmt.Sprintf("ℹ️  model 沒有的索引 %s %s", d.Name, d.Actual)
+	case database.DiffMissingForeignKey:
+		return fmt.Sprintf("⚠️  缺少外鍵 %s %s", d.Name, d.Expected)
+	case database.DiffForeignKeyChanged:
+		return fmt.Sprintf("⚠️  外鍵 %s：資料庫為 %s，model 為 %s", d.Name, d.Actual, d.Expected)
+	case database.DiffExtraForeignKey:
+		return fmt.Sprintf("ℹ️  model 沒有的外鍵 %s %s", d.Name, d.Actual)
+	default:
+		return fmt.Sprintf("⚠️  %s %s", d.Kind, d.Name)
+	}
+}
+
+// syncMigrationStub - schema:diff --make 產生 migration 的參數
+type syncMigrationStub struct {
+	migrationStub
+	Up   []string // schema.Command 的原始碼
+	Down []string
+}
+
+// makeSyncMigration - 依差異產生 migration，只會建立資料表或新增、修改欄位、索引與外鍵
+// 資料庫多出 model 沒有的項目不會刪除（model 不一定會宣告所有的結構），需要時請手動加入
+func makeSyncMigration(dir, name string, tables []database.TableDiff) (string, error) {
+	base, err := newMigrationStub(dir, name)
+	if err != nil {
+		return "", err
+	}
+
+	stub := syncMigrationStub{migrationStub: base}
+	for _, table := range tables {
+		up, down := syncCommands(table)
+		if up == "" {
+			continue
+		}
+		stub.Up = append(stub.Up, up)
+		stub.Down = append(stub.Down, down)
+	}
+	// 依相反的順序回滾，例如先刪除參照其他資料表的外鍵
+	slices.Reverse(stub.Down)
+
+	var buf bytes.Buffer
+	if err := syncMigrationTemplate.Execute(&buf, stub); err != nil {
+		return "", err
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("產生 migration 失敗: %v", err)
+	}
+
+	filename := filepath.Join(dir, fmt.Sprintf("%s_%s.go", stub.Version, stub.Name))
+	if err := createFile(filename, source); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
+// syncCommands - 一個資料表的 Up 與 Down（schema.Command 的原始碼），沒有需要修正的差異時為空字串
+func syncCommands(table database.TableDiff) (up, down string) {
+	if len(table.Differences) == 1 && table.Differences[0].Kind == database.DiffMissingTable {
+		return createTableSource(table.Definition), fmt.Sprintf("schema.DropIfExists(%q)", table.Table)
+	}
+
+	var upLines, downLines []string
+	for _, d := range table.Differences {
+		switch d.Kind {
+		case database.DiffMissingColumn:
+			upLines = append(upLines, columnSource(*d.ModelColumn, ""))
+			downLines = append(downLines, fmt.Sprintf("t.DropColumn(%q)", d.Name))
+		case database.DiffColumnChanged:
+			upLines = append(upLines, columnSource(*d.ModelColumn, ".Change()"))
+			downLines = append(downLines, columnSource(*d.DatabaseColumn, ".Change()"))
+		case database.DiffMissingIndex:
+			upLines = append(upLines, indexSource(table.Table, *d.ModelIndex))
+			downLines = append(downLines, fmt.Sprintf("t.DropIndex(%q)", d.ModelIndex.Name))
+		case database.DiffIndexChanged:
+			upLines = append(upLines, fmt.Sprintf("t.DropIndex(%q)", d.DatabaseIndex.Name), indexSource(table.Table, *d.ModelIndex))
+			downLines = append(downLines, fmt.Sprintf("t.DropIndex(%q)", d.ModelIndex.Name), indexSource(table.Table, *d.DatabaseIndex))
+		case database.DiffMissingForeignKey:
+			upLines = append(upLines, foreignKeySource(*d.ModelForeignKey))
+			downLines = append(downLines, fmt.Sprintf("t.DropForeign(%q)", d.ModelForeignKey.Name))
+		case database.DiffForeignKeyChanged:
+			// 沿用資料庫原本的外鍵名稱，只修改 ON DELETE
+			fk := *d.ModelForeignKey
+			fk.Name = d.DatabaseForeignKey.Name
+			upLines = append(upLines, fmt.Sprintf("t.DropForeign(%q)", fk.Name), foreignKeySource(fk))
+			downLines = append(downLines, fmt.Sprintf("t.DropForeign(%q)", fk.Name), foreignKeySource(*d.DatabaseForeignKey))
+		}
+	}
+
+	if len(upLines) == 0 {
+		return "", ""
+	}
+	return tableSource("Table", table.Table, upLines), tableSource("Table", table.Table, downLines)
+}
+
+// createTableSource - 建立 model 資料表的 schema.Create
+func createTableSource(table database.TableDefinition) string {
+	var lines, primary []string
+	for _, c := range table.Columns {
+		lines = append(lines, columnSource(c, ""))
+		if c.PrimaryKey && !c.AutoIncrement {
+			primary = append(primary, strconv.Quote(c.Name))
+		}
+	}
+	if len(primary) > 0 {
+		lines = append(lines, "t.Primary("+strings.Join(primary, ", ")+")")
+	}
+	for _, i := range table.Indexes {
+		lines = append(lines, indexSource(table.Name, i))
+	}
+	for _, fk := range table.ForeignKeys {
+		lines = append(lines, foreignKeySource(fk))
+	}
+	return tableSource("Create", table.Name, lines)
+}
+
+func tableSource(method, table string, lines []string) string {
+	return fmt.Sprintf("schema.%s(%q, func(t *schema.Blueprint) {\n%s\n})", method, table, strings.Join(lines, "\n"))
+}
+
+// columnTypePattern - 正規化後的型別，例如 varchar(100)、decimal(10,2)、bigint unsigned
+var columnTypePattern = regexp.MustCompile(`^([a-z]+)(?:\((\d+)(?:,(\d+))?\))?( unsigned)?$`)
+
+// columnSource - 以 schema builder 定義欄位的原始碼，suffix 例如 .Change()
+// 無法對應 schema builder 的型別時產生 TODO 註解
+func columnSource(c database.ColumnDefinition, suffix string) string {
+	match := columnTypePattern.FindStringSubmatch(c.Type)
+	if match == nil {
+		return fmt.Sprintf("// TODO: schema builder 沒有對應 %s 的型別，請手動定義欄位 %s", c.Type, c.Name)
+	}
+	kind, length, scale, unsigned := match[1], match[2], match[3], match[4] != ""
+	increments := c.PrimaryKey && c.AutoIncrement
+
+	var source string
+	switch kind {
+	case "varchar":
+		if length == "" {
+			length = "255"
+		}
+		source = fmt.Sprintf("t.String(%q, %s)", c.Name, length)
+	case "char":
+		if length == "" {
+			length = "1"
+		}
+		source = fmt.Sprintf("t.Char(%q, %s)", c.Name, length)
+	case "text", "tinytext", "mediumtext":
+		source = fmt.Sprintf("t.Text(%q)", c.Name)
+	case "longtext":
+		source = fmt.Sprintf("t.LongText(%q)", c.Name)
+	case "tinyint", "smallint", "mediumint", "int":
+		if increments {
+			return fmt.Sprintf("t.Increments(%q)%s", c.Name, suffix)
+		}
+		source = fmt.Sprintf("t.Integer(%q)", c.Name)
+	case "bigint":
+		if increments && c.Name == "id" {
+			return "t.ID()" + suffix
+		}
+		if increments {
+			return fmt.Sprintf("t.BigIncrements(%q)%s", c.Name, suffix)
+		}
+		source = fmt.Sprintf("t.BigInteger(%q)", c.Name)
+	case "boolean":
+		source = fmt.Sprintf("t.Boolean(%q)", c.Name)
+	case "decimal":
+		if length == "" {
+			return fmt.Sprintf("// TODO: 請手動定義欄位 %s 的精度（%s）", c.Name, c.Type)
+		}
+		if scale == "" {
+			scale = "0"
+		}
+		source = fmt.Sprintf("t.Decimal(%q, %s, %s)", c.Name, length, scale)
+	case "date":
+		source = fmt.Sprintf("t.Date(%q)", c.Name)
+	case "timestamp", "timestamptz", "datetime":
+		source = fmt.Sprintf("t.Timestamp(%q)", c.Name)
+	case "json", "jsonb":
+		source = fmt.Sprintf("t.JSON(%q)", c.Name)
+	case "uuid":
+		source = fmt.Sprintf("t.UUID(%q)", c.Name)
+	default:
+		return fmt.Sprintf("// TODO: schema builder 沒有對應 %s 的型別，請手動定義欄位 %s", c.Type, c.Name)
+	}
+
+	if unsigned {
+		source += ".Unsigned()"
+	}
+	if c.Nullable {
+		source += ".Nullable()"
+	}
+	if c.Default != "" {
+		source += ".Default(" + defaultSource(c.Default) + ")"
+	}
+	return source + suffix
+}
+
+// defaultSource - gorm default tag 的值轉為 Default 的參數，例如 0、true、"user"
+func defaultSource(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	if value == "true" || value == "false" {
+		return value
+	}
+	if unquoted, ok := strings.CutPrefix(value, "'"); ok {
+		value = strings.TrimSuffix(unquoted, "'")
+	}
+	return strconv.Quote(value)
+}
+
+// indexSource - 名稱與 schema builder 的預設名稱（idx_資料表_欄位）不同時加上 Name
+func indexSource(table string, i database.IndexDefinition) string {
+	columns := make([]string, len(i.Columns))
+	for n, column := range i.Columns {
+		columns[n] = strconv.Quote(column)
+	}
+
+	method := "Index"
+	if i.Unique {
+		method = "Unique"
+	}
+	source := fmt.Sprintf("t.%s(%s)", method, strings.Join(columns, ", "))
+	if i.Name != "idx_"+table+"_"+strings.Join(i.Columns, "_") {
+		source += fmt.Sprintf(".Name(%q)", i.Name)
+	}
+	return source
+}
+
+// foreignKeySource - 外鍵使用 model 的名稱，回滾時依名稱刪除
+func foreignKeySource(fk database.ForeignKeyDefinition) string {
+	if len(fk.Columns) != 1 {
+		return fmt.Sprintf("// TODO: schema builder 不支援複合外鍵，請手動建立 %s", fk.Name)
+	}
+
+	source := fmt.Sprintf("t.Foreign(%q).On(%q)", fk.Columns[0], fk.ReferencedTable)
+	if fk.ReferencedColumns[0] != "id" {
+		source += fmt.Sprintf(".References(%q)", fk.ReferencedColumns[0])
+	}
+	source += fmt.Sprintf(".Name(%q)", fk.Name)
+
+	switch fk.OnDelete {
+	case "CASCADE":
+		source += ".CascadeOnDelete()"
+	case "SET NULL":
+		source += ".NullOnDelete()"
+	case "RESTRICT":
+		source += ".RestrictOnDelete()"
+	}
+	return source
+}
+
+var syncMigrationTemplate = template.Must(template.New("sync").Parse(`package migrations
+
+import (
+	"fmt"
+
+	"my-api/database/schema"
+)
+
+// {{.StructName}} - 讓資料庫與 GORM models 一致（由 schema:diff --make 產生，執行前請確認內容）
+type {{.StructName}} struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&{{.StructName}}{
+		BaseMigration: BaseMigration{
+			version:     "{{.Version}}",
+			description: "{{.Name}}",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *{{.StructName}}) Up(db Executor, dialect Dialect) error {
+	commands := []*schema.Command{
+{{- range .Up}}
+		{{.}},
+{{- end}}
+	}
+
+	for _, command := range commands {
+		if err := command.Run(db, schema.Dialect(dialect)); err != nil {
+			return fmt.Errorf("{{.Name}} 失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ {{.Name}} 成功")
+	return nil
+}
+
+// Down - 回滾 migration
+// 修改過的欄位還原為執行 schema:diff 時資料庫的型別與 NULL，原本的預設值不會還原
+func (m *{{.StructName}}) Down(db Executor, dialect Dialect) error {
+	commands := []*schema.Command{
+{{- range .Down}}
+		{{.}},
+{{- end}}
+	}
+
+	for _, command := range commands {
+		if err := command.Run(db, schema.Dialect(dialect)); err != nil {
+			return fmt.Errorf("rollback {{.Name}} 失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ rollback {{.Name}} 成功")
+	return nil
+}
+`))
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"my-api/database"
+)
+
+// TestSyncCommands 測試依各種差異產生的 schema.Command 原始碼
+func TestSyncCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		table    database.TableDiff
+		wantUp   []string
+		wantDown []string
+	}{
+		{
+			name: "資料表不存在",
+			table: database.TableDiff{
+				Table:       "tags",
+				Differences: []database.Difference{{Kind: database.DiffMissingTable, Name: "tags"}},
+				Definition: database.TableDefinition{
+					Name: "tags",
+					Columns: []database.ColumnDefinition{
+						{Name: "id", Type: "bigint unsigned", PrimaryKey: true, AutoIncrement: true},
+						{Name: "user_id", Type: "bigint unsigned"},
+						{Name: "name", Type: "varchar(50)"},
+						{Name: "color", Type: "char(7)", Nullable: true, Default: "'#000000'"},
+					},
+					Indexes: []database.IndexDefinition{
+						{Name: "idx_tags_name", Columns: []string{"name"}, Unique: true},
+					},
+					ForeignKeys: []database.ForeignKeyDefinition{
+						{Name: "fk_tags_users", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}, OnDelete: "CASCADE"},
+					},
+				},
+			},
+			wantUp: []string{
+				`schema.Create("tags", func(t *schema.Blueprint) {`,
+				`t.ID()`,
+				`t.BigInteger("user_id").Unsigned()`,
+				`t.String("name", 50)`,
+				`t.Char("color", 7).Nullable().Default("#000000")`,
+				`t.Unique("name")`,
+				`t.Foreign("user_id").On("users").Name("fk_tags_users").CascadeOnDelete()`,
+			},
+			wantDown: []string{`schema.DropIfExists("tags")`},
+		},
+		{
+			name: "欄位不同",
+			table: database.TableDiff{
+				Table: "users",
+				Differences: []database.Difference{{
+					Kind:           database.DiffColumnChanged,
+					Name:           "name",
+					ModelColumn:    &database.ColumnDefinition{Name: "name", Type: "varchar(100)"},
+					DatabaseColumn: &database.ColumnDefinition{Name: "name", Type: "varchar(50)", Nullable: true},
+				}},
+			},
+			wantUp:   []string{`schema.Table("users"`, `t.String("name", 100).Change()`},
+			wantDown: []string{`schema.Table("users"`, `t.String("name", 50).Nullable().Change()`},
+		},
+		{
+			name: "索引不同",
+			table: database.TableDiff{
+				Table: "users",
+				Differences: []database.Difference{{
+					Kind:          database.DiffIndexChanged,
+					Name:          "idx_users_email",
+					ModelIndex:    &database.IndexDefinition{Name: "idx_users_email", Columns: []string{"email"}, Unique: true},
+					DatabaseIndex: &database.IndexDefinition{Name: "users_email_index", Columns: []string{"email"}},
+				}},
+			},
+			wantUp:   []string{`t.DropIndex("users_email_index")`, `t.Unique("email")`},
+			wantDown: []string{`t.DropIndex("idx_users_email")`, `t.Index("email").Name("users_email_index")`},
+		},
+		{
+			name: "外鍵不同",
+			table: database.TableDiff{
+				Table: "posts",
+				Differences: []database.Difference{{
+					Kind:               database.DiffForeignKeyChanged,
+					Name:               "fk_posts_users",
+					ModelForeignKey:    &database.ForeignKeyDefinition{Name: "fk_posts_user", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}, OnDelete: "CASCADE"},
+					DatabaseForeignKey: &database.ForeignKeyDefinition{Name: "fk_posts_users", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}, OnDelete: "RESTRICT"},
+				}},
+			},
+			// 沿用資料庫原本的外鍵名稱
+			wantUp: []string{
+				`t.DropForeign("fk_posts_users")`,
+				`t.Foreign("user_id").On("users").Name("fk_posts_users").CascadeOnDelete()`,
+			},
+			wantDown: []string{
+				`t.DropForeign("fk_posts_users")`,
+				`t.Foreign("user_id").On("users").Name("fk_posts_users").RestrictOnDelete()`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up, down := syncCommands(tt.table)
+			assertContainsInOrder(t, "Up", up, tt.wantUp)
+			assertContainsInOrder(t, "Down", down, tt.wantDown)
+		})
+	}
+}
+
+// TestSyncCommands_ExtraOnly 測試只有資料庫多出的項目時不產生語句
+func TestSyncCommands_ExtraOnly(t *testing.T) {
+	up, down := syncCommands(database.TableDiff{
+		Table:       "users",
+		Differences: []database.Difference{{Kind: database.DiffExtraColumn, Name: "legacy"}},
+	})
+	if up != "" || down != "" {
+		t.Errorf("syncCommands() = %q, %q, want 空字串", up, down)
+	}
+}
+
+// TestColumnSource 測試欄位型別對應 schema builder 的方法
+func TestColumnSource(t *testing.T) {
+	tests := []struct {
+		column database.ColumnDefinition
+		want   string
+	}{
+		{database.ColumnDefinition{Name: "id", Type: "bigint", PrimaryKey: true, AutoIncrement: true}, `t.ID()`},
+		{database.ColumnDefinition{Name: "code", Type: "int", PrimaryKey: true, AutoIncrement: true}, `t.Increments("code")`},
+		{database.ColumnDefinition{Name: "title", Type: "varchar"}, `t.String("title", 255)`},
+		{database.ColumnDefinition{Name: "body", Type: "longtext"}, `t.LongText("body")`},
+		{database.ColumnDefinition{Name: "price", Type: "decimal(10,2)"}, `t.Decimal("price", 10, 2)`},
+		{database.ColumnDefinition{Name: "age", Type: "int", Default: "0"}, `t.Integer("age").Default(0)`},
+		{database.ColumnDefinition{Name: "active", Type: "boolean", Default: "true"}, `t.Boolean("active").Default(true)`},
+		{database.ColumnDefinition{Name: "role", Type: "varchar(20)", Default: "user"}, `t.String("role", 20).Default("user")`},
+		{database.ColumnDefinition{Name: "settings", Type: "jsonb", Nullable: true}, `t.JSON("settings").Nullable()`},
+		{database.ColumnDefinition{Name: "verified_at", Type: "timestamptz", Nullable: true}, `t.Timestamp("verified_at").Nullable()`},
+		{database.ColumnDefinition{Name: "amount", Type: "decimal"}, `// TODO: 請手動定義欄位 amount 的精度（decimal）`},
+		{database.ColumnDefinition{Name: "ratio", Type: "double"}, `// TODO: schema builder 沒有對應 double 的型別，請手動定義欄位 ratio`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column.Name, func(t *testing.T) {
+			if got := columnSource(tt.column, ""); got != tt.want {
+				t.Errorf("columnSource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestForeignKeySource 測試外鍵的參照欄位、ON DELETE 與複合外鍵
+func TestForeignKeySource(t *testing.T) {
+	tests := []struct {
+		name string
+		fk   database.ForeignKeyDefinition
+		want string
+	}{
+		{
+			name: "參照 id",
+			fk:   database.ForeignKeyDefinition{Name: "fk_posts_users", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+			want: `t.Foreign("user_id").On("users").Name("fk_posts_users")`,
+		},
+		{
+			name: "參照其他欄位",
+			fk:   database.ForeignKeyDefinition{Name: "fk_identities_users", Columns: []string{"email"}, ReferencedTable: "users", ReferencedColumns: []string{"email"}, OnDelete: "SET NULL"},
+			want: `t.Foreign("email").On("users").References("email").Name("fk_identities_users").NullOnDelete()`,
+		},
+		{
+			name: "複合外鍵",
+			fk:   database.ForeignKeyDefinition{Name: "fk_user_roles", Columns: []string{"user_id", "role_id"}, ReferencedTable: "roles", ReferencedColumns: []string{"user_id", "id"}},
+			want: `// TODO: schema builder 不支援複合外鍵，請手動建立 fk_user_roles`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := foreignKeySource(tt.fk); got != tt.want {
+				t.Errorf("foreignKeySource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMakeSyncMigration 測試產生的 migration 是合法的 Go 原始碼，且 Down 依相反的順序回滾
+func TestMakeSyncMigration(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sql"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tables := []database.TableDiff{
+		{
+			Table:       "tags",
+			Differences: []database.Difference{{Kind: database.DiffMissingTable, Name: "tags"}},
+			Definition: database.TableDefinition{
+				Name:    "tags",
+				Columns: []database.ColumnDefinition{{Name: "id", Type: "bigint", PrimaryKey: true, AutoIncrement: true}},
+			},
+		},
+		{
+			Table:       "sessions",
+			Differences: []database.Difference{{Kind: database.DiffExtraIndex, Name: "idx_sessions_legacy"}},
+		},
+		{
+			Table: "posts",
+			Differences: []database.Difference{{
+				Kind:            database.DiffMissingForeignKey,
+				Name:            "fk_posts_tags",
+				ModelForeignKey: &database.ForeignKeyDefinition{Name: "fk_posts_tags", Columns: []string{"tag_id"}, ReferencedTable: "tags", ReferencedColumns: []string{"id"}},
+			}},
+		},
+	}
+
+	filename, err := makeSyncMigration(dir, "sync_schema_with_models", tables)
+	if err != nil {
+		t.Fatalf("makeSyncMigration() 發生錯誤: %v", err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), filename, content, parser.AllErrors); err != nil {
+		t.Fatalf("產生的 migration 不是合法的 Go 原始碼: %v\n%s", err, content)
+	}
+
+	source := string(content)
+	if strings.Contains(source, "sessions") {
+		t.Error("只有資料庫多出的項目時不應產生語句")
+	}
+
+	up, down, ok := strings.Cut(source, "// Down - 回滾 migration")
+	if !ok {
+		t.Fatalf("找不到 Down:\n%s", source)
+	}
+	assertContainsInOrder(t, "Up", up, []string{`schema.Create("tags"`, `t.Foreign("tag_id").On("tags").Name("fk_posts_tags")`})
+	// 先刪除參照 tags 的外鍵，再刪除 tags
+	assertContainsInOrder(t, "Down", down, []string{`t.DropForeign("fk_posts_tags")`, `schema.DropIfExists("tags")`})
+
+	// 相同名稱的 migration 不能重複產生
+	if _, err := makeSyncMigration(dir, "sync_schema_with_models", tables); err == nil {
+		t.Error("同名的 migration 應回傳錯誤")
+	}
+}
+
+// assertContainsInOrder - source 依序包含 want 的每一段文字
+func assertContainsInOrder(t *testing.T, label, source string, want []string) {
+	t.Helper()
+
+	rest := source
+	for _, w := range want {
+		i := strings.Index(rest, w)
+		if i < 0 {
+			t.Errorf("%s 缺少 %s（或順序錯誤）:\n%s", label, w, source)
+			return
+		}
+		rest = rest[i+len(w):]
+	}
+}
@@ -1,20 +1,47 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 
+	"my-api/bootstrap"
 	"my-api/config"
 	"my-api/database"
+	"my-api/database/seeders"
 )
 
 func main() {
 	// 定義命令行參數
 	migrateCmd := flag.NewFlagSet("migrate", flag.ExitOnError)
+	migrateTo := migrateCmd.String("to", "", "只執行到指定版本（含），例如 000005")
+	migratePretend := migrateCmd.Bool("pretend", false, "只輸出會執行的 SQL，不變更資料庫")
+	migrateStrict := migrateCmd.Bool("strict", false, "已執行的 migration 內容被修改時中止")
+	migrateSeed := migrateCmd.Bool("seed", false, "執行完 migrations 後執行 DatabaseSeeder")
+	migrateForce := migrateCmd.Bool("force", false, "在 production 環境強制執行 seeder")
+
 	rollbackCmd := flag.NewFlagSet("rollback", flag.ExitOnError)
+	rollbackStep := rollbackCmd.Int("step", 0, "回滾最近執行的 N 個 migration（預設回滾最後一個批次）")
+	rollbackPretend := rollbackCmd.Bool("pretend", false, "只輸出會執行的 SQL，不變更資料庫")
+
 	statusCmd := flag.NewFlagSet("status", flag.ExitOnError)
+	statusJSON := statusCmd.Bool("json", false, "以 JSON 格式輸出")
+	statusStrict := statusCmd.Bool("strict", false, "已執行的 migration 內容被修改時 exit code 為 1")
+
+	resetCmd := flag.NewFlagSet("reset", flag.ExitOnError)
+	resetForce := resetCmd.Bool("force", false, "在 production 環境強制執行")
+
+	refreshCmd := flag.NewFlagSet("refresh", flag.ExitOnError)
+	refreshForce := refreshCmd.Bool("force", false, "在 production 環境強制執行")
+
+	freshCmd := flag.NewFlagSet("fresh", flag.ExitOnError)
+	freshForce := freshCmd.Bool("force", false, "在 production 環境強制執行")
+
+	seedCmd := flag.NewFlagSet("seed", flag.ExitOnError)
+	seedClass := seedCmd.String("class", seeders.DefaultSeeder, "執行的 seeder，例如 UserSeeder")
+	seedForce := seedCmd.Bool("force", false, "在 production 環境強制執行")
 
 	// 檢查參數
 	if len(os.Args) < 2 {
@@ -25,189 +52,298 @@ func main() {
 	// 載入配置
 	config.LoadConfig()
 
+	// 初始化 Logger（migration 鎖的等待紀錄會寫入 Log）
+	// status、make、schema:diff 不會取得鎖，且 --json 的輸出不能混入 Log
+	if command := os.Args[1]; command != "status" && command != "make" && command != "schema:diff" {
+		bootstrap.InitLogger()
+	}
+
 	// 根據命令執行對應操作
 	switch os.Args[1] {
 	case "migrate":
 		migrateCmd.Parse(os.Args[2:])
-		runMigrate()
-		
+		if *migrateSeed && !*migratePretend {
+			confirmSeed(*migrateForce)
+		}
+		runMigrate(database.MigrateOptions{To: *migrateTo, Pretend: *migratePretend, Strict: *migrateStrict})
+		if *migrateSeed && !*migratePretend {
+			runSeed(seeders.DefaultSeeder)
+		}
+
 	case "rollback":
 		rollbackCmd.Parse(os.Args[2:])
-		runRollback()
-		
+		runRollback(database.RollbackOptions{Step: *rollbackStep, Pretend: *rollbackPretend})
+
+	case "reset":
+		resetCmd.Parse(os.Args[2:])
+		confirmDestructive("reset", *resetForce)
+		runReset()
+
+	case "refresh":
+		refreshCmd.Parse(os.Args[2:])
+		confirmDestructive("refresh", *refreshForce)
+		runRefresh()
+
+	case "fresh":
+		freshCmd.Parse(os.Args[2:])
+		confirmDestructive("fresh", *freshForce)
+		runFresh()
+
 	case "status":
 		statusCmd.Parse(os.Args[2:])
-		showStatus()
-	
+		showStatus(*statusJSON, *statusStrict || config.GlobalConfig.Database.MigrationStrictChecksum)
+
+	case "repair":
+		runRepair()
+
+	case "seed":
+		seedCmd.Parse(os.Args[2:])
+		confirmSeed(*seedForce)
+		runSeed(*seedClass)
+
 	case "make":
-		if len(os.Args) < 3 {
-			fmt.Println("❌ 請提供 migration 名稱")
-			fmt.Println("範例: go run cmd/migrate/main.go make add_phone_to_users")
-			os.Exit(1)
-		}
-		makeMigration(os.Args[2])
-		
+		runMake(os.Args[2:])
+
+	case "schema:diff":
+		runSchemaDiff(os.Args[2:])
+
 	default:
 		printUsage()
 		os.Exit(1)
 	}
 }
 
-func runMigrate() {
-	fmt.Println("🚀 執行 Migration...")
-	if err := database.RunMigrations(); err != nil {
+func runMigrate(opts database.MigrateOptions) {
+	// pretend 模式的標準輸出只有 SQL，方便導向檔案
+	if !opts.Pretend {
+		fmt.Println("🚀 執行 Migration...")
+	}
+	if err := database.Migrate(opts); err != nil {
 		log.Fatal("❌ Migration 失敗:", err)
 	}
 }
 
-func runRollback() {
-	fmt.Println("⏮️  執行 Rollback...")
-	if err := database.RollbackMigration(); err != nil {
+func runRollback(opts database.RollbackOptions) {
+	if !opts.Pretend {
+		fmt.Println("⏮️  執行 Rollback...")
+	}
+	if err := database.Rollback(opts); err != nil {
 		log.Fatal("❌ Rollback 失敗:", err)
 	}
 }
 
-func showStatus() {
-	status, err := database.GetMigrationStatus()
-	if err != nil {
-		log.Fatal("❌ 無法獲取狀態:", err)
-	}
-	
-	fmt.Println("📊 Migration 狀態:")
-	fmt.Println()
-	
-	// 引入 migrations 確保所有 migration 被註冊
-	_ = "my-api/database/migrations"
-	
-	allMigrations := getAllMigrations()
-	for _, m := range allMigrations {
-		executed := status[m.Version]
-		statusIcon := "⏳"
-		statusText := "待執行"
-		if executed {
-			statusIcon = "✅"
-			statusText = "已執行"
-		}
-		fmt.Printf("  %s [%s] %s - %s\n", statusIcon, m.Version, m.Description, statusText)
+func runReset() {
+	fmt.Println("⏮️  回滾所有 Migrations...")
+	if err := database.ResetMigrations(); err != nil {
+		log.Fatal("❌ Reset 失敗:", err)
 	}
 }
 
-type migrationInfo struct {
-	Version     string
-	Description string
+func runRefresh() {
+	fmt.Println("🔄 回滾所有 Migrations 後重新執行...")
+	if err := database.RefreshMigrations(); err != nil {
+		log.Fatal("❌ Refresh 失敗:", err)
+	}
 }
 
-func getAllMigrations() []migrationInfo {
-	// 這裡需要手動列出或從 registry 獲取
-	// 為了簡化，這裡返回空列表
-	// 實際使用時，可以從 migrations.All() 獲取
-	return []migrationInfo{
-		{Version: "000001", Description: "create_users_table"},
+func runFresh() {
+	fmt.Println("🗑️  刪除所有資料表後重新執行 Migrations...")
+	if err := database.FreshMigrations(); err != nil {
+		log.Fatal("❌ Fresh 失敗:", err)
 	}
 }
 
-func makeMigration(name string) {
-	template := `package migrations
+func runRepair() {
+	fmt.Println("🔧 重新記錄 Migration checksum...")
+	repaired, err := database.RepairChecksums()
+	if err != nil {
+		log.Fatal("❌ Repair 失敗:", err)
+	}
 
-import (
-	"database/sql"
-	"fmt"
-)
+	if len(repaired) == 0 {
+		fmt.Println("✓ 所有已執行 migration 的 checksum 皆相符，無需更新")
+		return
+	}
+	for _, version := range repaired {
+		fmt.Printf("  ✓ [%s] 已更新 checksum\n", version)
+	}
+	fmt.Printf("✅ 已更新 %d 個 migration 的 checksum\n", len(repaired))
+}
 
-// TODO: 修改這個名稱
-type Migration%s struct {
-	BaseMigration
+func runSeed(class string) {
+	fmt.Println("🌱 執行 Seeder...")
+	bootstrap.InitDB()
+	bootstrap.InitPasswordPolicy() // AdminSeeder 檢查 ADMIN_PASSWORD
+	if err := seeders.Call(bootstrap.DB, class); err != nil {
+		log.Fatal("❌ Seed 失敗:", err)
+	}
+	fmt.Println("✅ Seed 完成！")
 }
 
-func init() {
-	Register(&Migration%s{
-		BaseMigration: BaseMigration{
-			version:     "%s",  // TODO: 修改版本號
-			description: "%s",
-		},
-	})
+// confirmSeed - production 環境執行 seeder 時，必須加上 --force
+func confirmSeed(force bool) {
+	if config.GlobalConfig.App.Env != "production" || force {
+		return
+	}
+	fmt.Println("❌ seeder 會寫入假資料，production 環境請加上 --force 確認執行")
+	os.Exit(1)
 }
 
-// Up - 執行 migration
-func (m *Migration%s) Up(db *sql.DB) error {
-	query := ` + "`" + `
-		-- TODO: 在這裡寫 SQL
-		-- 範例:
-		-- ALTER TABLE users ADD COLUMN phone VARCHAR(20);
-	` + "`" + `
-	
-	_, err := db.Exec(query)
-	if err != nil {
-		return fmt.Errorf("migration 失敗: %%v", err)
+// confirmDestructive - production 環境執行會清除資料的命令時，必須加上 --force
+func confirmDestructive(command string, force bool) {
+	if config.GlobalConfig.App.Env != "production" || force {
+		return
 	}
-	
-	fmt.Println("✓ Migration 成功")
-	return nil
+	fmt.Printf("❌ %s 會清除資料庫中的資料，production 環境請加上 --force 確認執行\n", command)
+	os.Exit(1)
 }
 
-// Down - 回滾 migration
-func (m *Migration%s) Down(db *sql.DB) error {
-	query := ` + "`" + `
-		-- TODO: 在這裡寫回滾 SQL
-		-- 範例:
-		-- ALTER TABLE users DROP COLUMN phone;
-	` + "`" + `
-	
-	_, err := db.Exec(query)
+// statusReport - status --json 的輸出格式
+type statusReport struct {
+	Migrations []database.MigrationStatus `json:"migrations"`
+	Pending    int                        `json:"pending"`
+	Orphans    int                        `json:"orphans"`
+	Modified   int                        `json:"modified"`
+}
+
+// showStatus - 顯示每個 migration 的狀態，有待執行的 migration（strict 時包含內容被修改的 migration）時 exit code 為 1
+func showStatus(asJSON, strict bool) {
+	statuses, err := database.GetMigrationStatus()
 	if err != nil {
-		return fmt.Errorf("rollback 失敗: %%v", err)
+		log.Fatal("❌ 無法獲取狀態:", err)
+	}
+
+	report := statusReport{Migrations: statuses}
+	for _, status := range statuses {
+		switch {
+		case status.Orphan:
+			report.Orphans++
+		case !status.Ran:
+			report.Pending++
+		case status.Modified:
+			report.Modified++
+		}
+	}
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatal("❌ 無法輸出狀態:", err)
+		}
+	} else {
+		printStatus(report)
+	}
+
+	if report.Pending > 0 || (strict && report.Modified > 0) {
+		os.Exit(1)
 	}
-	
-	fmt.Println("✓ Rollback 成功")
-	return nil
-}
-`
-	
-	// 生成文件名和結構名
-	structName := toPascalCase(name)
-	version := getNextVersion()
-	
-	content := fmt.Sprintf(template, structName, structName, version, name, structName, structName)
-	
-	filename := fmt.Sprintf("database/migrations/%s_%s.go", version, name)
-	
-	// 這裡應該寫入文件，但為了簡化，只打印
-	fmt.Printf("✅ Migration 模板已生成：\n")
-	fmt.Printf("   文件名: %s\n", filename)
-	fmt.Printf("   結構名: %s\n", structName)
-	fmt.Printf("\n請手動創建文件並複製以下內容：\n\n")
-	fmt.Println(content)
 }
 
-func toPascalCase(s string) string {
-	// 簡化版本的轉換
-	return s
+func printStatus(report statusReport) {
+	fmt.Println("📊 Migration 狀態:")
+	fmt.Println()
+
+	unverified := 0
+	for _, status := range report.Migrations {
+		if status.Ran && !status.Orphan && status.Checksum == "" {
+			unverified++
+		}
+
+		switch {
+		case status.Orphan:
+			fmt.Printf("  ⚠️  [%s] %s - 找不到 migration 程式碼%s\n", status.Version, status.Description, executedDetail(status))
+		case status.Modified:
+			fmt.Printf("  ⚠️  [%s] %s - 已執行，但執行後內容被修改%s\n", status.Version, status.Description, executedDetail(status))
+		case status.Ran:
+			fmt.Printf("  ✅ [%s] %s - 已執行%s\n", status.Version, status.Description, executedDetail(status))
+		default:
+			fmt.Printf("  ⏳ [%s] %s - 待執行\n", status.Version, status.Description)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("共 %d 個 migration，待執行 %d 個", len(report.Migrations)-report.Orphans, report.Pending)
+	if report.Orphans > 0 {
+		fmt.Printf("，找不到程式碼 %d 個", report.Orphans)
+	}
+	if report.Modified > 0 {
+		fmt.Printf("，內容被修改 %d 個", report.Modified)
+	}
+	fmt.Println()
+
+	if unverified > 0 {
+		fmt.Printf("%d 個已執行的 migration 沒有記錄 checksum，執行 repair 補上後才會檢查是否被修改\n", unverified)
+	}
+	if report.Modified > 0 {
+		fmt.Println("確認修改無誤後執行 repair 重新記錄 checksum")
+	}
 }
 
-func getNextVersion() string {
-	// 簡化版本，返回固定值
-	// 實際應該讀取現有 migrations 並 +1
-	return "000002"
+// executedDetail - 批次與執行時間，例如（batch 2，2026-01-28 10:30:15）
+func executedDetail(status database.MigrationStatus) string {
+	if status.ExecutedAt == nil {
+		return fmt.Sprintf("（batch %d）", status.Batch)
+	}
+	return fmt.Sprintf("（batch %d，%s）", status.Batch, status.ExecutedAt.Local().Format("2006-01-02 15:04:05"))
 }
 
 func printUsage() {
-	fmt.Println(`
+	fmt.Print(`
 Migration 管理工具 - 類似 Laravel Artisan （一個文件包含 Up 和 Down）
 
 使用方式:
   go run cmd/migrate/main.go [命令]
 
 可用命令:
-  migrate   - 執行所有待執行的 migrations
-  rollback  - 回滾最後一次 migration
-  status    - 查看當前 migration 狀態
-  make      - 建立新的 migration 文件
+  migrate   - 執行所有待執行的 migrations（同一次執行為同一個批次）
+              --to=<版本>  只執行到指定版本（含）
+              --pretend    只輸出會執行的 SQL，不變更資料庫
+              --strict     已執行的 migration 內容被修改時中止
+              --seed       執行完後執行 DatabaseSeeder
+  rollback  - 回滾最後一個批次
+              --step=N     回滾最近執行的 N 個 migration
+              --pretend    只輸出會執行的 SQL，不變更資料庫
+  reset     - 回滾所有 migrations
+  refresh   - 回滾所有 migrations 後重新執行
+  fresh     - 刪除所有資料表後重新執行 migrations（不執行 Down）
+  status    - 查看當前 migration 狀態（有待執行的 migration 時 exit code 為 1）
+              --json       以 JSON 格式輸出
+              --strict     已執行的 migration 內容被修改時 exit code 也為 1
+  repair    - 以目前的 migration 內容重新記錄已執行 migration 的 checksum
+  seed      - 執行 DatabaseSeeder，填入假資料（不建立管理員）
+              --class=<名稱>  只執行指定的 seeder，例如 UserSeeder
+                              AdminSeeder 將 ADMIN_EMAIL 設為管理員（不存在時以 ADMIN_PASSWORD 建立）
+  make      - 在 database/migrations 建立新的 migration 文件（版本號自動遞增）
+              --create=<資料表>  建立資料表的範本
+              --table=<資料表>   修改資料表的範本
+              --sql              在 database/migrations/sql 建立 .up.sql / .down.sql
+  schema:diff - 比對 GORM models 與資料庫的欄位型別、NULL、索引與外鍵（有差異時 exit code 為 1）
+              --json       以 JSON 格式輸出
+              --make       產生讓資料庫與 models 一致的 migration
+              --name=<名稱>  --make 產生的 migration 名稱（預設 sync_schema_with_models）
+
+  reset、refresh、fresh、seed、migrate --seed 在 APP_ENV=production 時需加上 --force
 
 範例:
   go run cmd/migrate/main.go migrate
+  go run cmd/migrate/main.go migrate --to=000005
+  go run cmd/migrate/main.go migrate --pretend > pending.sql
   go run cmd/migrate/main.go rollback
+  go run cmd/migrate/main.go rollback --step=2
+  go run cmd/migrate/main.go refresh
   go run cmd/migrate/main.go status
+  go run cmd/migrate/main.go status --json
+  go run cmd/migrate/main.go repair
+  go run cmd/migrate/main.go seed
+  go run cmd/migrate/main.go seed --class=UserSeeder
+  ADMIN_EMAIL=admin@example.com ADMIN_PASSWORD=... go run cmd/migrate/main.go seed --class=AdminSeeder --force
+  go run cmd/migrate/main.go migrate --seed
   go run cmd/migrate/main.go make add_phone_to_users
+  go run cmd/migrate/main.go make create_products_table
+  go run cmd/migrate/main.go make create_tags_table --sql
+  go run cmd/migrate/main.go schema:diff
+  go run cmd/migrate/main.go schema:diff --make
 
 Docker 內執行:
   docker exec -it my-go-app go run cmd/migrate/main.go migrate
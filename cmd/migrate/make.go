@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"my-api/database/migrations"
+)
+
+// migrationsDir - migration 檔案目錄（相對於專案根目錄），SQL migrations 放在其下的 sql 目錄
+const migrationsDir = "database/migrations"
+
+var (
+	migrationNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	migrationFilePattern = regexp.MustCompile(`^(\d{6})_(.+?)(?:\.go|\.(?:up|down)(?:\.[a-z]+)?\.sql)$`)
+	tableNamePattern     = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+	// 從名稱推測資料表，與 Laravel 相同：create_xxx_table、add_xxx_to_yyy_table、remove_xxx_from_yyy
+	createTablePattern = regexp.MustCompile(`^create_(\w+?)_table$`)
+	alterTablePattern  = regexp.MustCompile(`_(?:to|from|in)_(\w+?)(?:_table)?$`)
+)
+
+// runMake - make 命令：在 database/migrations 建立新的 migration 檔案
+func runMake(args []string) {
+	makeCmd := flag.NewFlagSet("make", flag.ExitOnError)
+	create := makeCmd.String("create", "", "建立資料表的 migration，例如 --create=products")
+	table := makeCmd.String("table", "", "修改資料表的 migration，例如 --table=users")
+	asSQL := makeCmd.Bool("sql", false, "建立 .up.sql / .down.sql 檔案（database/migrations/sql）")
+
+	// 名稱可以放在參數前或後：make add_phone_to_users --table=users
+	var flags, names []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		} else {
+			names = append(names, arg)
+		}
+	}
+	makeCmd.Parse(flags)
+
+	if len(names) != 1 {
+		fmt.Println("❌ 請提供 migration 名稱")
+		fmt.Println("範例: go run cmd/migrate/main.go make add_phone_to_users --table=users")
+		os.Exit(1)
+	}
+
+	filenames, err := makeMigration(migrationsDir, names[0], makeOptions{Create: *create, Table: *table, SQL: *asSQL})
+	if err != nil {
+		fmt.Println("❌", err)
+		os.Exit(1)
+	}
+
+	for _, filename := range filenames {
+		fmt.Printf("✅ 已建立 Migration: %s\n", filename)
+	}
+}
+
+// makeOptions - make 命令的選項
+type makeOptions struct {
+	Create string
+	Table  string
+	SQL    bool
+}
+
+// migrationStub - 產生 migration 檔案的參數
+type migrationStub struct {
+	Version     string
+	Name        string
+	StructName  string
+	Table       string
+	CreateTable bool
+}
+
+// makeMigration - 產生 migration 檔案，回傳檔案路徑
+func makeMigration(dir, name string, opts makeOptions) ([]string, error) {
+	if opts.Create != "" && opts.Table != "" {
+		return nil, fmt.Errorf("--create 與 --table 只能擇一使用")
+	}
+
+	stub, err := newMigrationStub(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case opts.Create != "":
+		stub.Table, stub.CreateTable = opts.Create, true
+	case opts.Table != "":
+		stub.Table = opts.Table
+	default:
+		stub.Table, stub.CreateTable = guessTable(stub.Name)
+	}
+	if stub.Table != "" && !tableNamePattern.MatchString(stub.Table) {
+		return nil, fmt.Errorf("資料表名稱只能包含小寫英文、數字與底線: %s", stub.Table)
+	}
+
+	if opts.SQL {
+		base := filepath.Join(dir, "sql", fmt.Sprintf("%s_%s", stub.Version, stub.Name))
+		up, down := renderSQLMigration(stub)
+		if err := createFile(base+".up.sql", up); err != nil {
+			return nil, err
+		}
+		if err := createFile(base+".down.sql", down); err != nil {
+			return []string{base + ".up.sql"}, err
+		}
+		return []string{base + ".up.sql", base + ".down.sql"}, nil
+	}
+
+	content, err := renderMigration(stub)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := filepath.Join(dir, fmt.Sprintf("%s_%s.go", stub.Version, stub.Name))
+	if err := createFile(filename, content); err != nil {
+		return nil, err
+	}
+	return []string{filename}, nil
+}
+
+// newMigrationStub - 檢查名稱、確認沒有同名的 migration，並取得下一個版本號
+func newMigrationStub(dir, name string) (migrationStub, error) {
+	name = strings.ToLower(strings.NewReplacer("-", "_", " ", "_").Replace(strings.TrimSpace(name)))
+	if !migrationNamePattern.MatchString(name) {
+		return migrationStub{}, fmt.Errorf("migration 名稱只能包含小寫英文、數字與底線: %s", name)
+	}
+
+	// Go 與 SQL migrations 共用版本號，兩個目錄都要檢查
+	var files []string
+	for _, d := range []string{dir, filepath.Join(dir, "sql")} {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			return migrationStub{}, fmt.Errorf("找不到 %s 目錄，請在專案根目錄執行: %v", d, err)
+		}
+		for _, entry := range entries {
+			files = append(files, filepath.Join(d, entry.Name()))
+		}
+	}
+
+	// 同名的 migration 會產生重複的型別名稱
+	for _, file := range files {
+		if match := migrationFilePattern.FindStringSubmatch(filepath.Base(file)); match != nil && match[2] == name {
+			return migrationStub{}, fmt.Errorf("已存在同名的 migration: %s", file)
+		}
+	}
+
+	return migrationStub{Version: getNextVersion(files), Name: name, StructName: toPascalCase(name)}, nil
+}
+
+// createFile - 建立新檔案，已存在時不覆寫
+func createFile(filename string, content []byte) error {
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("檔案已存在，不會覆寫: %s", filename)
+		}
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(content)
+	return err
+}
+
+// guessTable - 從 migration 名稱推測資料表
+func guessTable(name string) (table string, create bool) {
+	if match := createTablePattern.FindStringSubmatch(name); match != nil {
+		return match[1], true
+	}
+	if match := alterTablePattern.FindStringSubmatch(name); match != nil {
+		return match[1], false
+	}
+	return "", false
+}
+
+// toPascalCase - snake_case 轉 PascalCase，例如 add_phone_to_users → AddPhoneToUsers
+func toPascalCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// getNextVersion - 下一個版本號：已註冊的 migrations 與目錄中檔案的最大版本號 + 1
+// 目錄中的檔案也要算進去，避免尚未編譯進來的 migration 版本號重複
+func getNextVersion(files []string) string {
+	latest := 0
+	for _, m := range migrations.All() {
+		if version, err := strconv.Atoi(m.Version()); err == nil && version > latest {
+			latest = version
+		}
+	}
+	for _, file := range files {
+		if match := migrationFilePattern.FindStringSubmatch(filepath.Base(file)); match != nil {
+			if version, _ := strconv.Atoi(match[1]); version > latest {
+				latest = version
+			}
+		}
+	}
+	return fmt.Sprintf("%06d", latest+1)
+}
+
+// renderMigration - 依 stub 產生 migration 原始碼
+func renderMigration(stub migrationStub) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := migrationTemplate.Execute(&buf, stub); err != nil {
+		return nil, err
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("產生 migration 失敗: %v", err)
+	}
+	return source, nil
+}
+
+// renderSQLMigration - 產生 .up.sql 與 .down.sql 的內容
+// 預設檔案使用 MySQL 語法，PostgreSQL 語法不同時另外建立 .up.postgres.sql
+func renderSQLMigration(stub migrationStub) (up, down []byte) {
+	switch {
+	case stub.CreateTable:
+		up = []byte(fmt.Sprintf(`-- %s
+-- PostgreSQL 語法不同時，另外建立 %s_%s.up.postgres.sql
+CREATE TABLE IF NOT EXISTS %s (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+`, stub.Name, stub.Version, stub.Name, stub.Table))
+		down = []byte(fmt.Sprintf("-- %s\nDROP TABLE IF EXISTS %s;\n", stub.Name, stub.Table))
+	case stub.Table != "":
+		up = []byte(fmt.Sprintf("-- %s\n-- TODO: 修改 %s 表\nALTER TABLE %s ADD COLUMN column_name VARCHAR(255) NULL;\n", stub.Name, stub.Table, stub.Table))
+		down = []byte(fmt.Sprintf("-- %s\n-- TODO: 還原 %s 表的修改\nALTER TABLE %s DROP COLUMN column_name;\n", stub.Name, stub.Table, stub.Table))
+	default:
+		up = []byte(fmt.Sprintf("-- %s\n-- TODO: 在這裡寫 SQL，多個語句以 ; 分隔\n", stub.Name))
+		down = []byte(fmt.Sprintf("-- %s\n-- TODO: 在這裡寫回滾 SQL\n", stub.Name))
+	}
+	return up, down
+}
+
+var migrationTemplate = template.Must(template.New("migration").Parse(`package migrations
+
+import (
+	"fmt"
+{{- if .Table}}
+
+	"my-api/database/schema"
+{{- end}}
+)
+
+// {{.StructName}} - TODO: 說明這個 migration 做了什麼
+type {{.StructName}} struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&{{.StructName}}{
+		BaseMigration: BaseMigration{
+			version:     "{{.Version}}",
+			description: "{{.Name}}",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *{{.StructName}}) Up(db Executor, dialect Dialect) error {
+{{- if .CreateTable}}
+	err := schema.Create("{{.Table}}", func(t *schema.Blueprint) {
+		t.ID()
+		t.Timestamps()
+	}).Run(db, schema.Dialect(dialect))
+	if err != nil {
+		return fmt.Errorf("建立 {{.Table}} 表失敗: %v", err)
+	}
+
+	fmt.Println("✓ 建立 {{.Table}} 表成功")
+	return nil
+{{- else if .Table}}
+	err := schema.Table("{{.Table}}", func(t *schema.Blueprint) {
+		// TODO: 修改 {{.Table}} 表
+		t.String("column_name", 255).Nullable()
+	}).Run(db, schema.Dialect(dialect))
+	if err != nil {
+		return fmt.Errorf("修改 {{.Table}} 表失敗: %v", err)
+	}
+
+	fmt.Println("✓ {{.Name}} 成功")
+	return nil
+{{- else}}
+	statements, err := Statements{
+		// TODO: 在這裡寫 SQL（建立或修改資料表可以使用 schema.Create、schema.Table）
+		MySQL:    {},
+		Postgres: {},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("{{.Name}} 失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ {{.Name}} 成功")
+	return nil
+{{- end}}
+}
+
+// Down - 回滾 migration
+func (m *{{.StructName}}) Down(db Executor, dialect Dialect) error {
+{{- if .CreateTable}}
+	if err := schema.DropIfExists("{{.Table}}").Run(db, schema.Dialect(dialect)); err != nil {
+		return fmt.Errorf("刪除 {{.Table}} 表失敗: %v", err)
+	}
+
+	fmt.Println("✓ 刪除 {{.Table}} 表成功")
+{{- else if .Table}}
+	err := schema.Table("{{.Table}}", func(t *schema.Blueprint) {
+		// TODO: 還原 {{.Table}} 表的修改
+		t.DropColumn("column_name")
+	}).Run(db, schema.Dialect(dialect))
+	if err != nil {
+		return fmt.Errorf("還原 {{.Table}} 表失敗: %v", err)
+	}
+
+	fmt.Println("✓ rollback {{.Name}} 成功")
+{{- else}}
+	statements, err := Statements{
+		// TODO: 在這裡寫回滾 SQL
+		MySQL:    {},
+		Postgres: {},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("rollback {{.Name}} 失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ rollback {{.Name}} 成功")
+{{- end}}
+	return nil
+}
+`))
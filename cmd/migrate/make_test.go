@@ -0,0 +1,183 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGetNextVersion 測試下一個版本號同時考慮 Go 與 sql/ 目錄中的檔案
+func TestGetNextVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{
+			name:  "Go migration",
+			files: []string{"database/migrations/000100_create_tags_table.go"},
+			want:  "000101",
+		},
+		{
+			name: "SQL migration 的版本較大",
+			files: []string{
+				"database/migrations/000100_create_tags_table.go",
+				"database/migrations/sql/000105_add_phone_to_users.up.sql",
+				"database/migrations/sql/000105_add_phone_to_users.down.sql",
+			},
+			want: "000106",
+		},
+		{
+			name: "指定資料庫的 SQL migration",
+			files: []string{
+				"database/migrations/000100_create_tags_table.go",
+				"database/migrations/sql/000120_create_views.up.postgres.sql",
+			},
+			want: "000121",
+		},
+		{
+			name: "忽略不是 migration 的檔案",
+			files: []string{
+				"database/migrations/000100_create_tags_table.go",
+				"database/migrations/sql/README.md",
+				"database/migrations/000900_notes.txt",
+				"database/migrations/registry.go",
+			},
+			want: "000101",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getNextVersion(tt.files); got != tt.want {
+				t.Errorf("getNextVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestToPascalCase 測試 snake_case 轉 PascalCase
+func TestToPascalCase(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"create_users_table", "CreateUsersTable"},
+		{"add_phone_to_users", "AddPhoneToUsers"},
+		{"add_2fa_to_users", "Add2faToUsers"},
+		{"double__underscore_", "DoubleUnderscore"},
+		{"single", "Single"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := toPascalCase(tt.input); got != tt.want {
+				t.Errorf("toPascalCase(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGuessTable 測試從 migration 名稱推測資料表與是否為建立資料表
+func TestGuessTable(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantTable  string
+		wantCreate bool
+	}{
+		{"create_tags_table", "tags", true},
+		{"create_post_tags_table", "post_tags", true},
+		{"add_phone_to_users", "users", false},
+		{"add_phone_to_users_table", "users", false},
+		{"remove_age_from_users", "users", false},
+		{"add_index_in_posts", "posts", false},
+		{"backfill_user_roles", "", false},
+		{"create_tags", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, create := guessTable(tt.name)
+			if table != tt.wantTable || create != tt.wantCreate {
+				t.Errorf("guessTable(%q) = %q, %v, want %q, %v", tt.name, table, create, tt.wantTable, tt.wantCreate)
+			}
+		})
+	}
+}
+
+// TestNewMigrationStub 測試名稱正規化、版本號與同名檢查
+func TestNewMigrationStub(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "000100_create_tags_table.go"), "package migrations\n")
+	writeFile(t, filepath.Join(dir, "sql", "000110_add_phone_to_users.up.sql"), "ALTER TABLE users ADD COLUMN phone VARCHAR(20);\n")
+
+	stub, err := newMigrationStub(dir, " Add-Avatar To Users ")
+	if err != nil {
+		t.Fatalf("newMigrationStub() 發生錯誤: %v", err)
+	}
+	if stub.Version != "000111" || stub.Name != "add_avatar_to_users" || stub.StructName != "AddAvatarToUsers" {
+		t.Errorf("newMigrationStub() = %+v", stub)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"與 Go migration 同名", "create_tags_table", "已存在同名的 migration"},
+		{"與 SQL migration 同名", "add_phone_to_users", "已存在同名的 migration"},
+		{"數字開頭", "1st_migration", "只能包含小寫英文、數字與底線"},
+		{"特殊字元", "add_phone!", "只能包含小寫英文、數字與底線"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newMigrationStub(dir, tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("newMigrationStub(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNewMigrationStub_MissingSQLDir 測試沒有 sql/ 目錄時回傳錯誤（不在專案根目錄執行）
+func TestNewMigrationStub_MissingSQLDir(t *testing.T) {
+	if _, err := newMigrationStub(t.TempDir(), "create_tags_table"); err == nil {
+		t.Error("沒有 sql/ 目錄時應回傳錯誤")
+	}
+}
+
+// TestCreateFile 測試已存在的檔案不會被覆寫
+func TestCreateFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "000100_create_tags_table.go")
+
+	if err := createFile(filename, []byte("original")); err != nil {
+		t.Fatalf("createFile() 發生錯誤: %v", err)
+	}
+
+	err := createFile(filename, []byte("overwritten"))
+	if err == nil || !strings.Contains(err.Error(), "不會覆寫") {
+		t.Errorf("createFile() error = %v, want 檔案已存在", err)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "original" {
+		t.Errorf("檔案內容 = %q, want original", content)
+	}
+}
+
+// writeFile - 建立測試用的檔案（包含上層目錄）
+func writeFile(t *testing.T, filename, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -13,9 +14,65 @@ type Config struct {
 	Database DatabaseConfig
 	Redis    RedisConfig
 	JWT      JWTConfig
+	Auth     AuthConfig
+	OIDC     OIDCConfig
+	Account  AccountConfig
+	Mail     MailConfig
 	Log      LogConfig
 }
 
+type AuthConfig struct {
+	PasswordResetURL           string // 重設密碼頁面網址（信件中的連結，會附上 ?token=）
+	PasswordResetExpiryMinutes int    // 重設密碼 Token 有效期（分鐘）
+	PasswordSetupExpiryHours   int    // 管理員建立帳號時寄出的設定密碼連結有效期（小時）
+	VerificationExpiryMinutes  int    // Email 驗證連結有效期（分鐘）
+
+	LoginMaxAttemptsPerEmail int // 同一 Email 連續失敗幾次後鎖定
+	LoginMaxAttemptsPerIP    int // 同一 IP 連續失敗幾次後鎖定
+	LoginLockoutSeconds      int // 第一次鎖定秒數，之後每多失敗一次加倍
+	LoginMaxLockoutSeconds   int // 鎖定秒數上限
+	LoginDecayMinutes        int // 沒有新的失敗多久後計數歸零（分鐘）
+
+	TwoFactorIssuer           string // 驗證器 App 上顯示的服務名稱
+	TwoFactorChallengeMinutes int    // 登入後輸入兩步驟驗證碼的期限（分鐘）
+
+	PasswordMinLength        int  // 密碼最少字元數
+	PasswordRequireMixedCase bool // 密碼需同時包含大小寫字母
+	PasswordRequireNumbers   bool // 密碼需包含數字
+	PasswordRequireSymbols   bool // 密碼需包含符號
+	PasswordRejectUserInfo   bool // 密碼不可包含名稱或 Email
+}
+
+type OIDCConfig struct {
+	Providers         map[string]OIDCProviderConfig // 以名稱識別，對應路由 /api/auth/oidc/:provider
+	StateTTLMinutes   int                           // 導向 IdP 後完成登入的期限（分鐘）
+	AllowSignup       bool                          // IdP 的使用者沒有對應帳號時自動建立
+	LinkVerifiedEmail bool                          // IdP 確認過的 Email 與既有帳號相同時自動連結
+}
+
+type OIDCProviderConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // 預設為 APP_URL/api/auth/oidc/:provider/callback
+	Scopes       []string // 預設為 openid email profile
+}
+
+type AccountConfig struct {
+	DeletionGraceDays            int    // 申請刪除帳號後可以取消的天數，0 表示立即刪除
+	DeletionPostPolicy           string // 刪除帳號時文章的處理方式：delete（刪除）、reassign（轉移）
+	DeletionReassignTo           uint   // reassign 時接收文章的使用者 ID，0 表示保留在匿名化後的帳號
+	DeletionCheckIntervalMinutes int    // 多久檢查一次寬限期已過的刪除申請（分鐘）
+	DeletionConfirmMinutes       int    // 沒有輸入密碼時寄出的確認刪除連結有效期（分鐘）
+}
+
+type MailConfig struct {
+	Driver      string // log, file, array
+	FromAddress string
+	FromName    string
+	FilePath    string // file driver 的信件存放目錄
+}
+
 type LogConfig struct {
 	Level      string // debug, info, warn, error, fatal
 	Format     string // console, json
@@ -28,13 +85,27 @@ type LogConfig struct {
 }
 
 type JWTConfig struct {
-	Secret      string
-	ExpiryHours int
+	Secret              string
+	AccessExpiryMinutes int // Access Token 有效期（分鐘）
+	RefreshExpiryHours  int // Refresh Token 有效期（小時）
+
+	Algorithm      string   // 簽章演算法：HS256（使用 Secret）、RS256、ES256、EdDSA
+	PrivateKeyPath string   // 非對稱演算法的簽章私鑰（PEM）
+	PublicKeyPaths []string // 額外的驗證公鑰（PEM），金鑰輪替期間保留舊金鑰
+	Issuer         string   // iss
+	Audience       []string // aud，空白時不簽發也不檢查
+	LeewaySeconds  int      // 驗證 exp / nbf / iat 時容許的時鐘誤差（秒）
 }
 
 type AppConfig struct {
 	Env  string
 	Port string
+	URL  string // API 對外網址（信件中的簽章連結使用）
+	Key  string // 簽章連結的 HMAC 金鑰，也用來加密 TOTP 金鑰等資料
+
+	// TrustedProxies - 信任的反向代理 IP 或 CIDR，只有來自這些位址的 X-Forwarded-For 才會用來判斷用戶端 IP
+	// 未設定時不信任任何代理，以連線的來源 IP 為準（避免偽造 X-Forwarded-For 繞過以 IP 計數的登入鎖定）
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -45,15 +116,22 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string // For PostgreSQL
+
+	MigrationLockTimeout    int  // 等待其他程序釋放 migration 鎖的秒數，0 表示不等待
+	MigrationStrictChecksum bool // 已執行的 migration 內容被修改時中止 migrate（預設只記錄警告）
 }
 
 type RedisConfig struct {
+	Enabled  bool // 是否啟用 Redis（Token 撤銷清單等功能會改用 Redis 儲存）
 	Host     string
 	Port     string
 	Password string
 	DB       string
 }
 
+// PlaceholderAppKey - 未設定 APP_KEY 時的預設值（與 .env.example 相同），正式環境啟動時會拒絕
+const PlaceholderAppKey = "your-app-key-change-in-production"
+
 // GlobalConfig 全域配置變數
 var GlobalConfig *Config
 
@@ -82,6 +160,10 @@ func LoadConfig() {
 		App: AppConfig{
 			Env:  getEnv("APP_ENV", "development"),
 			Port: getEnv("APP_PORT", "8080"),
+			URL:  getEnv("APP_URL", "http://localhost:8080"),
+			Key:  getEnv("APP_KEY", PlaceholderAppKey),
+
+			TrustedProxies: getEnvAsList("APP_TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Type:     getEnv("DB_TYPE", "mysql"),
@@ -91,16 +173,64 @@ func LoadConfig() {
 			Password: getEnv("DB_PASSWORD", ""),
 			DBName:   getEnv("DB_NAME", "test"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			MigrationLockTimeout:    getEnvAsInt("DB_MIGRATION_LOCK_TIMEOUT", 60),
+			MigrationStrictChecksum: getEnvAsBool("DB_MIGRATION_STRICT_CHECKSUM", false),
 		},
 		Redis: RedisConfig{
+			Enabled:  getEnvAsBool("REDIS_ENABLED", false),
 			Host:     getEnv("REDIS_HOST", "127.0.0.1"),
 			Port:     getEnv("REDIS_PORT", "6379"),
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnv("REDIS_DB", "0"),
 		},
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "your-super-secret-key-change-in-production"),
-			ExpiryHours: getEnvAsInt("JWT_EXPIRY_HOURS", 24),
+			Secret:              getEnv("JWT_SECRET", "your-super-secret-key-change-in-production"),
+			AccessExpiryMinutes: loadAccessExpiryMinutes(),
+			RefreshExpiryHours:  getEnvAsInt("JWT_REFRESH_EXPIRY_HOURS", 720),
+			Algorithm:           getEnv("JWT_ALGORITHM", "HS256"),
+			PrivateKeyPath:      getEnv("JWT_PRIVATE_KEY_PATH", ""),
+			PublicKeyPaths:      getEnvAsList("JWT_PUBLIC_KEY_PATHS"),
+			Issuer:              getEnv("JWT_ISSUER", "my-api"),
+			Audience:            getEnvAsList("JWT_AUDIENCE"),
+			LeewaySeconds:       getEnvAsInt("JWT_LEEWAY_SECONDS", 0),
+		},
+		Auth: AuthConfig{
+			PasswordResetURL:           getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+			PasswordResetExpiryMinutes: getEnvAsInt("PASSWORD_RESET_EXPIRY_MINUTES", 60),
+			PasswordSetupExpiryHours:   getEnvAsInt("PASSWORD_SETUP_EXPIRY_HOURS", 72),
+			VerificationExpiryMinutes:  getEnvAsInt("EMAIL_VERIFICATION_EXPIRY_MINUTES", 60),
+			LoginMaxAttemptsPerEmail:   getEnvAsInt("LOGIN_MAX_ATTEMPTS_PER_EMAIL", 5),
+			LoginMaxAttemptsPerIP:      getEnvAsInt("LOGIN_MAX_ATTEMPTS_PER_IP", 20),
+			LoginLockoutSeconds:        getEnvAsInt("LOGIN_LOCKOUT_SECONDS", 60),
+			LoginMaxLockoutSeconds:     getEnvAsInt("LOGIN_MAX_LOCKOUT_SECONDS", 3600),
+			LoginDecayMinutes:          getEnvAsInt("LOGIN_DECAY_MINUTES", 15),
+			TwoFactorIssuer:            getEnv("TWO_FACTOR_ISSUER", "My API"),
+			TwoFactorChallengeMinutes:  getEnvAsInt("TWO_FACTOR_CHALLENGE_MINUTES", 5),
+			PasswordMinLength:          getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+			PasswordRequireMixedCase:   getEnvAsBool("PASSWORD_REQUIRE_MIXED_CASE", false),
+			PasswordRequireNumbers:     getEnvAsBool("PASSWORD_REQUIRE_NUMBERS", false),
+			PasswordRequireSymbols:     getEnvAsBool("PASSWORD_REQUIRE_SYMBOLS", false),
+			PasswordRejectUserInfo:     getEnvAsBool("PASSWORD_REJECT_USER_INFO", true),
+		},
+		OIDC: OIDCConfig{
+			Providers:         loadOIDCProviders(getEnv("APP_URL", "http://localhost:8080")),
+			StateTTLMinutes:   getEnvAsInt("OIDC_STATE_TTL_MINUTES", 10),
+			AllowSignup:       getEnvAsBool("OIDC_ALLOW_SIGNUP", true),
+			LinkVerifiedEmail: getEnvAsBool("OIDC_LINK_VERIFIED_EMAIL", true),
+		},
+		Account: AccountConfig{
+			DeletionGraceDays:            getEnvAsInt("ACCOUNT_DELETION_GRACE_DAYS", 30),
+			DeletionPostPolicy:           getEnv("ACCOUNT_DELETION_POST_POLICY", "delete"),
+			DeletionReassignTo:           uint(getEnvAsInt("ACCOUNT_DELETION_REASSIGN_TO", 0)),
+			DeletionCheckIntervalMinutes: getEnvAsInt("ACCOUNT_DELETION_CHECK_INTERVAL_MINUTES", 60),
+			DeletionConfirmMinutes:       getEnvAsInt("ACCOUNT_DELETION_CONFIRM_MINUTES", 60),
+		},
+		Mail: MailConfig{
+			Driver:      getEnv("MAIL_DRIVER", "log"),
+			FromAddress: getEnv("MAIL_FROM_ADDRESS", "noreply@example.com"),
+			FromName:    getEnv("MAIL_FROM_NAME", "My API"),
+			FilePath:    getEnv("MAIL_FILE_PATH", "storage/mail"),
 		},
 		Log: LogConfig{
 			Level:      getEnv("LOG_LEVEL", "debug"),
@@ -145,3 +275,42 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return value == "true" || value == "1" || value == "yes"
 }
+
+// loadAccessExpiryMinutes - Access Token 有效期（JWT_ACCESS_EXPIRY_MINUTES）
+// 未設定時沿用舊版的 JWT_EXPIRY_HOURS（已棄用，下一版移除），兩者都沒有時為 15 分鐘
+func loadAccessExpiryMinutes() int {
+	if os.Getenv("JWT_ACCESS_EXPIRY_MINUTES") == "" && os.Getenv("JWT_EXPIRY_HOURS") != "" {
+		log.Println("警告: JWT_EXPIRY_HOURS 已棄用，請改用 JWT_ACCESS_EXPIRY_MINUTES")
+		return getEnvAsInt("JWT_EXPIRY_HOURS", 24) * 60
+	}
+	return getEnvAsInt("JWT_ACCESS_EXPIRY_MINUTES", 15)
+}
+
+// 獲取以逗號分隔的環境變數，去除空白與空項目
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// loadOIDCProviders - 依 OIDC_PROVIDERS（逗號分隔的名稱）載入各 IdP 設定
+// 每個 IdP 讀取 OIDC_<NAME>_ISSUER、_CLIENT_ID、_CLIENT_SECRET、_REDIRECT_URL、_SCOPES
+func loadOIDCProviders(appURL string) map[string]OIDCProviderConfig {
+	providers := make(map[string]OIDCProviderConfig)
+	for _, name := range getEnvAsList("OIDC_PROVIDERS") {
+		name = strings.ToLower(name)
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		providers[name] = OIDCProviderConfig{
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", strings.TrimSuffix(appURL, "/")+"/api/auth/oidc/"+name+"/callback"),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "")),
+		}
+	}
+	return providers
+}
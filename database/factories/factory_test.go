@@ -0,0 +1,134 @@
+package factories
+
+import (
+	"testing"
+	"time"
+
+	"my-api/app/models"
+	"my-api/app/utils"
+)
+
+// TestUser_Defaults - 預設的使用者：已驗證、user 角色、密碼為 DefaultPassword
+func TestUser_Defaults(t *testing.T) {
+	u := User().Make()
+
+	if u.Name == "" || u.Email == "" {
+		t.Errorf("Make() 名稱或 Email 為空: %+v", u)
+	}
+	if u.EmailVerifiedAt == nil {
+		t.Error("Make() 預設應該已驗證 Email")
+	}
+	if !utils.CheckPassword(DefaultPassword, u.Password) {
+		t.Error("Make() 密碼不是 DefaultPassword 的雜湊")
+	}
+	if u.Age < 18 || u.Age > 65 {
+		t.Errorf("Make() Age = %d，應介於 18 到 65", u.Age)
+	}
+	if len(u.Roles) != 1 || u.Roles[0].Name != models.RoleUser {
+		t.Errorf("Make() Roles = %+v，應只有 user 角色", u.Roles)
+	}
+}
+
+// TestUser_States - 各種狀態
+func TestUser_States(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory *UserFactory
+		check   func(u models.User) bool
+	}{
+		{
+			name:    "尚未驗證 Email",
+			factory: User().Unverified(),
+			check:   func(u models.User) bool { return u.EmailVerifiedAt == nil },
+		},
+		{
+			name:    "指定密碼",
+			factory: User().WithPassword("secret123"),
+			check:   func(u models.User) bool { return utils.CheckPassword("secret123", u.Password) },
+		},
+		{
+			name:    "管理員",
+			factory: User().Admin(),
+			check: func(u models.User) bool {
+				return len(u.Roles) == 2 && u.Roles[1].Name == models.RoleAdmin
+			},
+		},
+		{
+			name:    "指定角色",
+			factory: User().WithRoles(models.RoleAdmin),
+			check: func(u models.User) bool {
+				return len(u.Roles) == 1 && u.Roles[0].Name == models.RoleAdmin
+			},
+		},
+		{
+			name:    "已申請刪除帳號",
+			factory: User().PendingDeletion(24 * time.Hour),
+			check: func(u models.User) bool {
+				return u.DeletionScheduledAt != nil && u.DeletionScheduledAt.After(time.Now())
+			},
+		},
+		{
+			name:    "自訂狀態",
+			factory: User().State(func(u *models.User) { u.Name = "王小明" }),
+			check:   func(u models.User) bool { return u.Name == "王小明" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if u := tt.factory.Make(); !tt.check(u) {
+				t.Errorf("Make() = %+v，不符合預期的狀態", u)
+			}
+		})
+	}
+}
+
+// TestUser_StateDoesNotMutate - 串接狀態不會影響原本的 Factory
+func TestUser_StateDoesNotMutate(t *testing.T) {
+	base := User()
+	_ = base.Unverified().Admin()
+
+	u := base.Make()
+	if u.EmailVerifiedAt == nil {
+		t.Error("原本的 Factory 被 Unverified() 修改了")
+	}
+	if len(u.Roles) != 1 {
+		t.Errorf("原本的 Factory 被 Admin() 修改了: %+v", u.Roles)
+	}
+}
+
+// TestUser_MakeMany - Count 筆資料，Email 不重複
+func TestUser_MakeMany(t *testing.T) {
+	users := User().Count(50).MakeMany()
+	if len(users) != 50 {
+		t.Fatalf("MakeMany() 產生 %d 筆，want 50", len(users))
+	}
+
+	seen := make(map[string]bool)
+	for _, u := range users {
+		if seen[u.Email] {
+			t.Errorf("MakeMany() Email 重複: %s", u.Email)
+		}
+		seen[u.Email] = true
+	}
+}
+
+// TestPost - 文章的預設值與狀態
+func TestPost(t *testing.T) {
+	p := Post().Make()
+	if p.Title == "" || p.Content == "" || p.Description == "" {
+		t.Errorf("Make() 有欄位為空: %+v", p)
+	}
+	if p.UserID != 0 {
+		t.Errorf("Make() 未指定作者時 UserID 應為 0，got %d", p.UserID)
+	}
+
+	author := User().State(func(u *models.User) { u.ID = 42 }).Make()
+	p = Post().ForUser(&author).WithoutDescription().Make()
+	if p.UserID != 42 {
+		t.Errorf("ForUser() UserID = %d，want 42", p.UserID)
+	}
+	if p.Description != "" {
+		t.Errorf("WithoutDescription() Description = %q，want 空字串", p.Description)
+	}
+}
@@ -0,0 +1,74 @@
+package factories
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	surnames   = []string{"陳", "林", "黃", "張", "李", "王", "吳", "劉", "蔡", "楊", "許", "鄭", "謝", "郭", "洪"}
+	givenNames = []string{"怡君", "志明", "雅婷", "俊傑", "淑芬", "建宏", "佳穎", "家豪", "美玲", "冠宇", "詩涵", "宗翰", "欣怡", "承恩", "宜蓁"}
+	nicknames  = []string{"alex", "amy", "ben", "cindy", "david", "emma", "frank", "grace", "henry", "ivy", "jack", "kelly", "leo", "mia", "nick"}
+
+	topics  = []string{"Go", "Gin", "GORM", "Docker", "PostgreSQL", "MySQL", "Redis", "JWT", "微服務", "單元測試", "效能調校", "API 設計"}
+	titles  = []string{"%s 入門筆記", "用 %s 打造可維護的專案", "%s 常見問題整理", "我在正式環境踩過的 %s 坑", "%s 最佳實踐", "從零開始學 %s"}
+	phrases = []string{
+		"這篇文章整理了實際專案中的經驗",
+		"先從最基本的設定開始",
+		"接著說明常見的錯誤與解法",
+		"正式環境需要特別注意效能與安全性",
+		"範例程式碼都可以直接執行",
+		"測試是確保重構不出錯的關鍵",
+		"設定檔建議透過環境變數管理",
+		"遇到問題時先查看日誌",
+		"文件寫清楚可以省下很多溝通成本",
+		"最後附上參考資料供延伸閱讀",
+	}
+
+	sequence atomic.Uint64 // 讓同一個程序內產生的 Email 不重複
+)
+
+// fakeName - 中文姓名，例如 陳怡君
+func fakeName() string {
+	return pick(surnames) + pick(givenNames)
+}
+
+// fakeEmail - 不重複的 Email，例如 amy.3f9a1c2b@example.com
+// 加上隨機值，重複執行 seeder 時也不會與既有資料衝突
+func fakeEmail() string {
+	return fmt.Sprintf("%s.%x%x@example.com", pick(nicknames), sequence.Add(1), rand.Uint32())
+}
+
+// fakeTitle - 文章標題
+func fakeTitle() string {
+	return fmt.Sprintf(pick(titles), pick(topics))
+}
+
+// fakeSentence - 由 n 個片語組成的句子
+func fakeSentence(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = pick(phrases)
+	}
+	return strings.Join(parts, "，") + "。"
+}
+
+// fakeParagraphs - n 個段落，以空行分隔
+func fakeParagraphs(n int) string {
+	paragraphs := make([]string, n)
+	for i := range paragraphs {
+		paragraphs[i] = fakeSentence(between(2, 4)) + fakeSentence(between(2, 4))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// between - min 到 max 之間的整數（含）
+func between(min, max int) int {
+	return min + rand.IntN(max-min+1)
+}
+
+func pick(values []string) string {
+	return values[rand.IntN(len(values))]
+}
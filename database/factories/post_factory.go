@@ -0,0 +1,57 @@
+package factories
+
+import (
+	"gorm.io/gorm"
+	"my-api/app/models"
+)
+
+// PostFactory - models.Post 的 Factory
+// 沒有指定作者時，寫入資料庫前會以 User Factory 建立一位
+type PostFactory struct {
+	*Factory[models.Post]
+}
+
+// Post - 建立 Post Factory
+func Post() *PostFactory {
+	return &PostFactory{New(func() models.Post {
+		return models.Post{
+			Title:       fakeTitle(),
+			Content:     fakeParagraphs(between(2, 5)),
+			Description: fakeSentence(2),
+		}
+	}).BeforeCreate(func(tx *gorm.DB, p *models.Post) error {
+		if p.UserID != 0 {
+			return nil
+		}
+		user, err := User().Create(tx)
+		if err != nil {
+			return err
+		}
+		p.UserID = user.ID
+		return nil
+	})}
+}
+
+// Count - CreateMany、MakeMany 產生的數量
+func (f *PostFactory) Count(n int) *PostFactory {
+	return &PostFactory{f.Factory.Count(n)}
+}
+
+// State - 自訂狀態
+func (f *PostFactory) State(fn func(p *models.Post)) *PostFactory {
+	return &PostFactory{f.Factory.State(fn)}
+}
+
+// ForUser - 指定作者
+func (f *PostFactory) ForUser(user *models.User) *PostFactory {
+	return f.State(func(p *models.Post) {
+		p.UserID = user.ID
+	})
+}
+
+// WithoutDescription - 沒有摘要的文章
+func (f *PostFactory) WithoutDescription() *PostFactory {
+	return f.State(func(p *models.Post) {
+		p.Description = ""
+	})
+}
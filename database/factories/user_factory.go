@@ -0,0 +1,127 @@
+package factories
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"my-api/app/models"
+	"my-api/app/utils"
+)
+
+// DefaultPassword - User Factory 產生的使用者密碼，測試與本機登入時使用
+const DefaultPassword = "password"
+
+// defaultPasswordHash - bcrypt 很慢，預設密碼只雜湊一次
+var defaultPasswordHash = sync.OnceValue(func() string {
+	return mustHashPassword(DefaultPassword)
+})
+
+// UserFactory - models.User 的 Factory
+// 預設為已驗證 Email、擁有 user 角色的使用者，密碼為 DefaultPassword
+type UserFactory struct {
+	*Factory[models.User]
+}
+
+// User - 建立 User Factory
+func User() *UserFactory {
+	return &UserFactory{New(func() models.User {
+		now := time.Now()
+		return models.User{
+			Name:            fakeName(),
+			Email:           fakeEmail(),
+			EmailVerifiedAt: &now,
+			Password:        defaultPasswordHash(),
+			Age:             between(18, 65),
+			Roles:           []models.Role{{Name: models.RoleUser}},
+		}
+	}).BeforeCreate(resolveRoles)}
+}
+
+// Count - CreateMany、MakeMany 產生的數量
+func (f *UserFactory) Count(n int) *UserFactory {
+	return &UserFactory{f.Factory.Count(n)}
+}
+
+// State - 自訂狀態
+func (f *UserFactory) State(fn func(u *models.User)) *UserFactory {
+	return &UserFactory{f.Factory.State(fn)}
+}
+
+// Unverified - 尚未驗證 Email
+func (f *UserFactory) Unverified() *UserFactory {
+	return f.State(func(u *models.User) {
+		u.EmailVerifiedAt = nil
+	})
+}
+
+// WithPassword - 指定密碼（以 utils.HashPassword 雜湊）
+func (f *UserFactory) WithPassword(password string) *UserFactory {
+	hash := mustHashPassword(password)
+	return f.State(func(u *models.User) {
+		u.Password = hash
+	})
+}
+
+// WithRoles - 指定角色（取代預設的 user 角色），寫入資料庫時角色需已存在
+func (f *UserFactory) WithRoles(names ...string) *UserFactory {
+	return f.State(func(u *models.User) {
+		u.Roles = make([]models.Role, len(names))
+		for i, name := range names {
+			u.Roles[i] = models.Role{Name: name}
+		}
+	})
+}
+
+// Admin - 系統管理員（admin 與 user 角色）
+func (f *UserFactory) Admin() *UserFactory {
+	return f.WithRoles(models.RoleUser, models.RoleAdmin)
+}
+
+// PendingDeletion - 已申請刪除帳號，預定在 after 之後刪除
+func (f *UserFactory) PendingDeletion(after time.Duration) *UserFactory {
+	return f.State(func(u *models.User) {
+		scheduledAt := time.Now().Add(after)
+		u.DeletionScheduledAt = &scheduledAt
+	})
+}
+
+// HasPosts - 寫入資料庫後為使用者建立 n 篇文章
+func (f *UserFactory) HasPosts(n int) *UserFactory {
+	return &UserFactory{f.AfterCreate(func(tx *gorm.DB, u *models.User) error {
+		_, err := Post().ForUser(u).Count(n).CreateMany(tx)
+		return err
+	})}
+}
+
+// resolveRoles - 以角色名稱查詢資料庫中的角色（由 migration 建立），關聯時使用既有的 ID
+func resolveRoles(tx *gorm.DB, u *models.User) error {
+	if len(u.Roles) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(u.Roles))
+	for i, role := range u.Roles {
+		names[i] = role.Name
+	}
+
+	var roles []models.Role
+	if err := tx.Where("name IN ?", names).Find(&roles).Error; err != nil {
+		return err
+	}
+	if len(roles) != len(names) {
+		return fmt.Errorf("找不到角色 %v，請先執行 migrate", names)
+	}
+
+	u.Roles = roles
+	return nil
+}
+
+func mustHashPassword(password string) string {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		panic(fmt.Sprintf("雜湊密碼失敗: %v", err))
+	}
+	return hash
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"strings"
+	"time"
+
+	"my-api/bootstrap"
+	"my-api/config"
+	"my-api/database/migrations"
+)
+
+// ErrMigrationLockTimeout - 等待其他程序釋放 migration 鎖逾時
+var ErrMigrationLockTimeout = errors.New("等待 migration 鎖逾時，可能有其他程序正在執行 migration")
+
+// migrationLock - 跨程序的 migration 鎖（MySQL GET_LOCK、Postgres pg_advisory_lock）
+// 多台機器同時啟動時只有一台會執行 migration，其他的等待鎖釋放後再重新檢查
+// 鎖綁定在資料庫連線上，因此使用獨立的 *sql.Conn，程序異常結束時資料庫也會自動釋放
+type migrationLock struct {
+	conn    *sql.Conn
+	dialect migrations.Dialect
+	name    string // MySQL 鎖名稱（整個 MySQL server 共用，因此包含資料庫名稱）
+	key     int64  // Postgres advisory lock 的 key
+}
+
+// acquireMigrationLock - 取得 migration 鎖，最多等待 timeout
+func acquireMigrationLock(db *sql.DB, dialect migrations.Dialect, timeout time.Duration) (*migrationLock, error) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("無法連接資料庫: %v", err)
+	}
+
+	name := "migrations:" + config.GlobalConfig.Database.DBName
+	lock := &migrationLock{
+		conn:    conn,
+		dialect: dialect,
+		name:    name,
+		key:     int64(crc32.ChecksumIEEE([]byte(name))),
+	}
+
+	acquired, err := lock.try(ctx)
+	if err == nil && !acquired {
+		bootstrap.Log.Warning("migration 鎖被其他程序持有，等待釋放", lock.holder(ctx, map[string]interface{}{
+			"lock":    name,
+			"timeout": timeout.String(),
+		}))
+
+		if acquired, err = lock.wait(ctx, timeout); err == nil && acquired {
+			bootstrap.Log.Info("取得 migration 鎖，重新檢查待執行的 migrations", lock.owner(ctx))
+		}
+	} else if err == nil {
+		bootstrap.Log.Debug("取得 migration 鎖", lock.owner(ctx))
+	}
+
+	if err != nil || !acquired {
+		conn.Close()
+		if err != nil {
+			return nil, fmt.Errorf("取得 migration 鎖失敗: %v", err)
+		}
+		return nil, ErrMigrationLockTimeout
+	}
+
+	return lock, nil
+}
+
+// try - 不等待，立即嘗試取得鎖
+func (l *migrationLock) try(ctx context.Context) (bool, error) {
+	var acquired sql.NullBool
+	var err error
+	if l.dialect == migrations.Postgres {
+		err = l.conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired)
+	} else {
+		err = l.conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", l.name).Scan(&acquired)
+	}
+	return acquired.Valid && acquired.Bool, err
+}
+
+// wait - 等待鎖釋放，最多等待 timeout
+func (l *migrationLock) wait(ctx context.Context, timeout time.Duration) (bool, error) {
+	if timeout <= 0 {
+		return false, nil
+	}
+
+	if l.dialect != migrations.Postgres {
+		// GET_LOCK 逾時回傳 0，發生錯誤（例如連線被 KILL）回傳 NULL
+		var acquired sql.NullBool
+		seconds := max(int(timeout/time.Second), 1)
+		if err := l.conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", l.name, seconds).Scan(&acquired); err != nil {
+			return false, err
+		}
+		if !acquired.Valid {
+			return false, errors.New("GET_LOCK 回傳 NULL")
+		}
+		return acquired.Bool, nil
+	}
+
+	// pg_advisory_lock 會一直等待，以 lock_timeout 限制等待時間
+	if _, err := l.conn.ExecContext(ctx, fmt.Sprintf("SET lock_timeout = %d", timeout.Milliseconds())); err != nil {
+		return false, err
+	}
+	defer l.conn.ExecContext(ctx, "RESET lock_timeout")
+
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", l.key); err != nil {
+		// 55P03 lock_not_available
+		if strings.Contains(err.Error(), "lock timeout") || strings.Contains(err.Error(), "55P03") {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Release - 釋放鎖並關閉連線
+func (l *migrationLock) Release() error {
+	ctx := context.Background()
+	defer l.conn.Close()
+
+	var err error
+	if l.dialect == migrations.Postgres {
+		_, err = l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	} else {
+		_, err = l.conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", l.name)
+	}
+	return err
+}
+
+// owner - 目前程序的識別資訊，與其他程序等待時記錄的 holder 對照
+func (l *migrationLock) owner(ctx context.Context) map[string]interface{} {
+	hostname, _ := os.Hostname()
+	fields := map[string]interface{}{
+		"lock":     l.name,
+		"hostname": hostname,
+		"pid":      os.Getpid(),
+	}
+
+	query := "SELECT CONNECTION_ID()"
+	if l.dialect == migrations.Postgres {
+		query = "SELECT pg_backend_pid()"
+	}
+	var connectionID int64
+	if err := l.conn.QueryRowContext(ctx, query).Scan(&connectionID); err == nil {
+		fields["connection_id"] = connectionID
+	}
+
+	return fields
+}
+
+// holder - 查詢持有鎖的連線資訊，查不到時（例如權限不足）只回傳連線 ID 或原本的欄位
+func (l *migrationLock) holder(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	var (
+		connectionID sql.NullInt64
+		host         sql.NullString
+		user         sql.NullString
+		since        sql.NullString // Postgres 連線建立時間
+	)
+
+	if l.dialect == migrations.Postgres {
+		err := l.conn.QueryRowContext(ctx, `
+			SELECT a.pid, COALESCE(host(a.client_addr), ''), a.usename, a.backend_start::text
+			FROM pg_locks l JOIN pg_stat_activity a ON a.pid = l.pid
+			WHERE l.locktype = 'advisory' AND l.granted
+				AND l.classid = 0 AND l.objid::bigint = $1 AND l.objsubid = 1
+		`, l.key).Scan(&connectionID, &host, &user, &since)
+		if err != nil {
+			return fields
+		}
+	} else {
+		if err := l.conn.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?)", l.name).Scan(&connectionID); err != nil || !connectionID.Valid {
+			return fields
+		}
+		// 沒有 PROCESS 權限時只看得到自己帳號的連線
+		l.conn.QueryRowContext(ctx,
+			"SELECT host, user FROM information_schema.processlist WHERE id = ?",
+			connectionID.Int64,
+		).Scan(&host, &user)
+	}
+
+	fields["holder_connection_id"] = connectionID.Int64
+	if host.Valid {
+		fields["holder_host"] = host.String
+	}
+	if user.Valid {
+		fields["holder_user"] = user.String
+	}
+	if since.Valid {
+		fields["holder_since"] = since.String
+	}
+	return fields
+}
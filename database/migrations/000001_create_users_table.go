@@ -1,7 +1,6 @@
 package migrations
 
 import (
-	"database/sql"
 	"fmt"
 )
 
@@ -20,39 +19,58 @@ func init() {
 }
 
 // Up - 執行 migration
-func (m *CreateUsersTable) Up(db *sql.DB) error {
-	query := `
-		CREATE TABLE IF NOT EXISTS users (
-			id BIGINT AUTO_INCREMENT PRIMARY KEY,
-			name VARCHAR(255) NOT NULL,
-			email VARCHAR(255) UNIQUE NOT NULL,
-			age INT DEFAULT 0,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-			deleted_at TIMESTAMP NULL,
-			INDEX idx_email (email),
-			INDEX idx_deleted_at (deleted_at)
-		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-	`
-	
-	_, err := db.Exec(query)
+// Postgres 的索引名稱在整個 schema 內必須唯一，因此索引名稱加上資料表前綴
+func (m *CreateUsersTable) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {`
+			CREATE TABLE IF NOT EXISTS users (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				email VARCHAR(255) UNIQUE NOT NULL,
+				age INT DEFAULT 0,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				deleted_at TIMESTAMP NULL,
+				INDEX idx_email (email),
+				INDEX idx_deleted_at (deleted_at)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+		`},
+		Postgres: {
+			`CREATE TABLE IF NOT EXISTS users (
+				id BIGSERIAL PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				email VARCHAR(255) UNIQUE NOT NULL,
+				age INT DEFAULT 0,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				deleted_at TIMESTAMPTZ NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at)`,
+		},
+	}.For(dialect)
 	if err != nil {
-		return fmt.Errorf("建立 users 表失敗: %v", err)
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("建立 users 表失敗: %v", err)
+		}
 	}
-	
+
 	fmt.Println("✓ 建立 users 表成功")
 	return nil
 }
 
 // Down - 回滾 migration
-func (m *CreateUsersTable) Down(db *sql.DB) error {
+func (m *CreateUsersTable) Down(db Executor, dialect Dialect) error {
 	query := `DROP TABLE IF EXISTS users;`
-	
+
 	_, err := db.Exec(query)
 	if err != nil {
 		return fmt.Errorf("刪除 users 表失敗: %v", err)
 	}
-	
+
 	fmt.Println("✓ 刪除 users 表成功")
 	return nil
 }
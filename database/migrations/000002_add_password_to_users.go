@@ -1,7 +1,6 @@
 package migrations
 
 import (
-	"database/sql"
 	"fmt"
 	"strings"
 )
@@ -21,11 +20,17 @@ func init() {
 }
 
 // Up - 執行 migration
-func (m *AddPasswordToUsers) Up(db *sql.DB) error {
-	// 直接嘗試新增欄位，如果已存在會報錯
-	query := `ALTER TABLE users ADD COLUMN password VARCHAR(255) DEFAULT '' AFTER email;`
+func (m *AddPasswordToUsers) Up(db Executor, dialect Dialect) error {
+	// MySQL 直接嘗試新增欄位，如果已存在會報錯；Postgres 使用 IF NOT EXISTS
+	query, err := SQL{
+		MySQL:    `ALTER TABLE users ADD COLUMN password VARCHAR(255) DEFAULT '' AFTER email;`,
+		Postgres: `ALTER TABLE users ADD COLUMN IF NOT EXISTS password VARCHAR(255) DEFAULT ''`,
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
 
-	_, err := db.Exec(query)
+	_, err = db.Exec(query)
 	if err != nil {
 		// 如果欄位已存在，MySQL 會報 "Duplicate column name" 錯誤
 		if strings.Contains(err.Error(), "Duplicate column") {
@@ -40,7 +45,7 @@ func (m *AddPasswordToUsers) Up(db *sql.DB) error {
 }
 
 // Down - 回滾 migration
-func (m *AddPasswordToUsers) Down(db *sql.DB) error {
+func (m *AddPasswordToUsers) Down(db Executor, dialect Dialect) error {
 	query := `ALTER TABLE users DROP COLUMN password;`
 
 	_, err := db.Exec(query)
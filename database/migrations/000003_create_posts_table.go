@@ -1,7 +1,6 @@
 package migrations
 
 import (
-	"database/sql"
 	"fmt"
 )
 
@@ -20,26 +19,47 @@ func init() {
 }
 
 // Up - 執行 migration
-func (m *CreatePostsTable) Up(db *sql.DB) error {
-	query := `
-		CREATE TABLE IF NOT EXISTS posts (
-			id BIGINT AUTO_INCREMENT PRIMARY KEY,
-			title VARCHAR(255) NOT NULL,
-			content LONGTEXT NOT NULL,
-			description VARCHAR(500),
-			user_id BIGINT NOT NULL,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-			deleted_at TIMESTAMP NULL,
-			INDEX idx_user_id (user_id),
-			INDEX idx_deleted_at (deleted_at),
-			CONSTRAINT fk_posts_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
-		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
-	`
-
-	_, err := db.Exec(query)
+func (m *CreatePostsTable) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {`
+			CREATE TABLE IF NOT EXISTS posts (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				title VARCHAR(255) NOT NULL,
+				content LONGTEXT NOT NULL,
+				description VARCHAR(500),
+				user_id BIGINT NOT NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				deleted_at TIMESTAMP NULL,
+				INDEX idx_user_id (user_id),
+				INDEX idx_deleted_at (deleted_at),
+				CONSTRAINT fk_posts_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+		`},
+		Postgres: {
+			`CREATE TABLE IF NOT EXISTS posts (
+				id BIGSERIAL PRIMARY KEY,
+				title VARCHAR(255) NOT NULL,
+				content TEXT NOT NULL,
+				description VARCHAR(500),
+				user_id BIGINT NOT NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				deleted_at TIMESTAMPTZ NULL,
+				CONSTRAINT fk_posts_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_posts_user_id ON posts (user_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts (deleted_at)`,
+		},
+	}.For(dialect)
 	if err != nil {
-		return fmt.Errorf("建立 posts 表失敗: %v", err)
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("建立 posts 表失敗: %v", err)
+		}
 	}
 
 	fmt.Println("✓ 建立 posts 表成功")
@@ -47,7 +67,7 @@ func (m *CreatePostsTable) Up(db *sql.DB) error {
 }
 
 // Down - 回滾 migration
-func (m *CreatePostsTable) Down(db *sql.DB) error {
+func (m *CreatePostsTable) Down(db Executor, dialect Dialect) error {
 	query := `DROP TABLE IF EXISTS posts;`
 
 	_, err := db.Exec(query)
@@ -0,0 +1,84 @@
+package migrations
+
+import (
+	"fmt"
+)
+
+// CreateRefreshTokensTable - 建立 refresh_tokens 資料表
+type CreateRefreshTokensTable struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&CreateRefreshTokensTable{
+		BaseMigration: BaseMigration{
+			version:     "000004",
+			description: "create_refresh_tokens_table",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *CreateRefreshTokensTable) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {`
+			CREATE TABLE IF NOT EXISTS refresh_tokens (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				family_id VARCHAR(36) NOT NULL,
+				token_hash CHAR(64) NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				used_at TIMESTAMP NULL,
+				revoked_at TIMESTAMP NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				UNIQUE INDEX idx_refresh_tokens_token_hash (token_hash),
+				INDEX idx_refresh_tokens_user_id (user_id),
+				INDEX idx_refresh_tokens_family_id (family_id),
+				CONSTRAINT fk_refresh_tokens_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+		`},
+		Postgres: {
+			`CREATE TABLE IF NOT EXISTS refresh_tokens (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				family_id VARCHAR(36) NOT NULL,
+				token_hash CHAR(64) NOT NULL,
+				expires_at TIMESTAMPTZ NOT NULL,
+				used_at TIMESTAMPTZ NULL,
+				revoked_at TIMESTAMPTZ NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_refresh_tokens_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash)`,
+			`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id)`,
+		},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("建立 refresh_tokens 表失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ 建立 refresh_tokens 表成功")
+	return nil
+}
+
+// Down - 回滾 migration
+func (m *CreateRefreshTokensTable) Down(db Executor, dialect Dialect) error {
+	query := `DROP TABLE IF EXISTS refresh_tokens;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("刪除 refresh_tokens 表失敗: %v", err)
+	}
+
+	fmt.Println("✓ 刪除 refresh_tokens 表成功")
+	return nil
+}
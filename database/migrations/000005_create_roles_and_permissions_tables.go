@@ -0,0 +1,175 @@
+package migrations
+
+import (
+	"fmt"
+)
+
+// CreateRolesAndPermissionsTables - 建立角色權限相關資料表，並寫入預設角色
+//
+// 預設資料：
+//   - 權限：users.*、roles.manage
+//   - 角色：admin（擁有所有權限）、user（一般使用者）
+//   - 既有使用者全部指派 user 角色；不自動指派 admin（最早註冊的帳號不一定是管理員），
+//     第一位管理員以 seed --class=AdminSeeder 指定
+type CreateRolesAndPermissionsTables struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&CreateRolesAndPermissionsTables{
+		BaseMigration: BaseMigration{
+			version:     "000005",
+			description: "create_roles_and_permissions_tables",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *CreateRolesAndPermissionsTables) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {
+			`CREATE TABLE IF NOT EXISTS roles (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				name VARCHAR(50) NOT NULL,
+				description VARCHAR(255),
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				UNIQUE INDEX idx_roles_name (name)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+
+			`CREATE TABLE IF NOT EXISTS permissions (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				name VARCHAR(100) NOT NULL,
+				description VARCHAR(255),
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				UNIQUE INDEX idx_permissions_name (name)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+
+			`CREATE TABLE IF NOT EXISTS role_permissions (
+				role_id BIGINT NOT NULL,
+				permission_id BIGINT NOT NULL,
+				PRIMARY KEY (role_id, permission_id),
+				CONSTRAINT fk_role_permissions_roles FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+				CONSTRAINT fk_role_permissions_permissions FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+
+			`CREATE TABLE IF NOT EXISTS user_roles (
+				user_id BIGINT NOT NULL,
+				role_id BIGINT NOT NULL,
+				PRIMARY KEY (user_id, role_id),
+				CONSTRAINT fk_user_roles_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+				CONSTRAINT fk_user_roles_roles FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+
+			// 預設權限
+			`INSERT IGNORE INTO permissions (name, description) VALUES
+				('users.view', '檢視使用者'),
+				('users.create', '新增使用者'),
+				('users.update', '更新使用者'),
+				('users.delete', '刪除使用者'),
+				('roles.manage', '管理角色與權限')`,
+
+			// 預設角色
+			`INSERT IGNORE INTO roles (name, description) VALUES
+				('admin', '系統管理員'),
+				('user', '一般使用者')`,
+
+			// admin 擁有所有權限
+			`INSERT IGNORE INTO role_permissions (role_id, permission_id)
+				SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin'`,
+
+			// 既有使用者指派 user 角色
+			`INSERT IGNORE INTO user_roles (user_id, role_id)
+				SELECT u.id, r.id FROM users u CROSS JOIN roles r
+				WHERE r.name = 'user' AND u.deleted_at IS NULL`,
+		},
+		Postgres: {
+			`CREATE TABLE IF NOT EXISTS roles (
+				id BIGSERIAL PRIMARY KEY,
+				name VARCHAR(50) NOT NULL,
+				description VARCHAR(255),
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_name ON roles (name)`,
+
+			`CREATE TABLE IF NOT EXISTS permissions (
+				id BIGSERIAL PRIMARY KEY,
+				name VARCHAR(100) NOT NULL,
+				description VARCHAR(255),
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_name ON permissions (name)`,
+
+			`CREATE TABLE IF NOT EXISTS role_permissions (
+				role_id BIGINT NOT NULL,
+				permission_id BIGINT NOT NULL,
+				PRIMARY KEY (role_id, permission_id),
+				CONSTRAINT fk_role_permissions_roles FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+				CONSTRAINT fk_role_permissions_permissions FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+			)`,
+
+			`CREATE TABLE IF NOT EXISTS user_roles (
+				user_id BIGINT NOT NULL,
+				role_id BIGINT NOT NULL,
+				PRIMARY KEY (user_id, role_id),
+				CONSTRAINT fk_user_roles_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+				CONSTRAINT fk_user_roles_roles FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+			)`,
+
+			// 預設權限
+			`INSERT INTO permissions (name, description) VALUES
+				('users.view', '檢視使用者'),
+				('users.create', '新增使用者'),
+				('users.update', '更新使用者'),
+				('users.delete', '刪除使用者'),
+				('roles.manage', '管理角色與權限')
+				ON CONFLICT DO NOTHING`,
+
+			// 預設角色
+			`INSERT INTO roles (name, description) VALUES
+				('admin', '系統管理員'),
+				('user', '一般使用者')
+				ON CONFLICT DO NOTHING`,
+
+			// admin 擁有所有權限
+			`INSERT INTO role_permissions (role_id, permission_id)
+				SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin'
+				ON CONFLICT DO NOTHING`,
+
+			// 既有使用者指派 user 角色
+			`INSERT INTO user_roles (user_id, role_id)
+				SELECT u.id, r.id FROM users u CROSS JOIN roles r
+				WHERE r.name = 'user' AND u.deleted_at IS NULL
+				ON CONFLICT DO NOTHING`,
+		},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("建立角色權限資料表失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ 建立 roles、permissions、role_permissions、user_roles 表成功")
+	return nil
+}
+
+// Down - 回滾 migration
+func (m *CreateRolesAndPermissionsTables) Down(db Executor, dialect Dialect) error {
+	tables := []string{"user_roles", "role_permissions", "permissions", "roles"}
+
+	for _, table := range tables {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			return fmt.Errorf("刪除 %s 表失敗: %v", table, err)
+		}
+	}
+
+	fmt.Println("✓ 刪除角色權限資料表成功")
+	return nil
+}
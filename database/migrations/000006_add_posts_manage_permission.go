@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"fmt"
+)
+
+// AddPostsManagePermission - 新增 posts.manage 權限並指派給 admin 角色
+// 具備此權限者可以修改、刪除其他人的文章（見 policies.PostPolicy）
+type AddPostsManagePermission struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&AddPostsManagePermission{
+		BaseMigration: BaseMigration{
+			version:     "000006",
+			description: "add_posts_manage_permission",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *AddPostsManagePermission) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {
+			`INSERT IGNORE INTO permissions (name, description) VALUES ('posts.manage', '管理所有文章')`,
+
+			`INSERT IGNORE INTO role_permissions (role_id, permission_id)
+				SELECT r.id, p.id FROM roles r CROSS JOIN permissions p
+				WHERE r.name = 'admin' AND p.name = 'posts.manage'`,
+		},
+		Postgres: {
+			`INSERT INTO permissions (name, description) VALUES ('posts.manage', '管理所有文章')
+				ON CONFLICT DO NOTHING`,
+
+			`INSERT INTO role_permissions (role_id, permission_id)
+				SELECT r.id, p.id FROM roles r CROSS JOIN permissions p
+				WHERE r.name = 'admin' AND p.name = 'posts.manage'
+				ON CONFLICT DO NOTHING`,
+		},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("新增 posts.manage 權限失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ 新增 posts.manage 權限成功")
+	return nil
+}
+
+// Down - 回滾 migration（role_permissions 由外鍵 ON DELETE CASCADE 清除）
+func (m *AddPostsManagePermission) Down(db Executor, dialect Dialect) error {
+	if _, err := db.Exec("DELETE FROM permissions WHERE name = 'posts.manage'"); err != nil {
+		return fmt.Errorf("刪除 posts.manage 權限失敗: %v", err)
+	}
+
+	fmt.Println("✓ 刪除 posts.manage 權限成功")
+	return nil
+}
@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"fmt"
+)
+
+// CreatePasswordResetTokensTable - 建立 password_reset_tokens 資料表
+type CreatePasswordResetTokensTable struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&CreatePasswordResetTokensTable{
+		BaseMigration: BaseMigration{
+			version:     "000007",
+			description: "create_password_reset_tokens_table",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *CreatePasswordResetTokensTable) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {`
+			CREATE TABLE IF NOT EXISTS password_reset_tokens (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				token_hash CHAR(64) NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				used_at TIMESTAMP NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE INDEX idx_password_reset_tokens_token_hash (token_hash),
+				INDEX idx_password_reset_tokens_user_id (user_id),
+				CONSTRAINT fk_password_reset_tokens_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+		`},
+		Postgres: {
+			`CREATE TABLE IF NOT EXISTS password_reset_tokens (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				token_hash CHAR(64) NOT NULL,
+				expires_at TIMESTAMPTZ NOT NULL,
+				used_at TIMESTAMPTZ NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_password_reset_tokens_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_password_reset_tokens_token_hash ON password_reset_tokens (token_hash)`,
+			`CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens (user_id)`,
+		},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("建立 password_reset_tokens 表失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ 建立 password_reset_tokens 表成功")
+	return nil
+}
+
+// Down - 回滾 migration
+func (m *CreatePasswordResetTokensTable) Down(db Executor, dialect Dialect) error {
+	query := `DROP TABLE IF EXISTS password_reset_tokens;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("刪除 password_reset_tokens 表失敗: %v", err)
+	}
+
+	fmt.Println("✓ 刪除 password_reset_tokens 表成功")
+	return nil
+}
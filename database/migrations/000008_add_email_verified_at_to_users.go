@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AddEmailVerifiedAtToUsers - 新增 email_verified_at 欄位到 users 表
+// 既有使用者維持 NULL（未驗證），可透過 POST /api/email/resend 重新寄送驗證信
+type AddEmailVerifiedAtToUsers struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&AddEmailVerifiedAtToUsers{
+		BaseMigration: BaseMigration{
+			version:     "000008",
+			description: "add_email_verified_at_to_users",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *AddEmailVerifiedAtToUsers) Up(db Executor, dialect Dialect) error {
+	query, err := SQL{
+		MySQL:    `ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP NULL AFTER email;`,
+		Postgres: `ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ NULL`,
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(query)
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate column") {
+			fmt.Println("→ email_verified_at 欄位已存在，跳過")
+			return nil
+		}
+		return fmt.Errorf("新增 email_verified_at 欄位失敗: %v", err)
+	}
+
+	fmt.Println("✓ 新增 email_verified_at 欄位成功")
+	return nil
+}
+
+// Down - 回滾 migration
+func (m *AddEmailVerifiedAtToUsers) Down(db Executor, dialect Dialect) error {
+	query := `ALTER TABLE users DROP COLUMN email_verified_at;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("刪除 email_verified_at 欄位失敗: %v", err)
+	}
+
+	fmt.Println("✓ 刪除 email_verified_at 欄位成功")
+	return nil
+}
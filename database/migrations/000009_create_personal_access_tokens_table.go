@@ -0,0 +1,82 @@
+package migrations
+
+import (
+	"fmt"
+)
+
+// CreatePersonalAccessTokensTable - 建立 personal_access_tokens 資料表
+type CreatePersonalAccessTokensTable struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&CreatePersonalAccessTokensTable{
+		BaseMigration: BaseMigration{
+			version:     "000009",
+			description: "create_personal_access_tokens_table",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *CreatePersonalAccessTokensTable) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {`
+			CREATE TABLE IF NOT EXISTS personal_access_tokens (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				name VARCHAR(100) NOT NULL,
+				token_hash CHAR(64) NOT NULL,
+				scopes TEXT,
+				last_used_at TIMESTAMP NULL,
+				expires_at TIMESTAMP NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				UNIQUE INDEX idx_personal_access_tokens_token_hash (token_hash),
+				INDEX idx_personal_access_tokens_user_id (user_id),
+				CONSTRAINT fk_personal_access_tokens_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+		`},
+		Postgres: {
+			`CREATE TABLE IF NOT EXISTS personal_access_tokens (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				name VARCHAR(100) NOT NULL,
+				token_hash CHAR(64) NOT NULL,
+				scopes TEXT,
+				last_used_at TIMESTAMPTZ NULL,
+				expires_at TIMESTAMPTZ NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_personal_access_tokens_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_personal_access_tokens_token_hash ON personal_access_tokens (token_hash)`,
+			`CREATE INDEX IF NOT EXISTS idx_personal_access_tokens_user_id ON personal_access_tokens (user_id)`,
+		},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("建立 personal_access_tokens 表失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ 建立 personal_access_tokens 表成功")
+	return nil
+}
+
+// Down - 回滾 migration
+func (m *CreatePersonalAccessTokensTable) Down(db Executor, dialect Dialect) error {
+	query := `DROP TABLE IF EXISTS personal_access_tokens;`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("刪除 personal_access_tokens 表失敗: %v", err)
+	}
+
+	fmt.Println("✓ 刪除 personal_access_tokens 表成功")
+	return nil
+}
@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AddTwoFactorColumnsToUsers - 新增兩步驟驗證欄位到 users 表
+type AddTwoFactorColumnsToUsers struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&AddTwoFactorColumnsToUsers{
+		BaseMigration: BaseMigration{
+			version:     "000010",
+			description: "add_two_factor_columns_to_users",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *AddTwoFactorColumnsToUsers) Up(db Executor, dialect Dialect) error {
+	query, err := SQL{
+		MySQL: `
+			ALTER TABLE users
+				ADD COLUMN two_factor_secret TEXT NULL AFTER password,
+				ADD COLUMN two_factor_recovery_codes TEXT NULL AFTER two_factor_secret,
+				ADD COLUMN two_factor_last_step BIGINT NOT NULL DEFAULT 0 AFTER two_factor_recovery_codes,
+				ADD COLUMN two_factor_confirmed_at TIMESTAMP NULL AFTER two_factor_last_step;
+		`,
+		Postgres: `
+			ALTER TABLE users
+				ADD COLUMN IF NOT EXISTS two_factor_secret TEXT NULL,
+				ADD COLUMN IF NOT EXISTS two_factor_recovery_codes TEXT NULL,
+				ADD COLUMN IF NOT EXISTS two_factor_last_step BIGINT NOT NULL DEFAULT 0,
+				ADD COLUMN IF NOT EXISTS two_factor_confirmed_at TIMESTAMPTZ NULL
+		`,
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(query)
+	if err != nil {
+		if strings.Contains(err.Error(), "Duplicate column") {
+			fmt.Println("→ 兩步驟驗證欄位已存在，跳過")
+			return nil
+		}
+		return fmt.Errorf("新增兩步驟驗證欄位失敗: %v", err)
+	}
+
+	fmt.Println("✓ 新增兩步驟驗證欄位成功")
+	return nil
+}
+
+// Down - 回滾 migration
+func (m *AddTwoFactorColumnsToUsers) Down(db Executor, dialect Dialect) error {
+	query := `
+		ALTER TABLE users
+			DROP COLUMN two_factor_secret,
+			DROP COLUMN two_factor_recovery_codes,
+			DROP COLUMN two_factor_last_step,
+			DROP COLUMN two_factor_confirmed_at;
+	`
+
+	_, err := db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("刪除兩步驟驗證欄位失敗: %v", err)
+	}
+
+	fmt.Println("✓ 刪除兩步驟驗證欄位成功")
+	return nil
+}
@@ -0,0 +1,111 @@
+package migrations
+
+import (
+	"fmt"
+)
+
+// CreateSessionsAndLoginHistoriesTables - 建立 sessions（登入裝置）與 login_histories（登入紀錄）資料表
+type CreateSessionsAndLoginHistoriesTables struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&CreateSessionsAndLoginHistoriesTables{
+		BaseMigration: BaseMigration{
+			version:     "000011",
+			description: "create_sessions_and_login_histories_tables",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *CreateSessionsAndLoginHistoriesTables) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {
+			`CREATE TABLE IF NOT EXISTS sessions (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				family_id VARCHAR(36) NOT NULL,
+				ip_address VARCHAR(45),
+				user_agent VARCHAR(255),
+				last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				revoked_at TIMESTAMP NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				UNIQUE INDEX idx_sessions_family_id (family_id),
+				INDEX idx_sessions_user_id (user_id),
+				CONSTRAINT fk_sessions_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+
+			`CREATE TABLE IF NOT EXISTS login_histories (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				session_id BIGINT NULL,
+				successful BOOLEAN NOT NULL,
+				ip_address VARCHAR(45),
+				user_agent VARCHAR(255),
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				INDEX idx_login_histories_user_id (user_id),
+				CONSTRAINT fk_login_histories_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+				CONSTRAINT fk_login_histories_sessions FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE SET NULL
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci`,
+		},
+		Postgres: {
+			`CREATE TABLE IF NOT EXISTS sessions (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				family_id VARCHAR(36) NOT NULL,
+				ip_address VARCHAR(45),
+				user_agent VARCHAR(255),
+				last_seen_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				expires_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				revoked_at TIMESTAMPTZ NULL,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_sessions_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions (family_id)`,
+			`CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id)`,
+
+			`CREATE TABLE IF NOT EXISTS login_histories (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				session_id BIGINT NULL,
+				successful BOOLEAN NOT NULL,
+				ip_address VARCHAR(45),
+				user_agent VARCHAR(255),
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_login_histories_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+				CONSTRAINT fk_login_histories_sessions FOREIGN KEY (session_id) REFERENCES sessions(id) ON DELETE SET NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_login_histories_user_id ON login_histories (user_id)`,
+		},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("建立 sessions、login_histories 表失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ 建立 sessions、login_histories 表成功")
+	return nil
+}
+
+// Down - 回滾 migration
+func (m *CreateSessionsAndLoginHistoriesTables) Down(db Executor, dialect Dialect) error {
+	tables := []string{"login_histories", "sessions"}
+
+	for _, table := range tables {
+		if _, err := db.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			return fmt.Errorf("刪除 %s 表失敗: %v", table, err)
+		}
+	}
+
+	fmt.Println("✓ 刪除 sessions、login_histories 表成功")
+	return nil
+}
@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"fmt"
+)
+
+// CreateIdentitiesTable - 建立 identities（OpenID Connect 外部身分）資料表
+type CreateIdentitiesTable struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&CreateIdentitiesTable{
+		BaseMigration: BaseMigration{
+			version:     "000012",
+			description: "create_identities_table",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *CreateIdentitiesTable) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {`
+			CREATE TABLE IF NOT EXISTS identities (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				provider VARCHAR(50) NOT NULL,
+				subject VARCHAR(255) NOT NULL,
+				email VARCHAR(255),
+				last_login_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+				UNIQUE INDEX idx_identities_provider_subject (provider, subject),
+				INDEX idx_identities_user_id (user_id),
+				CONSTRAINT fk_identities_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+		`},
+		Postgres: {
+			`CREATE TABLE IF NOT EXISTS identities (
+				id BIGSERIAL PRIMARY KEY,
+				user_id BIGINT NOT NULL,
+				provider VARCHAR(50) NOT NULL,
+				subject VARCHAR(255) NOT NULL,
+				email VARCHAR(255),
+				last_login_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_identities_users FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			)`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_identities_provider_subject ON identities (provider, subject)`,
+			`CREATE INDEX IF NOT EXISTS idx_identities_user_id ON identities (user_id)`,
+		},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("建立 identities 表失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ 建立 identities 表成功")
+	return nil
+}
+
+// Down - 回滾 migration
+func (m *CreateIdentitiesTable) Down(db Executor, dialect Dialect) error {
+	if _, err := db.Exec("DROP TABLE IF EXISTS identities"); err != nil {
+		return fmt.Errorf("刪除 identities 表失敗: %v", err)
+	}
+
+	fmt.Println("✓ 刪除 identities 表成功")
+	return nil
+}
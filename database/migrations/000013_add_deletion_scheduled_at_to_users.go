@@ -0,0 +1,75 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AddDeletionScheduledAtToUsers - 新增刪除帳號排程欄位到 users 表
+type AddDeletionScheduledAtToUsers struct {
+	BaseMigration
+}
+
+func init() {
+	Register(&AddDeletionScheduledAtToUsers{
+		BaseMigration: BaseMigration{
+			version:     "000013",
+			description: "add_deletion_scheduled_at_to_users",
+		},
+	})
+}
+
+// Up - 執行 migration
+func (m *AddDeletionScheduledAtToUsers) Up(db Executor, dialect Dialect) error {
+	statements, err := Statements{
+		MySQL: {`
+			ALTER TABLE users
+				ADD COLUMN deletion_scheduled_at TIMESTAMP NULL AFTER two_factor_confirmed_at,
+				ADD INDEX idx_users_deletion_scheduled_at (deletion_scheduled_at);
+		`},
+		Postgres: {
+			`ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ NULL`,
+			`CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users (deletion_scheduled_at)`,
+		},
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range statements {
+		if _, err := db.Exec(query); err != nil {
+			if strings.Contains(err.Error(), "Duplicate column") {
+				fmt.Println("→ deletion_scheduled_at 欄位已存在，跳過")
+				return nil
+			}
+			return fmt.Errorf("新增 deletion_scheduled_at 欄位失敗: %v", err)
+		}
+	}
+
+	fmt.Println("✓ 新增 deletion_scheduled_at 欄位成功")
+	return nil
+}
+
+// Down - 回滾 migration
+func (m *AddDeletionScheduledAtToUsers) Down(db Executor, dialect Dialect) error {
+	// Postgres 刪除欄位時會一併刪除索引
+	query, err := SQL{
+		MySQL: `
+			ALTER TABLE users
+				DROP INDEX idx_users_deletion_scheduled_at,
+				DROP COLUMN deletion_scheduled_at;
+		`,
+		Postgres: `ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_at`,
+	}.For(dialect)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(query)
+	if err != nil {
+		return fmt.Errorf("刪除 deletion_scheduled_at 欄位失敗: %v", err)
+	}
+
+	fmt.Println("✓ 刪除 deletion_scheduled_at 欄位成功")
+	return nil
+}
@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect - 資料庫類型（對應 DB_TYPE）
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+)
+
+// ParseDialect - 將 DB_TYPE 轉成 Dialect
+func ParseDialect(name string) (Dialect, error) {
+	switch Dialect(strings.ToLower(name)) {
+	case MySQL:
+		return MySQL, nil
+	case Postgres:
+		return Postgres, nil
+	default:
+		return "", fmt.Errorf("不支援的資料庫類型: %s", name)
+	}
+}
+
+// TransactionalDDL - 是否支援在交易中執行 DDL
+// MySQL 的 DDL 會隱式提交交易，失敗時無法回滾，因此只有 Postgres 會包在交易內
+func (d Dialect) TransactionalDDL() bool {
+	return d == Postgres
+}
+
+// Quote - 以資料庫的識別字引號包住資料表或欄位名稱
+func (d Dialect) Quote(identifier string) string {
+	if d == Postgres {
+		return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+	}
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+// Rebind - 將 ? 佔位符轉成資料庫使用的格式（Postgres 為 $1、$2…），單引號字串內的 ? 不轉換
+func (d Dialect) Rebind(query string) string {
+	if d != Postgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == '?' && !quoted:
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SQL - 各資料庫的 SQL（單一語句）
+type SQL map[Dialect]string
+
+// For - 取得指定資料庫的 SQL，沒有提供時回傳錯誤
+func (q SQL) For(d Dialect) (string, error) {
+	query, ok := q[d]
+	if !ok {
+		return "", fmt.Errorf("migration 不支援 %s", d)
+	}
+	return query, nil
+}
+
+// Statements - 各資料庫依序執行的多個語句
+// Postgres 的索引需要另外以 CREATE INDEX 建立，語句數量可能與 MySQL 不同
+type Statements map[Dialect][]string
+
+// For - 取得指定資料庫的語句，沒有提供時回傳錯誤
+func (s Statements) For(d Dialect) ([]string, error) {
+	statements, ok := s[d]
+	if !ok {
+		return nil, fmt.Errorf("migration 不支援 %s", d)
+	}
+	return statements, nil
+}
@@ -5,13 +5,22 @@ import (
 )
 
 // Migration 介面定義
+// dialect 為目前連線的資料庫類型，migration 依此選擇對應的 SQL（見 SQL、Statements）
 type Migration interface {
-	Up(db *sql.DB) error
-	Down(db *sql.DB) error
+	Up(db Executor, dialect Dialect) error
+	Down(db Executor, dialect Dialect) error
 	Version() string
 	Description() string
 }
 
+// Executor - migration 執行 SQL 的介面
+// 支援交易式 DDL 的資料庫（Postgres）會傳入 *sql.Tx，其餘傳入 *sql.DB
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 // BaseMigration 基礎結構
 type BaseMigration struct {
 	version     string
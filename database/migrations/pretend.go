@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"database/sql"
+	"database/sql/driver"
+)
+
+// PretendExecutor - 記錄 migration 要執行的語句而不實際執行（migrate --pretend）
+// Exec 只會記錄；Query、QueryRow 交給 reader 執行，讓依現有資料決定行為的 migration 也能預覽
+// reader 應使用唯讀交易，確保預覽時不會變更資料庫
+type PretendExecutor struct {
+	reader  Executor
+	queries []PretendedQuery
+}
+
+// PretendedQuery - 一個被記錄的語句與參數
+type PretendedQuery struct {
+	Query string
+	Args  []interface{}
+}
+
+// NewPretendExecutor - 建立 PretendExecutor，只會呼叫 Exec 的 migration 可傳入 nil
+func NewPretendExecutor(reader Executor) *PretendExecutor {
+	return &PretendExecutor{reader: reader}
+}
+
+// Exec - 記錄語句，回傳影響 0 筆
+func (e *PretendExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	e.queries = append(e.queries, PretendedQuery{Query: query, Args: args})
+	return driver.RowsAffected(0), nil
+}
+
+// Query - 交給 reader 執行
+func (e *PretendExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return e.reader.Query(query, args...)
+}
+
+// QueryRow - 交給 reader 執行
+func (e *PretendExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
+	return e.reader.QueryRow(query, args...)
+}
+
+// Queries - 依執行順序回傳記錄的語句
+func (e *PretendExecutor) Queries() []PretendedQuery {
+	return e.queries
+}
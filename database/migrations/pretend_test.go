@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPretendExecutor_AllMigrations 測試所有 migration 在 pretend 模式都能產生語句，且不需要讀取資料庫
+func TestPretendExecutor_AllMigrations(t *testing.T) {
+	for _, dialect := range []Dialect{MySQL, Postgres} {
+		for _, m := range All() {
+			up := NewPretendExecutor(nil)
+			if err := m.Up(up, dialect); err != nil {
+				t.Errorf("%s Up(%s) 發生錯誤: %v", m.Version(), dialect, err)
+			}
+			if len(up.Queries()) == 0 {
+				t.Errorf("%s Up(%s) 應該記錄語句", m.Version(), dialect)
+			}
+
+			down := NewPretendExecutor(nil)
+			if err := m.Down(down, dialect); err != nil {
+				t.Errorf("%s Down(%s) 發生錯誤: %v", m.Version(), dialect, err)
+			}
+			if len(down.Queries()) == 0 {
+				t.Errorf("%s Down(%s) 應該記錄語句", m.Version(), dialect)
+			}
+		}
+	}
+}
+
+// TestCreateRolesAndPermissionsTables_NoAdminAssignment 測試 000005 不會把既有使用者指派為 admin
+func TestCreateRolesAndPermissionsTables_NoAdminAssignment(t *testing.T) {
+	m, ok := Get("000005")
+	if !ok {
+		t.Fatal("找不到 migration 000005")
+	}
+
+	for _, dialect := range []Dialect{MySQL, Postgres} {
+		exec := NewPretendExecutor(nil)
+		if err := m.Up(exec, dialect); err != nil {
+			t.Fatalf("Up(%s) 發生錯誤: %v", dialect, err)
+		}
+		for _, q := range exec.Queries() {
+			if strings.Contains(q.Query, "INTO user_roles") && strings.Contains(q.Query, "'admin'") {
+				t.Errorf("%s 不應指派 admin 角色: %s", dialect, q.Query)
+			}
+		}
+	}
+}
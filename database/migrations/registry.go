@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"fmt"
 	"sort"
 	"sync"
 )
@@ -10,10 +11,13 @@ var (
 	mu       sync.RWMutex
 )
 
-// Register 註冊 migration
+// Register 註冊 migration，版本號重複時 panic（Go 與 SQL migrations 共用版本號）
 func Register(m Migration) {
 	mu.Lock()
 	defer mu.Unlock()
+	if existing, ok := registry[m.Version()]; ok {
+		panic(fmt.Sprintf("migration 版本號重複: %s（%s、%s）", m.Version(), existing.Description(), m.Description()))
+	}
 	registry[m.Version()] = m
 }
 
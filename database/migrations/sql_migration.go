@@ -0,0 +1,251 @@
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// sqlFiles - 純 SQL 的 migrations（database/migrations/sql 目錄）
+//
+//go:embed sql
+var sqlFiles embed.FS
+
+// sqlFilePattern - NNNNNN_name.up.sql、NNNNNN_name.down.sql，可加上資料庫類型，例如 NNNNNN_name.up.postgres.sql
+var sqlFilePattern = regexp.MustCompile(`^(\d{6})_([a-z0-9_]+)\.(up|down)(?:\.([a-z]+))?\.sql$`)
+
+func init() {
+	sub, err := fs.Sub(sqlFiles, "sql")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded, err := LoadSQLMigrations(sub)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, m := range loaded {
+		Register(m)
+	}
+}
+
+// SQLMigration - 由 .up.sql / .down.sql 檔案組成的 migration
+// 沒有指定資料庫類型的檔案為預設，有 .mysql.sql / .postgres.sql 時優先使用
+type SQLMigration struct {
+	BaseMigration
+	up   map[Dialect]sqlFile // key 為空字串時是預設檔案
+	down map[Dialect]sqlFile
+}
+
+// sqlFile - 單一 SQL 檔案
+type sqlFile struct {
+	name    string
+	content string
+}
+
+// LoadSQLMigrations - 讀取目錄中的 SQL migrations（不會註冊）
+// 檔名不符合格式的 .sql 檔案、缺少 .up.sql 或不支援的資料庫類型都會回傳錯誤
+func LoadSQLMigrations(fsys fs.FS) ([]*SQLMigration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]*SQLMigration)
+	var loaded []*SQLMigration
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+
+		match := sqlFilePattern.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("SQL migration 檔名格式錯誤: %s（應為 000001_name.up.sql）", name)
+		}
+		version, description, direction, dialectName := match[1], match[2], match[3], match[4]
+
+		var dialect Dialect
+		if dialectName != "" {
+			if dialect, err = ParseDialect(dialectName); err != nil {
+				return nil, fmt.Errorf("SQL migration %s: %v", name, err)
+			}
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &SQLMigration{
+				BaseMigration: BaseMigration{version: version, description: description},
+				up:            make(map[Dialect]sqlFile),
+				down:          make(map[Dialect]sqlFile),
+			}
+			byVersion[version] = m
+			loaded = append(loaded, m)
+		} else if m.description != description {
+			return nil, fmt.Errorf("SQL migration 版本 %s 的名稱不一致: %s、%s", version, m.description, description)
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		files := m.up
+		if direction == "down" {
+			files = m.down
+		}
+		files[dialect] = sqlFile{name: path.Base(name), content: string(content)}
+	}
+
+	for _, m := range loaded {
+		if len(m.up) == 0 {
+			return nil, fmt.Errorf("SQL migration %s_%s 缺少 .up.sql", m.version, m.description)
+		}
+	}
+
+	return loaded, nil
+}
+
+// Up - 執行 .up.sql
+func (m *SQLMigration) Up(db Executor, dialect Dialect) error {
+	return m.run(db, dialect, m.up, "up")
+}
+
+// Down - 執行 .down.sql
+func (m *SQLMigration) Down(db Executor, dialect Dialect) error {
+	return m.run(db, dialect, m.down, "down")
+}
+
+func (m *SQLMigration) run(db Executor, dialect Dialect, files map[Dialect]sqlFile, direction string) error {
+	file, ok := files[dialect]
+	if !ok {
+		if file, ok = files[""]; !ok {
+			return fmt.Errorf("migration %s_%s 沒有 %s 可用的 .%s.sql", m.version, m.description, dialect, direction)
+		}
+	}
+
+	for i, statement := range SplitStatements(file.content, dialect) {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("%s 第 %d 個語句失敗: %v", file.name, i+1, err)
+		}
+	}
+
+	fmt.Printf("✓ 執行 %s 成功\n", file.name)
+	return nil
+}
+
+// SplitStatements - 將 SQL 依分號拆成多個語句，不需要在 DSN 開啟 multiStatements
+// 字串、識別字、註解與 Postgres 的 $tag$ 字串中的分號不會拆開，只有註解的語句會略過
+func SplitStatements(script string, dialect Dialect) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		hasCode    bool // 目前的語句是否有註解以外的內容
+	)
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); hasCode && statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+		hasCode = false
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+
+		switch {
+		// -- 單行註解
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			end := strings.IndexByte(script[i:], '\n')
+			if end < 0 {
+				end = len(script) - i
+			}
+			current.WriteString(script[i : i+end])
+			i += end
+
+		// /* 區塊註解 */
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				end = len(script) - i
+			} else {
+				end += 4
+			}
+			current.WriteString(script[i : i+end])
+			i += end
+
+		// 字串與識別字
+		case c == '\'' || c == '"' || (c == '`' && dialect == MySQL):
+			end := quotedEnd(script, i, dialect)
+			current.WriteString(script[i:end])
+			hasCode = true
+			i = end
+
+		// Postgres 的 $$ 或 $tag$ 字串（函式內容常用）
+		case c == '$' && dialect == Postgres:
+			if tag := dollarTag(script[i:]); tag != "" {
+				end := strings.Index(script[i+len(tag):], tag)
+				if end < 0 {
+					end = len(script) - i
+				} else {
+					end += 2 * len(tag)
+				}
+				current.WriteString(script[i : i+end])
+				hasCode = true
+				i += end
+				continue
+			}
+			current.WriteByte(c)
+			hasCode = true
+			i++
+
+		case c == ';':
+			flush()
+			i++
+
+		default:
+			current.WriteByte(c)
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				hasCode = true
+			}
+			i++
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// quotedEnd - 從 start 的引號開始，回傳結束引號之後的位置
+// 連續兩個引號視為跳脫；MySQL 的字串另外支援反斜線跳脫
+func quotedEnd(script string, start int, dialect Dialect) int {
+	quote := script[start]
+	for i := start + 1; i < len(script); i++ {
+		switch script[i] {
+		case '\\':
+			if dialect == MySQL && quote != '`' {
+				i++
+			}
+		case quote:
+			if i+1 < len(script) && script[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(script)
+}
+
+// dollarTagPattern - $$ 或 $tag$
+var dollarTagPattern = regexp.MustCompile(`^\$[A-Za-z_][A-Za-z0-9_]*\$|^\$\$`)
+
+func dollarTag(s string) string {
+	return dollarTagPattern.FindString(s)
+}
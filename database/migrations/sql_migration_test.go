@@ -0,0 +1,141 @@
+package migrations
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// TestSplitStatements 測試依分號拆分語句，字串、註解與 $$ 字串中的分號不拆開
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		script  string
+		want    []string
+	}{
+		{
+			name:    "多個語句",
+			dialect: MySQL,
+			script:  "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:    []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:    "最後一個語句沒有分號",
+			dialect: MySQL,
+			script:  "SELECT 1;\nSELECT 2",
+			want:    []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name:    "字串中的分號",
+			dialect: MySQL,
+			script:  "INSERT INTO t VALUES ('a;b', 'it''s; ok', 'c\\';d');",
+			want:    []string{"INSERT INTO t VALUES ('a;b', 'it''s; ok', 'c\\';d')"},
+		},
+		{
+			name:    "識別字中的分號",
+			dialect: MySQL,
+			script:  "SELECT `a;b`, \"c;d\" FROM t;",
+			want:    []string{"SELECT `a;b`, \"c;d\" FROM t"},
+		},
+		{
+			name:    "註解中的分號，只有註解的語句略過",
+			dialect: MySQL,
+			script:  "-- 建立資料表; 說明\nCREATE TABLE a (id INT); /* 結尾; 註解 */\n-- 最後的註解;\n",
+			want:    []string{"-- 建立資料表; 說明\nCREATE TABLE a (id INT)"},
+		},
+		{
+			name:    "Postgres 的 $$ 字串",
+			dialect: Postgres,
+			script:  "CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN NEW.a := 1; RETURN NEW; END; $$ LANGUAGE plpgsql;\nSELECT 1;",
+			want: []string{
+				"CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN NEW.a := 1; RETURN NEW; END; $$ LANGUAGE plpgsql",
+				"SELECT 1",
+			},
+		},
+		{
+			name:    "Postgres 的 $tag$ 字串與佔位符",
+			dialect: Postgres,
+			script:  "DO $body$ BEGIN PERFORM 1; END $body$;\nSELECT $1;",
+			want:    []string{"DO $body$ BEGIN PERFORM 1; END $body$", "SELECT $1"},
+		},
+		{
+			name:    "Postgres 的反斜線不是跳脫字元",
+			dialect: Postgres,
+			script:  "SELECT 'a\\'; SELECT 2;",
+			want:    []string{"SELECT 'a\\'", "SELECT 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.script, tt.dialect); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadSQLMigrations 測試讀取 SQL migrations 與依資料庫類型選擇檔案
+func TestLoadSQLMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000020_create_tags.up.sql":          {Data: []byte("CREATE TABLE tags (id BIGINT AUTO_INCREMENT PRIMARY KEY);")},
+		"000020_create_tags.up.postgres.sql": {Data: []byte("CREATE TABLE tags (id BIGSERIAL PRIMARY KEY);")},
+		"000020_create_tags.down.sql":        {Data: []byte("DROP TABLE tags;")},
+		"README.md":                          {Data: []byte("說明文件不是 migration")},
+	}
+
+	loaded, err := LoadSQLMigrations(fsys)
+	if err != nil {
+		t.Fatalf("LoadSQLMigrations() 發生錯誤: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Version() != "000020" || loaded[0].Description() != "create_tags" {
+		t.Fatalf("應讀取 1 個 migration，got %+v", loaded)
+	}
+
+	m := loaded[0]
+	for dialect, want := range map[Dialect]string{
+		MySQL:    "CREATE TABLE tags (id BIGINT AUTO_INCREMENT PRIMARY KEY)",
+		Postgres: "CREATE TABLE tags (id BIGSERIAL PRIMARY KEY)",
+	} {
+		exec := NewPretendExecutor(nil)
+		if err := m.Up(exec, dialect); err != nil {
+			t.Fatalf("Up(%s) 發生錯誤: %v", dialect, err)
+		}
+		if got := pretendedQueries(exec); !reflect.DeepEqual(got, []string{want}) {
+			t.Errorf("Up(%s) 執行 %q，want %q", dialect, got, want)
+		}
+	}
+
+	exec := NewPretendExecutor(nil)
+	if err := m.Down(exec, Postgres); err != nil || !reflect.DeepEqual(pretendedQueries(exec), []string{"DROP TABLE tags"}) {
+		t.Errorf("Down 應使用預設的 .down.sql，got %q, %v", pretendedQueries(exec), err)
+	}
+}
+
+// TestLoadSQLMigrations_Invalid 測試檔名錯誤、缺少 .up.sql 與不支援的資料庫類型
+func TestLoadSQLMigrations_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"檔名格式錯誤":   {"create_tags.up.sql": {Data: []byte("SELECT 1")}},
+		"缺少 up":    {"000020_create_tags.down.sql": {Data: []byte("SELECT 1")}},
+		"不支援的資料庫":  {"000020_create_tags.up.oracle.sql": {Data: []byte("SELECT 1")}},
+		"同版本名稱不一致": {"000020_a.up.sql": {Data: []byte("SELECT 1")}, "000020_b.up.sql": {Data: []byte("SELECT 1")}},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadSQLMigrations(fsys); err == nil {
+				t.Error("應回傳錯誤")
+			}
+		})
+	}
+}
+
+// pretendedQueries - 取出 PretendExecutor 記錄的語句
+func pretendedQueries(exec *PretendExecutor) []string {
+	var queries []string
+	for _, q := range exec.Queries() {
+		queries = append(queries, q.Query)
+	}
+	return queries
+}
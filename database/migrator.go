@@ -1,191 +1,777 @@
 package database
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	"my-api/bootstrap"
 	"my-api/config"
 	"my-api/database/migrations"
 )
 
+// MigrateOptions - 執行 migration 的選項
+type MigrateOptions struct {
+	// To - 只執行到指定版本（含），空字串表示全部執行
+	To string
+	// Pretend - 只輸出會執行的 SQL，不變更資料庫
+	Pretend bool
+	// Strict - 已執行的 migration 內容被修改（checksum 不符）時中止，未設定時依 DB_MIGRATION_STRICT_CHECKSUM
+	Strict bool
+}
+
+// RollbackOptions - 回滾 migration 的選項
+type RollbackOptions struct {
+	// Step - 回滾最近執行的 N 個 migration，0 表示回滾最後一個批次
+	Step int
+	// Pretend - 只輸出會執行的 SQL，不變更資料庫
+	Pretend bool
+}
+
 // RunMigrations 執行所有待執行的 migrations
 func RunMigrations() error {
-	db, err := getDBConnection()
+	return Migrate(MigrateOptions{})
+}
+
+// Migrate 執行待執行的 migrations，同一次執行的 migrations 記錄為同一個批次
+func Migrate(opts MigrateOptions) error {
+	if opts.Pretend {
+		return withPretendMigrator(func(m *migrator) error {
+			return m.migrate(opts)
+		})
+	}
+	return withMigrator(func(m *migrator) error {
+		return m.migrate(opts)
+	})
+}
+
+// RollbackMigration 回滾最後一個批次
+func RollbackMigration() error {
+	return Rollback(RollbackOptions{})
+}
+
+// Rollback 回滾最後一個批次，或指定 Step 時回滾最近執行的 N 個 migration
+func Rollback(opts RollbackOptions) error {
+	if opts.Pretend {
+		return withPretendMigrator(func(m *migrator) error {
+			return m.rollback(opts)
+		})
+	}
+	return withMigrator(func(m *migrator) error {
+		return m.rollback(opts)
+	})
+}
+
+// ResetMigrations 回滾所有已執行的 migrations
+func ResetMigrations() error {
+	return withMigrator(func(m *migrator) error {
+		return m.reset()
+	})
+}
+
+// RefreshMigrations 回滾所有 migrations 後重新執行
+func RefreshMigrations() error {
+	return withMigrator(func(m *migrator) error {
+		if err := m.reset(); err != nil {
+			return err
+		}
+		return m.migrate(MigrateOptions{})
+	})
+}
+
+// FreshMigrations 刪除所有資料表後重新執行 migrations（不會執行 Down）
+func FreshMigrations() error {
+	return withMigrator(func(m *migrator) error {
+		if err := m.dropAllTables(); err != nil {
+			return err
+		}
+		if err := m.createMigrationsTable(); err != nil {
+			return err
+		}
+		return m.migrate(MigrateOptions{})
+	})
+}
+
+// MigrationStatus - 單一 migration 的執行狀態
+type MigrationStatus struct {
+	Version     string     `json:"version"`
+	Description string     `json:"description"`
+	Ran         bool       `json:"ran"`
+	Batch       int        `json:"batch,omitempty"`
+	ExecutedAt  *time.Time `json:"executed_at,omitempty"`
+	Checksum    string     `json:"checksum,omitempty"` // 執行時記錄的 checksum，舊紀錄為空字串
+	Modified    bool       `json:"modified"`           // 執行後 migration 內容被修改（checksum 不符）
+	Orphan      bool       `json:"orphan"`             // migrations 表中有紀錄，但程式碼中找不到對應的 migration
+}
+
+// GetMigrationStatus 獲取所有 migration 的狀態（依版本號排序），包含找不到程式碼的紀錄
+func GetMigrationStatus() ([]MigrationStatus, error) {
+	// 只讀取狀態，不需要等待 migration 鎖；因此也不建立或升級 migrations 表，避免與執行中的 migrate 同時變更
+	m, err := newMigrator()
+	if err != nil {
+		return nil, err
+	}
+	defer m.db.Close()
+	m.readOnly = true
+
+	executed, err := m.executed()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, mig := range migrations.All() {
+		status := MigrationStatus{Version: mig.Version(), Description: mig.Description()}
+		if record, ok := executed[mig.Version()]; ok {
+			status.Ran, status.Batch, status.ExecutedAt, status.Checksum = true, record.Batch, record.ExecutedAt, record.Checksum
+			if status.Modified, err = m.modified(mig, record); err != nil {
+				return nil, err
+			}
+			delete(executed, mig.Version())
+		}
+		statuses = append(statuses, status)
+	}
+
+	// 剩下的紀錄沒有對應的程式碼
+	for _, record := range executed {
+		statuses = append(statuses, MigrationStatus{
+			Version:     record.Version,
+			Description: record.Description,
+			Ran:         true,
+			Batch:       record.Batch,
+			ExecutedAt:  record.ExecutedAt,
+			Checksum:    record.Checksum,
+			Orphan:      true,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+// RepairChecksums 以目前的 migration 內容重新記錄已執行 migration 的 checksum，回傳更新的版本
+// 用於確認修改已執行的 migration 是刻意的（例如只調整註解或格式），或為舊紀錄補上 checksum
+func RepairChecksums() ([]string, error) {
+	var repaired []string
+	err := withMigrator(func(m *migrator) error {
+		records, err := m.ran()
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			mig, exists := migrations.Get(record.Version)
+			if !exists {
+				continue
+			}
+
+			checksum, err := m.checksum(mig)
+			if err != nil {
+				return err
+			}
+			if checksum == record.Checksum {
+				continue
+			}
+
+			if _, err := m.db.Exec(m.dialect.Rebind("UPDATE migrations SET checksum = ? WHERE version = ?"), checksum, record.Version); err != nil {
+				return err
+			}
+			repaired = append(repaired, record.Version)
+		}
+		return nil
+	})
+
+	sort.Strings(repaired)
+	return repaired, err
+}
+
+// migrator - 持有連線與資料庫類型，執行 migrate / rollback 等操作
+type migrator struct {
+	db       *sql.DB
+	dialect  migrations.Dialect
+	pretend  bool // 只輸出 SQL，不執行
+	readOnly bool // 不變更資料庫：不建立或升級 migrations 表
+}
+
+// migrationRecord - migrations 表中的一筆紀錄
+type migrationRecord struct {
+	Version     string
+	Description string
+	Batch       int
+	ExecutedAt  *time.Time
+	Checksum    string
+}
+
+func newMigrator() (*migrator, error) {
+	db, dialect, err := getDBConnection()
+	if err != nil {
+		return nil, err
+	}
+	return &migrator{db: db, dialect: dialect}, nil
+}
+
+// withMigrator - 建立連線、取得 migration 鎖並確認 migrations 表存在後執行 fn
+// 待執行的 migrations 在取得鎖之後才查詢，等待其他程序執行完畢後不會重複執行
+func withMigrator(fn func(m *migrator) error) error {
+	m, err := newMigrator()
 	if err != nil {
 		return err
 	}
-	defer db.Close()
-	
-	// 建立 migrations 記錄表
-	if err := createMigrationsTable(db); err != nil {
+	defer m.db.Close()
+
+	timeout := time.Duration(config.GlobalConfig.Database.MigrationLockTimeout) * time.Second
+	lock, err := acquireMigrationLock(m.db, m.dialect, timeout)
+	if err != nil {
 		return err
 	}
-	
-	// 獲取已執行的 migrations
-	executed, err := getExecutedMigrations(db)
+	defer lock.Release()
+
+	if err := m.createMigrationsTable(); err != nil {
+		return err
+	}
+
+	return fn(m)
+}
+
+// withPretendMigrator - 建立 pretend 模式的 migrator 後執行 fn
+// 不會變更資料庫，因此不取得 migration 鎖，也不建立 migrations 表
+func withPretendMigrator(fn func(m *migrator) error) error {
+	m, err := newMigrator()
 	if err != nil {
 		return err
 	}
-	
-	// 執行所有未執行的 migrations
-	allMigrations := migrations.All()
-	hasNew := false
-	
-	for _, m := range allMigrations {
-		if _, exists := executed[m.Version()]; !exists {
-			hasNew = true
-			log.Printf("🚀 執行 Migration: %s - %s", m.Version(), m.Description())
-			
-			if err := m.Up(db); err != nil {
-				return fmt.Errorf("migration %s 失敗: %v", m.Version(), err)
-			}
-			
-			// 記錄已執行
-			if err := recordMigration(db, m.Version(), m.Description()); err != nil {
+	defer m.db.Close()
+
+	m.pretend, m.readOnly = true, true
+	return fn(m)
+}
+
+func (m *migrator) migrate(opts MigrateOptions) error {
+	if opts.To != "" {
+		if _, exists := migrations.Get(opts.To); !exists {
+			return fmt.Errorf("找不到版本 %s 的 migration", opts.To)
+		}
+	}
+
+	executed, err := m.executed()
+	if err != nil {
+		return err
+	}
+
+	if err := m.verifyChecksums(executed, opts.Strict || config.GlobalConfig.Database.MigrationStrictChecksum); err != nil {
+		return err
+	}
+
+	var pending []migrations.Migration
+	for _, mig := range migrations.All() {
+		if opts.To != "" && mig.Version() > opts.To {
+			break
+		}
+		if _, exists := executed[mig.Version()]; !exists {
+			pending = append(pending, mig)
+		}
+	}
+
+	if len(pending) == 0 {
+		log.Println("✓ 資料庫已是最新版本，無需 migration")
+		return nil
+	}
+
+	if m.pretend {
+		for _, mig := range pending {
+			if err := m.pretendRun(mig, "up", mig.Up); err != nil {
 				return err
 			}
 		}
+		return nil
 	}
-	
-	if !hasNew {
-		log.Println("✓ 資料庫已是最新版本，無需 migration")
-	} else {
-		log.Println("✅ 所有 Migrations 執行成功！")
+
+	batch, err := m.nextBatch()
+	if err != nil {
+		return err
 	}
-	
+
+	for _, mig := range pending {
+		log.Printf("🚀 執行 Migration: %s - %s", mig.Version(), mig.Description())
+
+		checksum, err := m.checksum(mig)
+		if err != nil {
+			return err
+		}
+
+		err = m.transaction(func(exec migrations.Executor) error {
+			if err := mig.Up(exec, m.dialect); err != nil {
+				return fmt.Errorf("migration %s 失敗: %v", mig.Version(), err)
+			}
+			return recordMigration(exec, m.dialect, mig.Version(), mig.Description(), batch, checksum)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("✅ 所有 Migrations 執行成功！（batch %d）", batch)
 	return nil
 }
 
-// RollbackMigration 回滾最後一個 migration
-func RollbackMigration() error {
-	db, err := getDBConnection()
+func (m *migrator) rollback(opts RollbackOptions) error {
+	records, err := m.ran()
 	if err != nil {
 		return err
 	}
-	defer db.Close()
-	
-	// 獲取最後執行的 migration
-	lastVersion, err := getLastMigration(db)
+
+	var targets []migrationRecord
+	if opts.Step > 0 {
+		targets = records[:min(opts.Step, len(records))]
+	} else {
+		for _, record := range records {
+			if record.Batch != records[0].Batch {
+				break
+			}
+			targets = append(targets, record)
+		}
+	}
+
+	return m.rollbackRecords(targets)
+}
+
+func (m *migrator) reset() error {
+	records, err := m.ran()
 	if err != nil {
 		return err
 	}
-	
-	if lastVersion == "" {
+
+	return m.rollbackRecords(records)
+}
+
+// rollbackRecords - 依序執行 Down 並刪除紀錄，records 需由新到舊排列
+func (m *migrator) rollbackRecords(records []migrationRecord) error {
+	if len(records) == 0 {
 		log.Println("⚠️  沒有可以回滾的 migration")
 		return nil
 	}
-	
-	// 獲取對應的 migration
-	m, exists := migrations.Get(lastVersion)
-	if !exists {
-		return fmt.Errorf("找不到版本 %s 的 migration", lastVersion)
+
+	for _, record := range records {
+		mig, exists := migrations.Get(record.Version)
+		if !exists {
+			return fmt.Errorf("找不到版本 %s 的 migration", record.Version)
+		}
+
+		if m.pretend {
+			if err := m.pretendRun(mig, "down", mig.Down); err != nil {
+				return err
+			}
+			continue
+		}
+
+		log.Printf("⏮️  回滾 Migration: %s - %s", mig.Version(), mig.Description())
+
+		err := m.transaction(func(exec migrations.Executor) error {
+			if err := mig.Down(exec, m.dialect); err != nil {
+				return fmt.Errorf("rollback %s 失敗: %v", mig.Version(), err)
+			}
+			return removeMigrationRecord(exec, m.dialect, mig.Version())
+		})
+		if err != nil {
+			return err
+		}
 	}
-	
-	log.Printf("⏮️  回滾 Migration: %s - %s", m.Version(), m.Description())
-	
-	// 執行 Down
-	if err := m.Down(db); err != nil {
-		return fmt.Errorf("rollback %s 失敗: %v", m.Version(), err)
+
+	if !m.pretend {
+		log.Println("✅ Rollback 成功！")
 	}
-	
-	// 刪除記錄
-	if err := removeMigrationRecord(db, lastVersion); err != nil {
-		return err
+	return nil
+}
+
+// pretendRun - 以 PretendExecutor 執行 Up 或 Down，依序輸出記錄的語句
+func (m *migrator) pretendRun(mig migrations.Migration, direction string, run func(migrations.Executor, migrations.Dialect) error) error {
+	queries, err := m.capture(run)
+	if err != nil {
+		return fmt.Errorf("migration %s 失敗: %v", mig.Version(), err)
 	}
-	
-	log.Println("✅ Rollback 成功！")
+
+	fmt.Printf("-- %s %s（%s）\n", mig.Version(), mig.Description(), direction)
+	for _, q := range queries {
+		query := strings.TrimSpace(q.Query)
+		if !strings.HasSuffix(query, ";") {
+			query += ";"
+		}
+		fmt.Println(query)
+		if len(q.Args) > 0 {
+			fmt.Printf("-- 參數: %v\n", q.Args)
+		}
+	}
+	fmt.Println()
+
 	return nil
 }
 
-// GetMigrationStatus 獲取 migration 狀態
-func GetMigrationStatus() (map[string]bool, error) {
-	db, err := getDBConnection()
+// capture - 以 PretendExecutor 執行 run，回傳記錄的語句，不會變更資料庫
+// 讀取資料的查詢在唯讀交易中執行，結束後回滾
+func (m *migrator) capture(run func(migrations.Executor, migrations.Dialect) error) ([]migrations.PretendedQuery, error) {
+	tx, err := m.db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("無法開始交易: %v", err)
 	}
-	defer db.Close()
-	
-	if err := createMigrationsTable(db); err != nil {
+	defer tx.Rollback()
+
+	exec := migrations.NewPretendExecutor(tx)
+	// migration 執行成功的訊息（例如「✓ 建立 users 表成功」）在沒有實際執行時沒有意義
+	if err := discardStdout(func() error { return run(exec, m.dialect) }); err != nil {
 		return nil, err
 	}
-	
-	executed, err := getExecutedMigrations(db)
+	return exec.Queries(), nil
+}
+
+// checksum - migration 的 Up 在目前資料庫會執行的 SQL 的 SHA-256
+// 只修改註解、Down 或其他資料庫的 SQL 不影響 checksum
+func (m *migrator) checksum(mig migrations.Migration) (string, error) {
+	queries, err := m.capture(mig.Up)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("無法計算 migration %s 的 checksum: %v", mig.Version(), err)
 	}
-	
-	status := make(map[string]bool)
-	for _, m := range migrations.All() {
-		_, status[m.Version()] = executed[m.Version()]
+
+	hash := sha256.New()
+	for _, q := range queries {
+		fmt.Fprintf(hash, "%s\n%v\n", strings.TrimSpace(q.Query), q.Args)
 	}
-	
-	return status, nil
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-// === 輔助函數 ===
+// modified - 已執行的 migration 內容是否被修改，沒有記錄 checksum 的舊紀錄不檢查
+func (m *migrator) modified(mig migrations.Migration, record migrationRecord) (bool, error) {
+	if record.Checksum == "" {
+		return false, nil
+	}
 
-func getDBConnection() (*sql.DB, error) {
-	cfg := config.GlobalConfig.Database
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&multiStatements=true",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
-	
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("無法連接資料庫: %v", err)
-	}
-	
-	return db, nil
-}
-
-func createMigrationsTable(db *sql.DB) error {
-	query := `
-		CREATE TABLE IF NOT EXISTS migrations (
-			version VARCHAR(14) PRIMARY KEY,
-			description VARCHAR(255) NOT NULL,
-			executed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
-	`
-	
-	_, err := db.Exec(query)
+	checksum, err := m.checksum(mig)
+	if err != nil {
+		return false, err
+	}
+	return checksum != record.Checksum, nil
+}
+
+// verifyChecksums - 檢查已執行的 migrations 是否被修改，strict 時有修改即回傳錯誤，否則只記錄警告
+func (m *migrator) verifyChecksums(executed map[string]migrationRecord, strict bool) error {
+	var modified []string
+	for _, mig := range migrations.All() {
+		record, ok := executed[mig.Version()]
+		if !ok {
+			continue
+		}
+
+		changed, err := m.modified(mig, record)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+
+		modified = append(modified, mig.Version())
+		bootstrap.Log.Warning("已執行的 migration 內容被修改", map[string]interface{}{
+			"version":     mig.Version(),
+			"description": mig.Description(),
+			"checksum":    record.Checksum,
+		})
+	}
+
+	if strict && len(modified) > 0 {
+		return fmt.Errorf("已執行的 migration 內容被修改: %s（確認修改無誤後執行 repair 重新記錄 checksum）", strings.Join(modified, "、"))
+	}
+	return nil
+}
+
+// transaction - 在交易中執行 fn，資料庫不支援交易式 DDL 時直接執行
+func (m *migrator) transaction(fn func(exec migrations.Executor) error) error {
+	if !m.dialect.TransactionalDDL() {
+		return fn(m.db)
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("無法開始交易: %v", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// dropAllTables - 刪除目前資料庫（schema）中的所有資料表
+func (m *migrator) dropAllTables() error {
+	query, err := migrations.SQL{
+		migrations.MySQL:    `SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'`,
+		migrations.Postgres: `SELECT tablename FROM pg_tables WHERE schemaname = current_schema()`,
+	}.For(m.dialect)
+	if err != nil {
+		return err
+	}
+
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return err
+		}
+		tables = append(tables, m.dialect.Quote(table))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(tables) == 0 {
+		return nil
+	}
+
+	log.Printf("🗑️  刪除所有資料表（%d 個）", len(tables))
+
+	if m.dialect == migrations.Postgres {
+		_, err := m.db.Exec("DROP TABLE IF EXISTS " + strings.Join(tables, ", ") + " CASCADE")
+		return err
+	}
+
+	// MySQL 需在同一個連線上暫時關閉外鍵檢查
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 1")
+
+	_, err = conn.ExecContext(ctx, "DROP TABLE IF EXISTS "+strings.Join(tables, ", "))
 	return err
 }
 
-func getExecutedMigrations(db *sql.DB) (map[string]string, error) {
-	rows, err := db.Query("SELECT version, description FROM migrations ORDER BY version")
+// executed - 已執行的 migrations，以版本為 key
+func (m *migrator) executed() (map[string]migrationRecord, error) {
+	records, err := m.ran()
+	if err != nil {
+		return nil, err
+	}
+
+	executed := make(map[string]migrationRecord, len(records))
+	for _, record := range records {
+		executed[record.Version] = record
+	}
+
+	return executed, nil
+}
+
+// ran - 已執行的 migrations，由新到舊排列（批次、版本遞減）
+func (m *migrator) ran() ([]migrationRecord, error) {
+	batch, checksum := "batch", "checksum"
+
+	// pretend 模式與 status 不會建立或升級 migrations 表：表不存在時視為尚未執行任何 migration，舊版缺少的欄位以預設值代替
+	if m.readOnly {
+		exists, err := m.hasTable("migrations")
+		if err != nil || !exists {
+			return nil, err
+		}
+		if exists, err = m.hasColumn("migrations", "batch"); err != nil {
+			return nil, err
+		} else if !exists {
+			batch = "1"
+		}
+		if exists, err = m.hasColumn("migrations", "checksum"); err != nil {
+			return nil, err
+		} else if !exists {
+			checksum = "NULL"
+		}
+	}
+
+	rows, err := m.db.Query(fmt.Sprintf("SELECT version, description, %s, executed_at, %s FROM migrations ORDER BY 3 DESC, 1 DESC", batch, checksum))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	
-	executed := make(map[string]string)
+
+	var records []migrationRecord
 	for rows.Next() {
-		var version, description string
-		if err := rows.Scan(&version, &description); err != nil {
+		var record migrationRecord
+		var executedAt sql.NullTime
+		var checksum sql.NullString
+		if err := rows.Scan(&record.Version, &record.Description, &record.Batch, &executedAt, &checksum); err != nil {
 			return nil, err
 		}
-		executed[version] = description
+		if executedAt.Valid {
+			record.ExecutedAt = &executedAt.Time
+		}
+		record.Checksum = checksum.String
+		records = append(records, record)
 	}
-	
-	return executed, nil
+
+	return records, rows.Err()
 }
 
-func getLastMigration(db *sql.DB) (string, error) {
-	var version string
-	err := db.QueryRow("SELECT version FROM migrations ORDER BY version DESC LIMIT 1").Scan(&version)
-	if err == sql.ErrNoRows {
-		return "", nil
+func (m *migrator) nextBatch() (int, error) {
+	var batch int
+	if err := m.db.QueryRow("SELECT COALESCE(MAX(batch), 0) FROM migrations").Scan(&batch); err != nil {
+		return 0, err
 	}
-	return version, err
+	return batch + 1, nil
+}
+
+func (m *migrator) createMigrationsTable() error {
+	query, err := migrations.SQL{
+		migrations.MySQL: `
+			CREATE TABLE IF NOT EXISTS migrations (
+				version VARCHAR(14) PRIMARY KEY,
+				description VARCHAR(255) NOT NULL,
+				batch INT NOT NULL DEFAULT 1,
+				checksum VARCHAR(64) NULL,
+				executed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+		`,
+		migrations.Postgres: `
+			CREATE TABLE IF NOT EXISTS migrations (
+				version VARCHAR(14) PRIMARY KEY,
+				description VARCHAR(255) NOT NULL,
+				batch INT NOT NULL DEFAULT 1,
+				checksum VARCHAR(64) NULL,
+				executed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+			)
+		`,
+	}.For(m.dialect)
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.db.Exec(query); err != nil {
+		return err
+	}
+
+	// 舊版的 migrations 表沒有 batch 欄位，既有紀錄視為第 1 批
+	if err := m.ensureColumn("migrations", "batch", "INT NOT NULL DEFAULT 1"); err != nil {
+		return err
+	}
+
+	// 舊版沒有 checksum 欄位，既有紀錄為 NULL（不檢查，可執行 repair 補上）
+	return m.ensureColumn("migrations", "checksum", "VARCHAR(64) NULL")
+}
+
+// hasTable - 資料表是否存在
+func (m *migrator) hasTable(table string) (bool, error) {
+	query, err := migrations.SQL{
+		migrations.MySQL:    `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?`,
+		migrations.Postgres: `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?`,
+	}.For(m.dialect)
+	if err != nil {
+		return false, err
+	}
+
+	var count int
+	if err := m.db.QueryRow(m.dialect.Rebind(query), table).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// hasColumn - 欄位是否存在
+func (m *migrator) hasColumn(table, column string) (bool, error) {
+	query, err := migrations.SQL{
+		migrations.MySQL:    `SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?`,
+		migrations.Postgres: `SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`,
+	}.For(m.dialect)
+	if err != nil {
+		return false, err
+	}
+
+	var count int
+	if err := m.db.QueryRow(m.dialect.Rebind(query), table, column).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ensureColumn - 欄位不存在時新增
+func (m *migrator) ensureColumn(table, column, definition string) error {
+	exists, err := m.hasColumn(table, column)
+	if err != nil || exists {
+		return err
+	}
+
+	_, err = m.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// === 輔助函數 ===
+
+// getDBConnection - 依 DB_TYPE 建立連線，並回傳對應的 Dialect
+func getDBConnection() (*sql.DB, migrations.Dialect, error) {
+	cfg := config.GlobalConfig.Database
+
+	dialect, err := migrations.ParseDialect(cfg.Type)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var db *sql.DB
+	switch dialect {
+	case migrations.Postgres:
+		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+			cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port, cfg.SSLMode)
+		db, err = sql.Open("postgres", dsn)
+	default:
+		// 不開啟 multiStatements：SQL migrations 會先以 migrations.SplitStatements 拆成單一語句
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True",
+			cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
+		db, err = sql.Open("mysql", dsn)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("無法連接資料庫: %v", err)
+	}
+
+	return db, dialect, nil
+}
+
+// discardStdout - 執行 fn 時暫時捨棄標準輸出
+func discardStdout(fn func() error) error {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return fn()
+	}
+	defer devNull.Close()
+
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = stdout }()
+
+	return fn()
 }
 
-func recordMigration(db *sql.DB, version, description string) error {
-	_, err := db.Exec("INSERT INTO migrations (version, description) VALUES (?, ?)", version, description)
+func recordMigration(exec migrations.Executor, dialect migrations.Dialect, version, description string, batch int, checksum string) error {
+	_, err := exec.Exec(dialect.Rebind("INSERT INTO migrations (version, description, batch, checksum) VALUES (?, ?, ?, ?)"), version, description, batch, checksum)
 	return err
 }
 
-func removeMigrationRecord(db *sql.DB, version string) error {
-	_, err := db.Exec("DELETE FROM migrations WHERE version = ?", version)
+func removeMigrationRecord(exec migrations.Executor, dialect migrations.Dialect, version string) error {
+	_, err := exec.Exec(dialect.Rebind("DELETE FROM migrations WHERE version = ?"), version)
 	return err
 }
@@ -0,0 +1,355 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// Blueprint - 資料表的欄位、索引與外鍵定義
+// 欄位預設為 NOT NULL（與 Laravel 相同），需要 NULL 時呼叫 Nullable
+type Blueprint struct {
+	table       string
+	columns     []*Column
+	primary     []string
+	indexes     []*Index
+	foreignKeys []*ForeignKey
+
+	// 只用於 Table
+	dropColumns  []string
+	renames      [][2]string
+	dropIndexes  []string
+	dropForeigns []string
+
+	errs []error
+}
+
+func newBlueprint(table string) *Blueprint {
+	return &Blueprint{table: table}
+}
+
+type columnType int
+
+const (
+	incrementsType columnType = iota
+	bigIncrementsType
+	stringType
+	charType
+	textType
+	longTextType
+	integerType
+	bigIntegerType
+	booleanType
+	decimalType
+	dateType
+	timestampType
+	jsonType
+	uuidType
+)
+
+func (b *Blueprint) addColumn(name string, kind columnType) *Column {
+	c := &Column{blueprint: b, name: name, kind: kind}
+	b.columns = append(b.columns, c)
+	return c
+}
+
+// ID - 自動遞增的 BIGINT 主鍵 id
+func (b *Blueprint) ID() *Column {
+	return b.BigIncrements("id")
+}
+
+// Increments - 自動遞增的 INT 主鍵
+func (b *Blueprint) Increments(name string) *Column {
+	return b.addColumn(name, incrementsType)
+}
+
+// BigIncrements - 自動遞增的 BIGINT 主鍵
+func (b *Blueprint) BigIncrements(name string) *Column {
+	return b.addColumn(name, bigIncrementsType)
+}
+
+// String - VARCHAR(length)
+func (b *Blueprint) String(name string, length int) *Column {
+	c := b.addColumn(name, stringType)
+	c.length = length
+	return c
+}
+
+// Char - CHAR(length)
+func (b *Blueprint) Char(name string, length int) *Column {
+	c := b.addColumn(name, charType)
+	c.length = length
+	return c
+}
+
+// Text - TEXT
+func (b *Blueprint) Text(name string) *Column {
+	return b.addColumn(name, textType)
+}
+
+// LongText - MySQL 為 LONGTEXT，其他資料庫為 TEXT
+func (b *Blueprint) LongText(name string) *Column {
+	return b.addColumn(name, longTextType)
+}
+
+// Integer - INT
+func (b *Blueprint) Integer(name string) *Column {
+	return b.addColumn(name, integerType)
+}
+
+// BigInteger - BIGINT
+func (b *Blueprint) BigInteger(name string) *Column {
+	return b.addColumn(name, bigIntegerType)
+}
+
+// Boolean - BOOLEAN
+func (b *Blueprint) Boolean(name string) *Column {
+	return b.addColumn(name, booleanType)
+}
+
+// Decimal - DECIMAL(precision, scale)
+func (b *Blueprint) Decimal(name string, precision, scale int) *Column {
+	c := b.addColumn(name, decimalType)
+	c.precision, c.scale = precision, scale
+	return c
+}
+
+// Date - DATE
+func (b *Blueprint) Date(name string) *Column {
+	return b.addColumn(name, dateType)
+}
+
+// Timestamp - MySQL 為 TIMESTAMP，Postgres 為 TIMESTAMPTZ，SQLite 為 DATETIME
+func (b *Blueprint) Timestamp(name string) *Column {
+	return b.addColumn(name, timestampType)
+}
+
+// JSON - MySQL 為 JSON，Postgres 為 JSONB，SQLite 為 TEXT
+func (b *Blueprint) JSON(name string) *Column {
+	return b.addColumn(name, jsonType)
+}
+
+// UUID - Postgres 為 UUID，其他資料庫為 36 個字元的字串
+func (b *Blueprint) UUID(name string) *Column {
+	return b.addColumn(name, uuidType)
+}
+
+// ForeignID - 參照其他資料表 id 的 BIGINT 欄位，搭配 Constrained 建立外鍵
+func (b *Blueprint) ForeignID(name string) *Column {
+	return b.BigInteger(name)
+}
+
+// Timestamps - created_at、updated_at（可為 NULL，預設為目前時間，MySQL 更新時自動更新 updated_at）
+func (b *Blueprint) Timestamps() {
+	b.Timestamp("created_at").Nullable().UseCurrent()
+	b.Timestamp("updated_at").Nullable().UseCurrent().UseCurrentOnUpdate()
+}
+
+// SoftDeletes - deleted_at 與索引（對應 gorm.DeletedAt）
+func (b *Blueprint) SoftDeletes() {
+	b.Timestamp("deleted_at").Nullable().Index()
+}
+
+// Primary - 複合主鍵，例如樞紐表的 PRIMARY KEY (role_id, permission_id)
+func (b *Blueprint) Primary(columns ...string) {
+	b.primary = columns
+}
+
+// Index - 建立索引，預設名稱為 idx_資料表_欄位
+func (b *Blueprint) Index(columns ...string) *Index {
+	i := &Index{columns: columns}
+	b.indexes = append(b.indexes, i)
+	return i
+}
+
+// Unique - 建立唯一索引，預設名稱為 idx_資料表_欄位
+func (b *Blueprint) Unique(columns ...string) *Index {
+	i := b.Index(columns...)
+	i.unique = true
+	return i
+}
+
+// Foreign - 建立外鍵，以 References、On 指定參照的欄位與資料表
+func (b *Blueprint) Foreign(column string) *ForeignKey {
+	fk := &ForeignKey{column: column, references: "id"}
+	b.foreignKeys = append(b.foreignKeys, fk)
+	return fk
+}
+
+// DropColumn - 刪除欄位（只用於 Table）
+func (b *Blueprint) DropColumn(columns ...string) {
+	b.dropColumns = append(b.dropColumns, columns...)
+}
+
+// RenameColumn - 重新命名欄位（只用於 Table）
+func (b *Blueprint) RenameColumn(from, to string) {
+	b.renames = append(b.renames, [2]string{from, to})
+}
+
+// DropIndex - 依名稱刪除索引（只用於 Table）
+func (b *Blueprint) DropIndex(name string) {
+	b.dropIndexes = append(b.dropIndexes, name)
+}
+
+// DropForeign - 依名稱刪除外鍵（只用於 Table，SQLite 不支援）
+func (b *Blueprint) DropForeign(name string) {
+	b.dropForeigns = append(b.dropForeigns, name)
+}
+
+// Column - 欄位定義，修飾方法可串接
+type Column struct {
+	blueprint *Blueprint
+	name      string
+	kind      columnType
+
+	length           int
+	precision, scale int
+
+	nullable           bool
+	unsigned           bool
+	hasDefault         bool
+	defaultValue       interface{}
+	useCurrent         bool
+	useCurrentOnUpdate bool
+	after              string
+	change             bool
+}
+
+// Nullable - 允許 NULL
+func (c *Column) Nullable() *Column {
+	c.nullable = true
+	return c
+}
+
+// Default - 預設值，可為字串、數字、布林、nil 或 Raw 的 SQL 表達式
+func (c *Column) Default(value interface{}) *Column {
+	c.hasDefault, c.defaultValue = true, value
+	return c
+}
+
+// UseCurrent - 預設為目前時間（DEFAULT CURRENT_TIMESTAMP）
+func (c *Column) UseCurrent() *Column {
+	c.useCurrent = true
+	return c
+}
+
+// UseCurrentOnUpdate - 更新資料時自動設為目前時間（只有 MySQL 支援，其他資料庫忽略）
+func (c *Column) UseCurrentOnUpdate() *Column {
+	c.useCurrentOnUpdate = true
+	return c
+}
+
+// Unsigned - 無號整數（只有 MySQL 支援，其他資料庫忽略）
+func (c *Column) Unsigned() *Column {
+	c.unsigned = true
+	return c
+}
+
+// After - 新增欄位時放在指定欄位之後（只有 MySQL 支援，其他資料庫忽略）
+func (c *Column) After(column string) *Column {
+	c.after = column
+	return c
+}
+
+// Change - 修改既有欄位的型別、NULL 與預設值（只用於 Table，SQLite 不支援）
+// 與 MySQL 的 MODIFY COLUMN 相同，需重新指定所有修飾，沒有指定 Default 時會移除原本的預設值
+func (c *Column) Change() *Column {
+	c.change = true
+	return c
+}
+
+// Index - 為此欄位建立索引
+func (c *Column) Index() *Column {
+	c.blueprint.Index(c.name)
+	return c
+}
+
+// Unique - 為此欄位建立唯一索引
+func (c *Column) Unique() *Column {
+	c.blueprint.Unique(c.name)
+	return c
+}
+
+// Constrained - 建立參照 table.id 的外鍵，未指定 table 時由欄位名稱推測（user_id → users）
+func (c *Column) Constrained(table ...string) *ForeignKey {
+	fk := c.blueprint.Foreign(c.name)
+	if len(table) > 0 {
+		return fk.On(table[0])
+	}
+
+	base, ok := strings.CutSuffix(c.name, "_id")
+	if !ok || base == "" {
+		c.blueprint.errs = append(c.blueprint.errs, fmt.Errorf("無法從欄位 %s 推測參照的資料表，請使用 Constrained(\"資料表\")", c.name))
+		return fk
+	}
+	return fk.On(inflection.Plural(base))
+}
+
+// Index - 索引定義
+type Index struct {
+	name    string
+	columns []string
+	unique  bool
+}
+
+// Name - 指定索引名稱
+func (i *Index) Name(name string) *Index {
+	i.name = name
+	return i
+}
+
+// ForeignKey - 外鍵定義
+type ForeignKey struct {
+	name       string
+	column     string
+	on         string
+	references string
+	onDelete   string
+	onUpdate   string
+}
+
+// References - 參照的欄位，預設為 id
+func (fk *ForeignKey) References(column string) *ForeignKey {
+	fk.references = column
+	return fk
+}
+
+// On - 參照的資料表
+func (fk *ForeignKey) On(table string) *ForeignKey {
+	fk.on = table
+	return fk
+}
+
+// Name - 指定外鍵名稱，預設為 fk_資料表_參照的資料表（欄位不是 參照資料表單數_id 時為 fk_資料表_欄位）
+func (fk *ForeignKey) Name(name string) *ForeignKey {
+	fk.name = name
+	return fk
+}
+
+// CascadeOnDelete - 參照的資料刪除時一併刪除（ON DELETE CASCADE）
+func (fk *ForeignKey) CascadeOnDelete() *ForeignKey {
+	fk.onDelete = "CASCADE"
+	return fk
+}
+
+// NullOnDelete - 參照的資料刪除時設為 NULL（ON DELETE SET NULL），欄位需為 Nullable
+func (fk *ForeignKey) NullOnDelete() *ForeignKey {
+	fk.onDelete = "SET NULL"
+	return fk
+}
+
+// RestrictOnDelete - 有資料參照時不允許刪除（ON DELETE RESTRICT）
+func (fk *ForeignKey) RestrictOnDelete() *ForeignKey {
+	fk.onDelete = "RESTRICT"
+	return fk
+}
+
+// CascadeOnUpdate - 參照的欄位更新時一併更新（ON UPDATE CASCADE）
+func (fk *ForeignKey) CascadeOnUpdate() *ForeignKey {
+	fk.onUpdate = "CASCADE"
+	return fk
+}
+
+// Raw - 不加引號直接放入 DDL 的 SQL 表達式，例如 Default(schema.Raw("CURRENT_DATE"))
+type Raw string
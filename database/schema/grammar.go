@@ -0,0 +1,413 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// grammar - 依資料庫類型產生 DDL
+type grammar struct {
+	dialect Dialect
+}
+
+func grammarFor(dialect Dialect) (grammar, error) {
+	switch dialect {
+	case MySQL, Postgres, SQLite:
+		return grammar{dialect: dialect}, nil
+	default:
+		return grammar{}, fmt.Errorf("schema 不支援資料庫類型: %s", dialect)
+	}
+}
+
+// compileCreate - CREATE TABLE；MySQL 的索引寫在表內，Postgres、SQLite 另外以 CREATE INDEX 建立
+func (g grammar) compileCreate(b *Blueprint) ([]string, error) {
+	if len(b.dropColumns) > 0 || len(b.renames) > 0 || len(b.dropIndexes) > 0 || len(b.dropForeigns) > 0 {
+		return nil, fmt.Errorf("建立資料表 %s 時不能刪除或重新命名欄位、索引與外鍵，請使用 Table", b.table)
+	}
+	if len(b.columns) == 0 {
+		return nil, fmt.Errorf("資料表 %s 沒有任何欄位", b.table)
+	}
+	for _, c := range b.columns {
+		if c.change {
+			return nil, fmt.Errorf("建立資料表 %s 時不能修改欄位 %s，請使用 Table", b.table, c.name)
+		}
+	}
+
+	var lines []string
+	for _, c := range b.columns {
+		lines = append(lines, g.column(c, false))
+	}
+	if len(b.primary) > 0 {
+		lines = append(lines, "PRIMARY KEY ("+g.columnize(b.primary)+")")
+	}
+	if g.dialect == MySQL {
+		for _, i := range b.indexes {
+			lines = append(lines, g.inlineIndex(b, i))
+		}
+	}
+	for _, fk := range b.foreignKeys {
+		lines = append(lines, "CONSTRAINT "+g.quote(g.foreignKeyName(b, fk))+" "+g.foreignKey(fk))
+	}
+
+	statement := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n    %s\n)", g.quote(b.table), strings.Join(lines, ",\n    "))
+	if g.dialect == MySQL {
+		statement += " ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci"
+	}
+
+	statements := []string{statement}
+	if g.dialect != MySQL {
+		for _, i := range b.indexes {
+			statements = append(statements, g.createIndex(b, i))
+		}
+	}
+	return statements, nil
+}
+
+// compileAlter - ALTER TABLE，依序刪除外鍵、索引、欄位，再重新命名、修改、新增欄位、索引與外鍵
+// SQLite 一個 ALTER TABLE 只能做一件事，且不能新增或刪除既有欄位的外鍵
+func (g grammar) compileAlter(b *Blueprint) ([]string, error) {
+	table := g.quote(b.table)
+	alter := "ALTER TABLE " + table + " "
+	var statements []string
+
+	// 外鍵
+	for _, name := range b.dropForeigns {
+		switch g.dialect {
+		case MySQL:
+			statements = append(statements, alter+"DROP FOREIGN KEY "+g.quote(name))
+		case Postgres:
+			statements = append(statements, alter+"DROP CONSTRAINT IF EXISTS "+g.quote(name))
+		default:
+			return nil, errors.New("SQLite 不支援刪除外鍵")
+		}
+	}
+
+	// 索引
+	for _, name := range b.dropIndexes {
+		if g.dialect == MySQL {
+			statements = append(statements, alter+"DROP INDEX "+g.quote(name))
+		} else {
+			statements = append(statements, "DROP INDEX IF EXISTS "+g.quote(name))
+		}
+	}
+
+	// 刪除欄位
+	var drops []string
+	for _, column := range b.dropColumns {
+		if g.dialect == Postgres {
+			drops = append(drops, "DROP COLUMN IF EXISTS "+g.quote(column))
+		} else {
+			drops = append(drops, "DROP COLUMN "+g.quote(column))
+		}
+	}
+	statements = append(statements, g.alterClauses(alter, drops)...)
+
+	// 重新命名欄位
+	for _, rename := range b.renames {
+		statements = append(statements, fmt.Sprintf("%sRENAME COLUMN %s TO %s", alter, g.quote(rename[0]), g.quote(rename[1])))
+	}
+
+	// 修改欄位
+	var changes []string
+	for _, c := range b.columns {
+		if !c.change {
+			continue
+		}
+		clauses, err := g.changeColumn(c)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, clauses...)
+	}
+	statements = append(statements, g.alterClauses(alter, changes)...)
+
+	// 新增欄位；SQLite 的外鍵只能寫在新增的欄位上
+	inlineForeignKeys := make(map[*ForeignKey]bool)
+	var adds []string
+	for _, c := range b.columns {
+		if c.change {
+			continue
+		}
+		definition := g.column(c, true)
+		if g.dialect == Postgres {
+			adds = append(adds, "ADD COLUMN IF NOT EXISTS "+definition)
+			continue
+		}
+		if g.dialect == SQLite {
+			for _, fk := range b.foreignKeys {
+				if fk.column == c.name {
+					definition += " " + g.references(fk)
+					inlineForeignKeys[fk] = true
+				}
+			}
+		}
+		adds = append(adds, "ADD COLUMN "+definition)
+	}
+	statements = append(statements, g.alterClauses(alter, adds)...)
+
+	// 主鍵
+	if len(b.primary) > 0 {
+		if g.dialect == SQLite {
+			return nil, errors.New("SQLite 不支援在既有資料表新增主鍵")
+		}
+		statements = append(statements, alter+"ADD PRIMARY KEY ("+g.columnize(b.primary)+")")
+	}
+
+	// 索引
+	for _, i := range b.indexes {
+		if g.dialect == MySQL {
+			statements = append(statements, alter+"ADD "+g.inlineIndex(b, i))
+		} else {
+			statements = append(statements, g.createIndex(b, i))
+		}
+	}
+
+	// 外鍵
+	for _, fk := range b.foreignKeys {
+		if inlineForeignKeys[fk] {
+			continue
+		}
+		if g.dialect == SQLite {
+			return nil, fmt.Errorf("SQLite 不支援為既有欄位 %s 新增外鍵", fk.column)
+		}
+		statements = append(statements, alter+"ADD CONSTRAINT "+g.quote(g.foreignKeyName(b, fk))+" "+g.foreignKey(fk))
+	}
+
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("修改資料表 %s 時沒有任何變更", b.table)
+	}
+	return statements, nil
+}
+
+// alterClauses - MySQL、Postgres 合併為一個 ALTER TABLE，SQLite 每個子句各一個語句
+func (g grammar) alterClauses(alter string, clauses []string) []string {
+	if len(clauses) == 0 {
+		return nil
+	}
+	if g.dialect != SQLite {
+		return []string{alter + strings.Join(clauses, ", ")}
+	}
+
+	statements := make([]string, len(clauses))
+	for i, clause := range clauses {
+		statements[i] = alter + clause
+	}
+	return statements
+}
+
+// column - 欄位定義，adding 為 ALTER TABLE ADD COLUMN（MySQL 才會加上 AFTER）
+func (g grammar) column(c *Column, adding bool) string {
+	definition := g.quote(c.name) + " " + g.columnType(c)
+
+	if c.kind == incrementsType || c.kind == bigIncrementsType {
+		switch g.dialect {
+		case MySQL:
+			definition += " NOT NULL AUTO_INCREMENT PRIMARY KEY"
+		case Postgres:
+			definition += " PRIMARY KEY"
+		default:
+			definition += " PRIMARY KEY AUTOINCREMENT"
+		}
+		return definition + g.after(c, adding)
+	}
+
+	if c.unsigned && g.dialect == MySQL && (c.kind == integerType || c.kind == bigIntegerType) {
+		definition += " UNSIGNED"
+	}
+	if c.nullable {
+		definition += " NULL"
+	} else {
+		definition += " NOT NULL"
+	}
+	if c.useCurrent {
+		definition += " DEFAULT CURRENT_TIMESTAMP"
+	} else if c.hasDefault {
+		definition += " DEFAULT " + g.value(c.defaultValue)
+	}
+	if c.useCurrentOnUpdate && g.dialect == MySQL {
+		definition += " ON UPDATE CURRENT_TIMESTAMP"
+	}
+	return definition + g.after(c, adding)
+}
+
+// changeColumn - 修改欄位的子句；Postgres 的型別、NULL 與預設值需分別修改
+func (g grammar) changeColumn(c *Column) ([]string, error) {
+	if c.kind == incrementsType || c.kind == bigIncrementsType {
+		return nil, fmt.Errorf("不支援修改自動遞增欄位 %s", c.name)
+	}
+
+	switch g.dialect {
+	case MySQL:
+		return []string{"MODIFY COLUMN " + g.column(c, false)}, nil
+	case Postgres:
+		column := "ALTER COLUMN " + g.quote(c.name) + " "
+		clauses := []string{column + "TYPE " + g.columnType(c)}
+		if c.nullable {
+			clauses = append(clauses, column+"DROP NOT NULL")
+		} else {
+			clauses = append(clauses, column+"SET NOT NULL")
+		}
+		switch {
+		case c.useCurrent:
+			clauses = append(clauses, column+"SET DEFAULT CURRENT_TIMESTAMP")
+		case c.hasDefault:
+			clauses = append(clauses, column+"SET DEFAULT "+g.value(c.defaultValue))
+		default:
+			clauses = append(clauses, column+"DROP DEFAULT")
+		}
+		return clauses, nil
+	default:
+		return nil, fmt.Errorf("SQLite 不支援修改欄位 %s", c.name)
+	}
+}
+
+func (g grammar) after(c *Column, adding bool) string {
+	if !adding || c.after == "" || g.dialect != MySQL {
+		return ""
+	}
+	return " AFTER " + g.quote(c.after)
+}
+
+func (g grammar) columnType(c *Column) string {
+	switch c.kind {
+	case incrementsType:
+		return g.pick("INT", "SERIAL", "INTEGER")
+	case bigIncrementsType:
+		return g.pick("BIGINT", "BIGSERIAL", "INTEGER")
+	case stringType:
+		return fmt.Sprintf("VARCHAR(%d)", c.length)
+	case charType:
+		return fmt.Sprintf("CHAR(%d)", c.length)
+	case textType:
+		return "TEXT"
+	case longTextType:
+		return g.pick("LONGTEXT", "TEXT", "TEXT")
+	case integerType:
+		return g.pick("INT", "INTEGER", "INTEGER")
+	case bigIntegerType:
+		return g.pick("BIGINT", "BIGINT", "INTEGER")
+	case booleanType:
+		return "BOOLEAN"
+	case decimalType:
+		return fmt.Sprintf("DECIMAL(%d, %d)", c.precision, c.scale)
+	case dateType:
+		return "DATE"
+	case timestampType:
+		return g.pick("TIMESTAMP", "TIMESTAMPTZ", "DATETIME")
+	case jsonType:
+		return g.pick("JSON", "JSONB", "TEXT")
+	case uuidType:
+		return g.pick("CHAR(36)", "UUID", "VARCHAR(36)")
+	default:
+		return ""
+	}
+}
+
+// pick - 依資料庫類型選擇
+func (g grammar) pick(mysql, postgres, sqlite string) string {
+	switch g.dialect {
+	case MySQL:
+		return mysql
+	case Postgres:
+		return postgres
+	default:
+		return sqlite
+	}
+}
+
+// value - 預設值的 SQL 表示
+func (g grammar) value(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case Raw:
+		return string(v)
+	case bool:
+		if g.dialect == Postgres {
+			return strings.ToUpper(fmt.Sprint(v))
+		}
+		if v {
+			return "1"
+		}
+		return "0"
+	case string:
+		if g.dialect == MySQL {
+			v = strings.ReplaceAll(v, `\`, `\\`)
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		return g.value(fmt.Sprint(v))
+	}
+}
+
+// inlineIndex - MySQL 在 CREATE TABLE 或 ALTER TABLE ADD 中的索引
+func (g grammar) inlineIndex(b *Blueprint, i *Index) string {
+	keyword := "INDEX "
+	if i.unique {
+		keyword = "UNIQUE INDEX "
+	}
+	return keyword + g.quote(g.indexName(b, i)) + " (" + g.columnize(i.columns) + ")"
+}
+
+func (g grammar) createIndex(b *Blueprint, i *Index) string {
+	keyword := "CREATE INDEX IF NOT EXISTS "
+	if i.unique {
+		keyword = "CREATE UNIQUE INDEX IF NOT EXISTS "
+	}
+	return keyword + g.quote(g.indexName(b, i)) + " ON " + g.quote(b.table) + " (" + g.columnize(i.columns) + ")"
+}
+
+// indexName - 預設為 idx_資料表_欄位（Postgres、SQLite 的索引名稱整個 schema 共用，因此包含資料表名稱）
+func (g grammar) indexName(b *Blueprint, i *Index) string {
+	if i.name != "" {
+		return i.name
+	}
+	return "idx_" + b.table + "_" + strings.Join(i.columns, "_")
+}
+
+// foreignKeyName - 預設為 fk_資料表_參照的資料表，欄位不是 參照資料表單數_id 時為 fk_資料表_欄位
+func (g grammar) foreignKeyName(b *Blueprint, fk *ForeignKey) string {
+	if fk.name != "" {
+		return fk.name
+	}
+	if fk.column == inflection.Singular(fk.on)+"_id" {
+		return "fk_" + b.table + "_" + fk.on
+	}
+	return "fk_" + b.table + "_" + fk.column
+}
+
+func (g grammar) foreignKey(fk *ForeignKey) string {
+	return "FOREIGN KEY (" + g.quote(fk.column) + ") " + g.references(fk)
+}
+
+func (g grammar) references(fk *ForeignKey) string {
+	clause := "REFERENCES " + g.quote(fk.on) + " (" + g.quote(fk.references) + ")"
+	if fk.onDelete != "" {
+		clause += " ON DELETE " + fk.onDelete
+	}
+	if fk.onUpdate != "" {
+		clause += " ON UPDATE " + fk.onUpdate
+	}
+	return clause
+}
+
+func (g grammar) columnize(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = g.quote(column)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// quote - MySQL 使用反引號，Postgres、SQLite 使用雙引號
+func (g grammar) quote(identifier string) string {
+	if g.dialect == MySQL {
+		return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
@@ -0,0 +1,123 @@
+// Package schema - 以 Go 描述資料表結構，依資料庫類型產生 DDL（類似 Laravel 的 Schema Builder）
+//
+//	schema.Create("posts", func(t *schema.Blueprint) {
+//		t.ID()
+//		t.String("title", 255)
+//		t.ForeignID("user_id").Constrained().CascadeOnDelete()
+//		t.Timestamps()
+//		t.SoftDeletes()
+//	}).Run(db, schema.Dialect(dialect))
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Dialect - 產生 DDL 的資料庫類型，MySQL、Postgres 的值與 DB_TYPE、migrations.Dialect 相同
+// SQLite 只用於 ToSQL 產生 DDL，migrator 沒有 SQLite 驅動，不能以 DB_TYPE=sqlite 執行
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// Executor - 執行 DDL 的介面（*sql.DB、*sql.Tx 與 migrations.Executor 都符合）
+type Executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+type commandKind int
+
+const (
+	createCommand commandKind = iota
+	alterCommand
+	dropCommand
+	dropIfExistsCommand
+	renameCommand
+)
+
+// Command - 對單一資料表的操作（建立、修改、刪除、重新命名）
+type Command struct {
+	kind      commandKind
+	blueprint *Blueprint
+	to        string // Rename 的新名稱
+}
+
+// Create - 建立資料表（CREATE TABLE IF NOT EXISTS）
+func Create(table string, fn func(t *Blueprint)) *Command {
+	b := newBlueprint(table)
+	fn(b)
+	return &Command{kind: createCommand, blueprint: b}
+}
+
+// Table - 修改資料表：新增、刪除、重新命名欄位，新增或刪除索引與外鍵
+func Table(table string, fn func(t *Blueprint)) *Command {
+	b := newBlueprint(table)
+	fn(b)
+	return &Command{kind: alterCommand, blueprint: b}
+}
+
+// Drop - 刪除資料表
+func Drop(table string) *Command {
+	return &Command{kind: dropCommand, blueprint: newBlueprint(table)}
+}
+
+// DropIfExists - 資料表存在時刪除
+func DropIfExists(table string) *Command {
+	return &Command{kind: dropIfExistsCommand, blueprint: newBlueprint(table)}
+}
+
+// Rename - 重新命名資料表
+func Rename(from, to string) *Command {
+	return &Command{kind: renameCommand, blueprint: newBlueprint(from), to: to}
+}
+
+// ToSQL - 產生指定資料庫的 DDL，依序執行
+func (c *Command) ToSQL(dialect Dialect) ([]string, error) {
+	g, err := grammarFor(dialect)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.blueprint.errs) > 0 {
+		return nil, c.blueprint.errs[0]
+	}
+	for _, fk := range c.blueprint.foreignKeys {
+		if fk.on == "" {
+			return nil, fmt.Errorf("外鍵 %s 沒有指定參照的資料表，請使用 On", fk.column)
+		}
+	}
+
+	table := g.quote(c.blueprint.table)
+	switch c.kind {
+	case createCommand:
+		return g.compileCreate(c.blueprint)
+	case alterCommand:
+		return g.compileAlter(c.blueprint)
+	case dropCommand:
+		return []string{"DROP TABLE " + table}, nil
+	case dropIfExistsCommand:
+		return []string{"DROP TABLE IF EXISTS " + table}, nil
+	case renameCommand:
+		return []string{fmt.Sprintf("ALTER TABLE %s RENAME TO %s", table, g.quote(c.to))}, nil
+	default:
+		return nil, fmt.Errorf("不支援的 schema 操作: %d", c.kind)
+	}
+}
+
+// Run - 產生 DDL 後依序執行
+func (c *Command) Run(db Executor, dialect Dialect) error {
+	statements, err := c.ToSQL(dialect)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("%v（%s）", err, statement)
+		}
+	}
+	return nil
+}
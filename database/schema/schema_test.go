@@ -0,0 +1,273 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func createPosts() *Command {
+	return Create("posts", func(t *Blueprint) {
+		t.ID()
+		t.String("title", 255)
+		t.Text("content").Nullable()
+		t.Boolean("published").Default(false)
+		t.ForeignID("user_id").Constrained().CascadeOnDelete()
+		t.Timestamps()
+		t.SoftDeletes()
+	})
+}
+
+// TestCreate 測試建立資料表在各資料庫產生的 DDL
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		want    []string
+	}{
+		{
+			dialect: MySQL,
+			want: []string{"CREATE TABLE IF NOT EXISTS `posts` (\n" +
+				"    `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,\n" +
+				"    `title` VARCHAR(255) NOT NULL,\n" +
+				"    `content` TEXT NULL,\n" +
+				"    `published` BOOLEAN NOT NULL DEFAULT 0,\n" +
+				"    `user_id` BIGINT NOT NULL,\n" +
+				"    `created_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+				"    `updated_at` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+				"    `deleted_at` TIMESTAMP NULL,\n" +
+				"    INDEX `idx_posts_deleted_at` (`deleted_at`),\n" +
+				"    CONSTRAINT `fk_posts_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci"},
+		},
+		{
+			dialect: Postgres,
+			want: []string{
+				"CREATE TABLE IF NOT EXISTS \"posts\" (\n" +
+					"    \"id\" BIGSERIAL PRIMARY KEY,\n" +
+					"    \"title\" VARCHAR(255) NOT NULL,\n" +
+					"    \"content\" TEXT NULL,\n" +
+					"    \"published\" BOOLEAN NOT NULL DEFAULT FALSE,\n" +
+					"    \"user_id\" BIGINT NOT NULL,\n" +
+					"    \"created_at\" TIMESTAMPTZ NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+					"    \"updated_at\" TIMESTAMPTZ NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+					"    \"deleted_at\" TIMESTAMPTZ NULL,\n" +
+					"    CONSTRAINT \"fk_posts_users\" FOREIGN KEY (\"user_id\") REFERENCES \"users\" (\"id\") ON DELETE CASCADE\n" +
+					")",
+				`CREATE INDEX IF NOT EXISTS "idx_posts_deleted_at" ON "posts" ("deleted_at")`,
+			},
+		},
+		{
+			dialect: SQLite,
+			want: []string{
+				"CREATE TABLE IF NOT EXISTS \"posts\" (\n" +
+					"    \"id\" INTEGER PRIMARY KEY AUTOINCREMENT,\n" +
+					"    \"title\" VARCHAR(255) NOT NULL,\n" +
+					"    \"content\" TEXT NULL,\n" +
+					"    \"published\" BOOLEAN NOT NULL DEFAULT 0,\n" +
+					"    \"user_id\" INTEGER NOT NULL,\n" +
+					"    \"created_at\" DATETIME NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+					"    \"updated_at\" DATETIME NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+					"    \"deleted_at\" DATETIME NULL,\n" +
+					"    CONSTRAINT \"fk_posts_users\" FOREIGN KEY (\"user_id\") REFERENCES \"users\" (\"id\") ON DELETE CASCADE\n" +
+					")",
+				`CREATE INDEX IF NOT EXISTS "idx_posts_deleted_at" ON "posts" ("deleted_at")`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			got, err := createPosts().ToSQL(tt.dialect)
+			if err != nil {
+				t.Fatalf("ToSQL() 發生錯誤: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToSQL() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCreate_PivotTable 測試複合主鍵、唯一索引與指定參照資料表
+func TestCreate_PivotTable(t *testing.T) {
+	got, err := Create("role_permissions", func(t *Blueprint) {
+		t.ForeignID("role_id").Constrained().CascadeOnDelete()
+		t.ForeignID("granted_by").Nullable().Constrained("users").NullOnDelete()
+		t.String("scope", 50).Default("it's")
+		t.Primary("role_id", "scope")
+		t.Unique("role_id", "granted_by").Name("uniq_role_granter")
+	}).ToSQL(Postgres)
+	if err != nil {
+		t.Fatalf("ToSQL() 發生錯誤: %v", err)
+	}
+
+	want := []string{
+		"CREATE TABLE IF NOT EXISTS \"role_permissions\" (\n" +
+			"    \"role_id\" BIGINT NOT NULL,\n" +
+			"    \"granted_by\" BIGINT NULL,\n" +
+			"    \"scope\" VARCHAR(50) NOT NULL DEFAULT 'it''s',\n" +
+			"    PRIMARY KEY (\"role_id\", \"scope\"),\n" +
+			"    CONSTRAINT \"fk_role_permissions_roles\" FOREIGN KEY (\"role_id\") REFERENCES \"roles\" (\"id\") ON DELETE CASCADE,\n" +
+			"    CONSTRAINT \"fk_role_permissions_granted_by\" FOREIGN KEY (\"granted_by\") REFERENCES \"users\" (\"id\") ON DELETE SET NULL\n" +
+			")",
+		`CREATE UNIQUE INDEX IF NOT EXISTS "uniq_role_granter" ON "role_permissions" ("role_id", "granted_by")`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToSQL() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestTable 測試修改資料表在各資料庫產生的 DDL
+func TestTable(t *testing.T) {
+	alter := Table("users", func(t *Blueprint) {
+		t.DropIndex("idx_users_nickname")
+		t.DropColumn("nickname")
+		t.RenameColumn("name", "full_name")
+		t.String("phone", 20).Nullable().Unique().After("email")
+		t.ForeignID("team_id").Nullable().Constrained().NullOnDelete()
+	})
+
+	tests := []struct {
+		dialect Dialect
+		want    []string
+	}{
+		{
+			dialect: MySQL,
+			want: []string{
+				"ALTER TABLE `users` DROP INDEX `idx_users_nickname`",
+				"ALTER TABLE `users` DROP COLUMN `nickname`",
+				"ALTER TABLE `users` RENAME COLUMN `name` TO `full_name`",
+				"ALTER TABLE `users` ADD COLUMN `phone` VARCHAR(20) NULL AFTER `email`, ADD COLUMN `team_id` BIGINT NULL",
+				"ALTER TABLE `users` ADD UNIQUE INDEX `idx_users_phone` (`phone`)",
+				"ALTER TABLE `users` ADD CONSTRAINT `fk_users_teams` FOREIGN KEY (`team_id`) REFERENCES `teams` (`id`) ON DELETE SET NULL",
+			},
+		},
+		{
+			dialect: Postgres,
+			want: []string{
+				`DROP INDEX IF EXISTS "idx_users_nickname"`,
+				`ALTER TABLE "users" DROP COLUMN IF EXISTS "nickname"`,
+				`ALTER TABLE "users" RENAME COLUMN "name" TO "full_name"`,
+				`ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "phone" VARCHAR(20) NULL, ADD COLUMN IF NOT EXISTS "team_id" BIGINT NULL`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_phone" ON "users" ("phone")`,
+				`ALTER TABLE "users" ADD CONSTRAINT "fk_users_teams" FOREIGN KEY ("team_id") REFERENCES "teams" ("id") ON DELETE SET NULL`,
+			},
+		},
+		{
+			dialect: SQLite,
+			want: []string{
+				`DROP INDEX IF EXISTS "idx_users_nickname"`,
+				`ALTER TABLE "users" DROP COLUMN "nickname"`,
+				`ALTER TABLE "users" RENAME COLUMN "name" TO "full_name"`,
+				`ALTER TABLE "users" ADD COLUMN "phone" VARCHAR(20) NULL`,
+				`ALTER TABLE "users" ADD COLUMN "team_id" INTEGER NULL REFERENCES "teams" ("id") ON DELETE SET NULL`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_phone" ON "users" ("phone")`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			got, err := alter.ToSQL(tt.dialect)
+			if err != nil {
+				t.Fatalf("ToSQL() 發生錯誤: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToSQL() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTable_Change 測試修改既有欄位
+func TestTable_Change(t *testing.T) {
+	alter := Table("users", func(t *Blueprint) {
+		t.String("name", 100).Change()
+		t.Integer("age").Nullable().Default(0).Change()
+		t.String("phone", 20).Nullable()
+	})
+
+	tests := []struct {
+		dialect Dialect
+		want    []string
+	}{
+		{
+			dialect: MySQL,
+			want: []string{
+				"ALTER TABLE `users` MODIFY COLUMN `name` VARCHAR(100) NOT NULL, MODIFY COLUMN `age` INT NULL DEFAULT 0",
+				"ALTER TABLE `users` ADD COLUMN `phone` VARCHAR(20) NULL",
+			},
+		},
+		{
+			dialect: Postgres,
+			want: []string{
+				`ALTER TABLE "users" ALTER COLUMN "name" TYPE VARCHAR(100), ALTER COLUMN "name" SET NOT NULL, ALTER COLUMN "name" DROP DEFAULT, ` +
+					`ALTER COLUMN "age" TYPE INTEGER, ALTER COLUMN "age" DROP NOT NULL, ALTER COLUMN "age" SET DEFAULT 0`,
+				`ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "phone" VARCHAR(20) NULL`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			got, err := alter.ToSQL(tt.dialect)
+			if err != nil {
+				t.Fatalf("ToSQL() 發生錯誤: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToSQL() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDropAndRename 測試刪除與重新命名資料表
+func TestDropAndRename(t *testing.T) {
+	tests := []struct {
+		name    string
+		command *Command
+		dialect Dialect
+		want    string
+	}{
+		{"Drop", Drop("posts"), MySQL, "DROP TABLE `posts`"},
+		{"DropIfExists", DropIfExists("posts"), Postgres, `DROP TABLE IF EXISTS "posts"`},
+		{"Rename", Rename("posts", "articles"), SQLite, `ALTER TABLE "posts" RENAME TO "articles"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.command.ToSQL(tt.dialect)
+			if err != nil || !reflect.DeepEqual(got, []string{tt.want}) {
+				t.Errorf("ToSQL() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestToSQL_Invalid 測試無法產生 DDL 的情況
+func TestToSQL_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		command *Command
+		dialect Dialect
+	}{
+		{"不支援的資料庫", createPosts(), Dialect("oracle")},
+		{"無法推測參照的資料表", Create("posts", func(t *Blueprint) { t.BigInteger("author").Constrained() }), MySQL},
+		{"外鍵沒有指定資料表", Create("posts", func(t *Blueprint) { t.ID(); t.Foreign("author_id") }), MySQL},
+		{"沒有欄位", Create("posts", func(t *Blueprint) {}), MySQL},
+		{"建立時刪除欄位", Create("posts", func(t *Blueprint) { t.ID(); t.DropColumn("title") }), MySQL},
+		{"沒有變更", Table("posts", func(t *Blueprint) {}), Postgres},
+		{"SQLite 為既有欄位新增外鍵", Table("posts", func(t *Blueprint) { t.Foreign("user_id").On("users") }), SQLite},
+		{"SQLite 刪除外鍵", Table("posts", func(t *Blueprint) { t.DropForeign("fk_posts_users") }), SQLite},
+		{"SQLite 修改欄位", Table("posts", func(t *Blueprint) { t.String("title", 100).Change() }), SQLite},
+		{"建立時修改欄位", Create("posts", func(t *Blueprint) { t.String("title", 100).Change() }), MySQL},
+		{"修改自動遞增欄位", Table("posts", func(t *Blueprint) { t.ID().Change() }), MySQL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.command.ToSQL(tt.dialect); err == nil {
+				t.Error("應回傳錯誤")
+			}
+		})
+	}
+}
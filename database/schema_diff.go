@@ -0,0 +1,628 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	gormschema "gorm.io/gorm/schema"
+	"my-api/app/models"
+	"my-api/database/migrations"
+)
+
+// Models - schema:diff 比對的 GORM models，新增 model 時也要加到這裡
+// many2many 的樞紐表（user_roles、role_permissions）沒有對應的 model，不會比對
+var Models = []interface{}{
+	&models.User{},
+	&models.Post{},
+	&models.Role{},
+	&models.Permission{},
+	&models.RefreshToken{},
+	&models.PasswordResetToken{},
+	&models.PersonalAccessToken{},
+	&models.Session{},
+	&models.LoginHistory{},
+	&models.Identity{},
+}
+
+// TableDefinition - 資料表結構，來自 GORM model 或資料庫
+type TableDefinition struct {
+	Name        string
+	Columns     []ColumnDefinition
+	Indexes     []IndexDefinition
+	ForeignKeys []ForeignKeyDefinition
+}
+
+// ColumnDefinition - 欄位定義
+type ColumnDefinition struct {
+	Name          string
+	Type          string // 正規化的型別，例如 varchar(100)、bigint、timestamp
+	Nullable      bool
+	PrimaryKey    bool
+	AutoIncrement bool
+	Default       string // model 的 default tag，資料庫的欄位不讀取
+	// Inferred - model 沒有以 type tag 指定型別，Type 由 Go 型別推測，只比對型別類別（例如 int 與 bigint 視為相同）
+	Inferred bool
+}
+
+// IndexDefinition - 索引定義（不含主鍵）
+type IndexDefinition struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// ForeignKeyDefinition - 外鍵定義
+type ForeignKeyDefinition struct {
+	Name              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+	OnDelete          string // model 沒有指定時為空字串，不比對
+}
+
+// DiffKind - 差異類型
+type DiffKind string
+
+const (
+	DiffMissingTable      DiffKind = "missing_table"       // 資料庫沒有 model 的資料表
+	DiffMissingColumn     DiffKind = "missing_column"      // 資料庫缺少 model 的欄位
+	DiffColumnChanged     DiffKind = "column_changed"      // 欄位型別或 NULL 不同
+	DiffExtraColumn       DiffKind = "extra_column"        // 資料庫多出 model 沒有的欄位
+	DiffMissingIndex      DiffKind = "missing_index"       // 資料庫缺少 model 的索引
+	DiffIndexChanged      DiffKind = "index_changed"       // 相同欄位的索引，是否唯一不同
+	DiffExtraIndex        DiffKind = "extra_index"         // 資料庫多出 model 沒有的索引
+	DiffMissingForeignKey DiffKind = "missing_foreign_key" // 資料庫缺少 model 關聯的外鍵
+	DiffForeignKeyChanged DiffKind = "foreign_key_changed" // 外鍵的 ON DELETE 與 model 的 constraint tag 不同
+	DiffExtraForeignKey   DiffKind = "extra_foreign_key"   // 資料庫多出 model 沒有宣告關聯的外鍵
+)
+
+// Difference - model 與資料庫的一項差異
+type Difference struct {
+	Kind     DiffKind `json:"kind"`
+	Name     string   `json:"name"`               // 欄位、索引或外鍵名稱
+	Expected string   `json:"expected,omitempty"` // model 的定義
+	Actual   string   `json:"actual,omitempty"`   // 資料庫的定義
+
+	// 產生 migration 使用，依 Kind 填入 model 與資料庫的定義
+	ModelColumn        *ColumnDefinition     `json:"-"`
+	DatabaseColumn     *ColumnDefinition     `json:"-"`
+	ModelIndex         *IndexDefinition      `json:"-"`
+	DatabaseIndex      *IndexDefinition      `json:"-"`
+	ModelForeignKey    *ForeignKeyDefinition `json:"-"`
+	DatabaseForeignKey *ForeignKeyDefinition `json:"-"`
+}
+
+// Extra - 資料庫多出 model 沒有的欄位、索引或外鍵
+// model 不一定會宣告所有的結構（例如沒有關聯欄位的外鍵），因此只列出，不視為需要修正的差異
+func (d Difference) Extra() bool {
+	return d.Kind == DiffExtraColumn || d.Kind == DiffExtraIndex || d.Kind == DiffExtraForeignKey
+}
+
+// TableDiff - 一個 model 的資料表與資料庫的差異
+type TableDiff struct {
+	Table       string          `json:"table"`
+	Model       string          `json:"model"`
+	Differences []Difference    `json:"differences"`
+	Definition  TableDefinition `json:"-"` // model 的資料表結構
+}
+
+// DiffSchema 比對 GORM models 與資料庫的結構（欄位型別、NULL、索引與外鍵），依 models 的順序回傳
+func DiffSchema(models ...interface{}) ([]TableDiff, error) {
+	m, err := newMigrator()
+	if err != nil {
+		return nil, err
+	}
+	defer m.db.Close()
+
+	cache := &sync.Map{}
+	var diffs []TableDiff
+	for _, model := range models {
+		expected, err := parseModel(model, cache)
+		if err != nil {
+			return nil, err
+		}
+
+		diff := TableDiff{
+			Table:      expected.Name,
+			Model:      reflect.Indirect(reflect.ValueOf(model)).Type().String(),
+			Definition: expected,
+		}
+
+		exists, err := m.hasTable(expected.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			diff.Differences = []Difference{{Kind: DiffMissingTable, Name: expected.Name}}
+			diffs = append(diffs, diff)
+			continue
+		}
+
+		actual, err := m.tableDefinition(expected.Name)
+		if err != nil {
+			return nil, fmt.Errorf("無法讀取 %s 表的結構: %v", expected.Name, err)
+		}
+
+		diff.Differences = compareTables(expected, actual)
+		diffs = append(diffs, diff)
+	}
+
+	return diffs, nil
+}
+
+// parseModel - 以 GORM 解析 model 的資料表結構，與 AutoMigrate 使用的定義相同
+func parseModel(model interface{}, cache *sync.Map) (TableDefinition, error) {
+	s, err := gormschema.Parse(model, cache, gormschema.NamingStrategy{})
+	if err != nil {
+		return TableDefinition{}, fmt.Errorf("無法解析 model %T: %v", model, err)
+	}
+
+	table := TableDefinition{Name: s.Table}
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+
+		column := ColumnDefinition{
+			Name:          field.DBName,
+			Nullable:      !field.NotNull && !field.PrimaryKey,
+			PrimaryKey:    field.PrimaryKey,
+			AutoIncrement: field.AutoIncrement,
+		}
+		if field.HasDefaultValue && field.DefaultValue != "" {
+			column.Default = field.DefaultValue
+		}
+
+		switch {
+		case field.TagSettings["TYPE"] != "":
+			column.Type = normalizeType(field.TagSettings["TYPE"])
+		case field.DataType == gormschema.String && field.TagSettings["SIZE"] != "":
+			column.Type = fmt.Sprintf("varchar(%s)", field.TagSettings["SIZE"])
+		default:
+			column.Type, column.Inferred = inferType(field), true
+		}
+
+		table.Columns = append(table.Columns, column)
+
+		// unique tag 建立的唯一限制（uniqueIndex 由 ParseIndexes 處理）
+		if field.Unique && !field.PrimaryKey {
+			table.Indexes = append(table.Indexes, IndexDefinition{
+				Name:    gormschema.NamingStrategy{}.UniqueName(s.Table, field.DBName),
+				Columns: []string{field.DBName},
+				Unique:  true,
+			})
+		}
+	}
+
+	for _, index := range s.ParseIndexes() {
+		definition := IndexDefinition{Name: index.Name, Unique: index.Class == "UNIQUE"}
+		for _, option := range index.Fields {
+			definition.Columns = append(definition.Columns, option.DBName)
+		}
+		table.Indexes = append(table.Indexes, definition)
+	}
+
+	// 與 AutoMigrate 相同，只比對外鍵在這個資料表上的關聯（例如 belongs to）
+	for _, rel := range s.Relationships.Relations {
+		if rel.Field.IgnoreMigration {
+			continue
+		}
+		constraint := rel.ParseConstraint()
+		if constraint == nil || constraint.Schema != s || constraint.ReferenceSchema == nil {
+			continue
+		}
+
+		fk := ForeignKeyDefinition{
+			Name:            constraint.Name,
+			ReferencedTable: constraint.ReferenceSchema.Table,
+			OnDelete:        strings.ToUpper(constraint.OnDelete),
+		}
+		for i := range constraint.ForeignKeys {
+			fk.Columns = append(fk.Columns, constraint.ForeignKeys[i].DBName)
+			fk.ReferencedColumns = append(fk.ReferencedColumns, constraint.References[i].DBName)
+		}
+		table.ForeignKeys = append(table.ForeignKeys, fk)
+	}
+
+	return table, nil
+}
+
+// inferType - model 沒有指定 type 時，由 Go 型別推測的型別
+func inferType(field *gormschema.Field) string {
+	switch field.DataType {
+	case gormschema.Bool:
+		return "boolean"
+	case gormschema.Int, gormschema.Uint:
+		if field.Size > 0 && field.Size <= 32 {
+			return "int"
+		}
+		return "bigint"
+	case gormschema.Float:
+		return "double"
+	case gormschema.String:
+		return "text"
+	case gormschema.Time:
+		return "timestamp"
+	case gormschema.Bytes:
+		return "blob"
+	default:
+		return normalizeType(string(field.DataType))
+	}
+}
+
+var (
+	// 整數的顯示寬度，例如 MySQL 5.7 的 int(11)、bigint(20)
+	displayWidthPattern = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+	// 時間的小數秒精度，例如 datetime(3)、timestamp(6)
+	fractionalPattern = regexp.MustCompile(`^(datetime|timestamp|timestamptz|time)\(\d+\)`)
+	typeNamePattern   = regexp.MustCompile(`^[a-z]+`)
+)
+
+// normalizeType - 將 MySQL、Postgres 的型別名稱轉為相同的寫法，例如 character varying(255) → varchar(255)
+func normalizeType(t string) string {
+	t = strings.Join(strings.Fields(strings.ToLower(t)), " ")
+	t = strings.ReplaceAll(t, ", ", ",")
+
+	for _, alias := range [][2]string{
+		{"character varying", "varchar"},
+		{"character", "char"},
+		{"timestamp with time zone", "timestamptz"},
+		{"timestamp without time zone", "timestamp"},
+		{"time with time zone", "timetz"},
+		{"time without time zone", "time"},
+		{"double precision", "double"},
+		{"numeric", "decimal"},
+		{"integer", "int"},
+		{"int4", "int"},
+		{"int8", "bigint"},
+		{"int2", "smallint"},
+		{"bool", "boolean"},
+	} {
+		if rest, ok := strings.CutPrefix(t, alias[0]); ok && (rest == "" || rest[0] == '(' || rest[0] == ' ') {
+			t = alias[1] + rest
+			break
+		}
+	}
+
+	if strings.HasPrefix(t, "tinyint(1)") {
+		return "boolean"
+	}
+	t = displayWidthPattern.ReplaceAllString(t, "$1")
+	return fractionalPattern.ReplaceAllString(t, "$1")
+}
+
+// typeFamily - 型別類別，model 沒有指定 type 時只比對類別
+func typeFamily(t string) string {
+	switch name := typeNamePattern.FindString(t); name {
+	case "tinyint", "smallint", "mediumint", "int", "bigint", "serial", "bigserial":
+		return "integer"
+	case "varchar", "char", "text", "tinytext", "mediumtext", "longtext":
+		return "string"
+	case "decimal", "float", "double", "real":
+		return "numeric"
+	case "date", "datetime", "timestamp", "timestamptz":
+		return "time"
+	case "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "bytea":
+		return "binary"
+	default:
+		return name
+	}
+}
+
+// compareTables - 比對 model（expected）與資料庫（actual）的資料表結構
+func compareTables(expected, actual TableDefinition) []Difference {
+	var diffs []Difference
+	diffs = append(diffs, compareColumns(expected.Columns, actual.Columns)...)
+	diffs = append(diffs, compareIndexes(expected.Indexes, actual.Indexes)...)
+	diffs = append(diffs, compareForeignKeys(expected.ForeignKeys, actual.ForeignKeys)...)
+	return diffs
+}
+
+func compareColumns(expected, actual []ColumnDefinition) []Difference {
+	var diffs []Difference
+	matched := make(map[string]bool)
+
+	for i := range expected {
+		want := &expected[i]
+		j := slices.IndexFunc(actual, func(c ColumnDefinition) bool { return c.Name == want.Name })
+		if j < 0 {
+			diffs = append(diffs, Difference{Kind: DiffMissingColumn, Name: want.Name, Expected: describeColumn(*want), ModelColumn: want})
+			continue
+		}
+
+		got := &actual[j]
+		matched[got.Name] = true
+		if columnTypeMatches(*want, *got) && want.Nullable == got.Nullable {
+			continue
+		}
+
+		model := want
+		if want.Inferred && columnTypeMatches(*want, *got) {
+			// 只有 NULL 不同，產生 migration 時沿用資料庫的型別（例如 int 不會改為推測的 bigint）
+			c := *want
+			c.Type = got.Type
+			model = &c
+		}
+		diffs = append(diffs, Difference{
+			Kind:           DiffColumnChanged,
+			Name:           want.Name,
+			Expected:       describeColumn(*want),
+			Actual:         describeColumn(*got),
+			ModelColumn:    model,
+			DatabaseColumn: got,
+		})
+	}
+
+	for i := range actual {
+		if !matched[actual[i].Name] {
+			diffs = append(diffs, Difference{Kind: DiffExtraColumn, Name: actual[i].Name, Actual: describeColumn(actual[i]), DatabaseColumn: &actual[i]})
+		}
+	}
+
+	return diffs
+}
+
+// columnTypeMatches - model 以 type tag 指定型別時需完全相同，否則只比對型別類別
+func columnTypeMatches(want, got ColumnDefinition) bool {
+	if want.Inferred {
+		return typeFamily(want.Type) == typeFamily(got.Type)
+	}
+	return want.Type == got.Type
+}
+
+// compareIndexes - 以欄位比對索引（名稱常因建立方式不同，例如 UNIQUE 限制的名稱由資料庫決定）
+func compareIndexes(expected, actual []IndexDefinition) []Difference {
+	var diffs []Difference
+	matched := make(map[int]bool)
+
+	// 相同欄位可能有多個索引（例如唯一索引與一般索引），每個索引只配對一次
+	find := func(want IndexDefinition, sameUnique bool) int {
+		for i, index := range actual {
+			if !matched[i] && slices.Equal(index.Columns, want.Columns) && (!sameUnique || index.Unique == want.Unique) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for i := range expected {
+		want := &expected[i]
+		if j := find(*want, true); j >= 0 {
+			matched[j] = true
+			continue
+		}
+		if j := find(*want, false); j >= 0 {
+			matched[j] = true
+			diffs = append(diffs, Difference{
+				Kind:          DiffIndexChanged,
+				Name:          want.Name,
+				Expected:      describeIndex(*want),
+				Actual:        describeIndex(actual[j]),
+				ModelIndex:    want,
+				DatabaseIndex: &actual[j],
+			})
+			continue
+		}
+		diffs = append(diffs, Difference{Kind: DiffMissingIndex, Name: want.Name, Expected: describeIndex(*want), ModelIndex: want})
+	}
+
+	for i := range actual {
+		if !matched[i] {
+			diffs = append(diffs, Difference{Kind: DiffExtraIndex, Name: actual[i].Name, Actual: describeIndex(actual[i]), DatabaseIndex: &actual[i]})
+		}
+	}
+
+	return diffs
+}
+
+// compareForeignKeys - 以欄位與參照的資料表比對外鍵，model 有指定 OnDelete 時一併比對
+func compareForeignKeys(expected, actual []ForeignKeyDefinition) []Difference {
+	var diffs []Difference
+	matched := make(map[int]bool)
+
+	for i := range expected {
+		want := &expected[i]
+		j := slices.IndexFunc(actual, func(fk ForeignKeyDefinition) bool {
+			return slices.Equal(fk.Columns, want.Columns) && fk.ReferencedTable == want.ReferencedTable && slices.Equal(fk.ReferencedColumns, want.ReferencedColumns)
+		})
+		if j < 0 {
+			diffs = append(diffs, Difference{Kind: DiffMissingForeignKey, Name: want.Name, Expected: describeForeignKey(*want), ModelForeignKey: want})
+			continue
+		}
+
+		matched[j] = true
+		if want.OnDelete != "" && want.OnDelete != actual[j].OnDelete {
+			diffs = append(diffs, Difference{
+				Kind:               DiffForeignKeyChanged,
+				Name:               actual[j].Name,
+				Expected:           describeForeignKey(*want),
+				Actual:             describeForeignKey(actual[j]),
+				ModelForeignKey:    want,
+				DatabaseForeignKey: &actual[j],
+			})
+		}
+	}
+
+	for i := range actual {
+		if !matched[i] {
+			diffs = append(diffs, Difference{Kind: DiffExtraForeignKey, Name: actual[i].Name, Actual: describeForeignKey(actual[i]), DatabaseForeignKey: &actual[i]})
+		}
+	}
+
+	return diffs
+}
+
+// describeColumn - 例如 varchar(100) NOT NULL
+func describeColumn(c ColumnDefinition) string {
+	description := c.Type
+	if c.Inferred {
+		description += "（未指定 type）"
+	}
+	if c.Nullable {
+		return description + " NULL"
+	}
+	return description + " NOT NULL"
+}
+
+// describeIndex - 例如 UNIQUE (provider, subject)
+func describeIndex(i IndexDefinition) string {
+	description := "(" + strings.Join(i.Columns, ", ") + ")"
+	if i.Unique {
+		return "UNIQUE " + description
+	}
+	return description
+}
+
+// describeForeignKey - 例如 (user_id) → users (id) ON DELETE CASCADE
+func describeForeignKey(fk ForeignKeyDefinition) string {
+	description := fmt.Sprintf("(%s) → %s (%s)", strings.Join(fk.Columns, ", "), fk.ReferencedTable, strings.Join(fk.ReferencedColumns, ", "))
+	if fk.OnDelete != "" {
+		description += " ON DELETE " + fk.OnDelete
+	}
+	return description
+}
+
+// tableDefinition - 讀取資料庫中資料表的欄位、索引（不含主鍵）與外鍵
+func (m *migrator) tableDefinition(table string) (TableDefinition, error) {
+	definition := TableDefinition{Name: table}
+
+	columns, err := m.introspect(table, migrations.SQL{
+		migrations.MySQL: `
+			SELECT column_name, column_type, is_nullable = 'YES', column_key = 'PRI', extra LIKE '%auto_increment%'
+			FROM information_schema.columns
+			WHERE table_schema = DATABASE() AND table_name = ?
+			ORDER BY ordinal_position`,
+		migrations.Postgres: `
+			SELECT a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull,
+				COALESCE(a.attnum = ANY(p.conkey), FALSE), a.attidentity <> '' OR COALESCE(pg_get_expr(d.adbin, d.adrelid), '') LIKE 'nextval(%'
+			FROM pg_attribute a
+			JOIN pg_class t ON t.oid = a.attrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			LEFT JOIN pg_constraint p ON p.conrelid = t.oid AND p.contype = 'p'
+			LEFT JOIN pg_attrdef d ON d.adrelid = t.oid AND d.adnum = a.attnum
+			WHERE n.nspname = current_schema() AND t.relname = ? AND a.attnum > 0 AND NOT a.attisdropped
+			ORDER BY a.attnum`,
+	}, 5)
+	if err != nil {
+		return definition, err
+	}
+	for _, row := range columns {
+		definition.Columns = append(definition.Columns, ColumnDefinition{
+			Name:          row[0],
+			Type:          normalizeType(row[1]),
+			Nullable:      parseBool(row[2]),
+			PrimaryKey:    parseBool(row[3]),
+			AutoIncrement: parseBool(row[4]),
+		})
+	}
+
+	indexes, err := m.introspect(table, migrations.SQL{
+		migrations.MySQL: `
+			SELECT index_name, column_name, non_unique = 0
+			FROM information_schema.statistics
+			WHERE table_schema = DATABASE() AND table_name = ? AND index_name <> 'PRIMARY'
+			ORDER BY index_name, seq_in_index`,
+		migrations.Postgres: `
+			SELECT i.relname, a.attname, ix.indisunique
+			FROM pg_index ix
+			JOIN pg_class t ON t.oid = ix.indrelid
+			JOIN pg_class i ON i.oid = ix.indexrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			CROSS JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord)
+			JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+			WHERE n.nspname = current_schema() AND t.relname = ? AND NOT ix.indisprimary
+			ORDER BY i.relname, k.ord`,
+	}, 3)
+	if err != nil {
+		return definition, err
+	}
+	for _, row := range indexes {
+		if n := len(definition.Indexes); n > 0 && definition.Indexes[n-1].Name == row[0] {
+			definition.Indexes[n-1].Columns = append(definition.Indexes[n-1].Columns, row[1])
+			continue
+		}
+		definition.Indexes = append(definition.Indexes, IndexDefinition{Name: row[0], Columns: []string{row[1]}, Unique: parseBool(row[2])})
+	}
+
+	foreignKeys, err := m.introspect(table, migrations.SQL{
+		migrations.MySQL: `
+			SELECT k.constraint_name, k.column_name, k.referenced_table_name, k.referenced_column_name, r.delete_rule
+			FROM information_schema.key_column_usage k
+			JOIN information_schema.referential_constraints r
+				ON r.constraint_schema = k.constraint_schema AND r.constraint_name = k.constraint_name
+			WHERE k.table_schema = DATABASE() AND k.table_name = ? AND k.referenced_table_name IS NOT NULL
+			ORDER BY k.constraint_name, k.ordinal_position`,
+		migrations.Postgres: `
+			SELECT c.conname, a.attname, rt.relname, ra.attname,
+				CASE c.confdeltype WHEN 'c' THEN 'CASCADE' WHEN 'n' THEN 'SET NULL' WHEN 'r' THEN 'RESTRICT' WHEN 'd' THEN 'SET DEFAULT' ELSE 'NO ACTION' END
+			FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN pg_class rt ON rt.oid = c.confrelid
+			CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+			JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+			JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.refattnum
+			WHERE c.contype = 'f' AND n.nspname = current_schema() AND t.relname = ?
+			ORDER BY c.conname, k.ord`,
+	}, 5)
+	if err != nil {
+		return definition, err
+	}
+	for _, row := range foreignKeys {
+		if n := len(definition.ForeignKeys); n > 0 && definition.ForeignKeys[n-1].Name == row[0] {
+			fk := &definition.ForeignKeys[n-1]
+			fk.Columns = append(fk.Columns, row[1])
+			fk.ReferencedColumns = append(fk.ReferencedColumns, row[3])
+			continue
+		}
+		definition.ForeignKeys = append(definition.ForeignKeys, ForeignKeyDefinition{
+			Name:              row[0],
+			Columns:           []string{row[1]},
+			ReferencedTable:   row[2],
+			ReferencedColumns: []string{row[3]},
+			OnDelete:          row[4],
+		})
+	}
+
+	return definition, nil
+}
+
+// introspect - 以資料表名稱為參數執行查詢，每一列以字串讀取 n 個欄位
+func (m *migrator) introspect(table string, queries migrations.SQL, n int) ([][]string, error) {
+	query, err := queries.For(m.dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query(m.dialect.Rebind(query), table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result [][]string
+	for rows.Next() {
+		values := make([]string, n)
+		dest := make([]interface{}, n)
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result = append(result, values)
+	}
+
+	return result, rows.Err()
+}
+
+// parseBool - 讀取查詢中比較運算的結果（MySQL 為 0/1，Postgres 為 true/false）
+func parseBool(s string) bool {
+	b, err := strconv.ParseBool(s)
+	return err == nil && b
+}
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"my-api/app/models"
+)
+
+// TestNormalizeType 測試 MySQL、Postgres 的型別名稱正規化
+func TestNormalizeType(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"VARCHAR(100)", "varchar(100)"},
+		{"character varying(255)", "varchar(255)"},
+		{"character(64)", "char(64)"},
+		{"integer", "int"},
+		{"int(11)", "int"},
+		{"bigint(20) unsigned", "bigint unsigned"},
+		{"tinyint(1)", "boolean"},
+		{"boolean", "boolean"},
+		{"timestamp with time zone", "timestamptz"},
+		{"timestamp without time zone", "timestamp"},
+		{"datetime(3)", "datetime"},
+		{"numeric(10,2)", "decimal(10,2)"},
+		{"DECIMAL(10, 2)", "decimal(10,2)"},
+		{"double precision", "double"},
+		{"longtext", "longtext"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := normalizeType(tt.input); got != tt.want {
+				t.Errorf("normalizeType(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseModel 測試從 GORM model 解析資料表結構
+func TestParseModel(t *testing.T) {
+	cache := &sync.Map{}
+
+	users, err := parseModel(&models.User{}, cache)
+	if err != nil {
+		t.Fatalf("parseModel() 發生錯誤: %v", err)
+	}
+	if users.Name != "users" {
+		t.Errorf("Name = %q, want users", users.Name)
+	}
+
+	columns := make(map[string]ColumnDefinition)
+	for _, c := range users.Columns {
+		columns[c.Name] = c
+	}
+	if _, ok := columns["roles"]; ok {
+		t.Error("關聯欄位 Roles 不應該是資料表欄位")
+	}
+
+	columnTests := []struct {
+		name string
+		want ColumnDefinition
+	}{
+		{"id", ColumnDefinition{Name: "id", Type: "bigint", PrimaryKey: true, AutoIncrement: true, Inferred: true}},
+		{"name", ColumnDefinition{Name: "name", Type: "varchar(100)"}},
+		{"email_verified_at", ColumnDefinition{Name: "email_verified_at", Type: "timestamp", Nullable: true, Inferred: true}},
+		{"two_factor_last_step", ColumnDefinition{Name: "two_factor_last_step", Type: "bigint", Default: "0", Inferred: true}},
+	}
+	for _, tt := range columnTests {
+		if got := columns[tt.name]; got != tt.want {
+			t.Errorf("欄位 %s = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	wantIndexes := []IndexDefinition{
+		{Name: "idx_users_deleted_at", Columns: []string{"deleted_at"}},
+		{Name: "idx_users_email", Columns: []string{"email"}, Unique: true},
+		{Name: "idx_users_deletion_scheduled_at", Columns: []string{"deletion_scheduled_at"}},
+	}
+	if !reflect.DeepEqual(users.Indexes, wantIndexes) {
+		t.Errorf("Indexes = %+v, want %+v", users.Indexes, wantIndexes)
+	}
+
+	// many2many 的外鍵在樞紐表上，不屬於 users
+	if len(users.ForeignKeys) != 0 {
+		t.Errorf("ForeignKeys = %+v, want 無", users.ForeignKeys)
+	}
+
+	posts, err := parseModel(&models.Post{}, cache)
+	if err != nil {
+		t.Fatalf("parseModel() 發生錯誤: %v", err)
+	}
+	wantForeignKeys := []ForeignKeyDefinition{
+		{Name: "fk_posts_user", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+	}
+	if !reflect.DeepEqual(posts.ForeignKeys, wantForeignKeys) {
+		t.Errorf("ForeignKeys = %+v, want %+v", posts.ForeignKeys, wantForeignKeys)
+	}
+
+	identities, err := parseModel(&models.Identity{}, cache)
+	if err != nil {
+		t.Fatalf("parseModel() 發生錯誤: %v", err)
+	}
+	composite := IndexDefinition{Name: "idx_identities_provider_subject", Columns: []string{"provider", "subject"}, Unique: true}
+	if !reflect.DeepEqual(identities.Indexes[len(identities.Indexes)-1], composite) {
+		t.Errorf("複合索引 = %+v, want %+v", identities.Indexes, composite)
+	}
+}
+
+// TestCompareTables 測試比對 users 的 model 與 000001_create_users_table 建立的 MySQL 資料表
+func TestCompareTables(t *testing.T) {
+	expected, err := parseModel(&models.User{}, &sync.Map{})
+	if err != nil {
+		t.Fatalf("parseModel() 發生錯誤: %v", err)
+	}
+	// 只比對部分欄位
+	expected.Columns = expected.Columns[:6]
+
+	actual := TableDefinition{
+		Name: "users",
+		Columns: []ColumnDefinition{
+			{Name: "id", Type: "bigint", PrimaryKey: true, AutoIncrement: true},
+			{Name: "name", Type: "varchar(255)"},
+			{Name: "email", Type: "varchar(255)"},
+			{Name: "age", Type: "int", Nullable: true},
+			{Name: "created_at", Type: "timestamp", Nullable: true},
+			{Name: "updated_at", Type: "timestamp", Nullable: true},
+			{Name: "deleted_at", Type: "timestamp", Nullable: true},
+		},
+		Indexes: []IndexDefinition{
+			{Name: "email", Columns: []string{"email"}, Unique: true},
+			{Name: "idx_email", Columns: []string{"email"}},
+			{Name: "idx_deleted_at", Columns: []string{"deleted_at"}},
+		},
+		ForeignKeys: []ForeignKeyDefinition{
+			{Name: "fk_users_teams", Columns: []string{"team_id"}, ReferencedTable: "teams", ReferencedColumns: []string{"id"}, OnDelete: "CASCADE"},
+		},
+	}
+
+	type result struct {
+		Kind     DiffKind
+		Name     string
+		Expected string
+		Actual   string
+	}
+	want := []result{
+		{DiffColumnChanged, "name", "varchar(100) NOT NULL", "varchar(255) NOT NULL"},
+		{DiffColumnChanged, "email", "varchar(100) NOT NULL", "varchar(255) NOT NULL"},
+		{DiffExtraColumn, "age", "", "int NULL"},
+		{DiffMissingIndex, "idx_users_deletion_scheduled_at", "(deletion_scheduled_at)", ""},
+		{DiffExtraIndex, "idx_email", "", "(email)"},
+		{DiffExtraForeignKey, "fk_users_teams", "", "(team_id) → teams (id) ON DELETE CASCADE"},
+	}
+
+	var got []result
+	for _, d := range compareTables(expected, actual) {
+		got = append(got, result{d.Kind, d.Name, d.Expected, d.Actual})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("compareTables() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// TestCompareColumns 測試欄位型別的比對規則
+func TestCompareColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   ColumnDefinition
+		column  ColumnDefinition
+		changed bool
+	}{
+		{"指定 type 時需完全相同", ColumnDefinition{Name: "c", Type: "char(64)"}, ColumnDefinition{Name: "c", Type: "char(64)"}, false},
+		{"長度不同", ColumnDefinition{Name: "c", Type: "varchar(100)"}, ColumnDefinition{Name: "c", Type: "varchar(255)"}, true},
+		{"未指定 type 時只比對類別", ColumnDefinition{Name: "c", Type: "bigint", Inferred: true}, ColumnDefinition{Name: "c", Type: "int"}, false},
+		{"未指定 type 的字串", ColumnDefinition{Name: "c", Type: "text", Inferred: true}, ColumnDefinition{Name: "c", Type: "longtext"}, false},
+		{"未指定 type 但類別不同", ColumnDefinition{Name: "c", Type: "timestamp", Inferred: true}, ColumnDefinition{Name: "c", Type: "varchar(20)"}, true},
+		{"NULL 不同", ColumnDefinition{Name: "c", Type: "timestamp", Inferred: true}, ColumnDefinition{Name: "c", Type: "timestamptz", Nullable: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs := compareColumns([]ColumnDefinition{tt.model}, []ColumnDefinition{tt.column})
+			if changed := len(diffs) > 0; changed != tt.changed {
+				t.Errorf("compareColumns() = %+v, want changed = %v", diffs, tt.changed)
+			}
+		})
+	}
+}
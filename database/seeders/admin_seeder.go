@@ -0,0 +1,75 @@
+package seeders
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+	"my-api/app/models"
+	"my-api/app/pkg/password"
+	"my-api/database/factories"
+)
+
+// AdminSeeder - 指定 ADMIN_EMAIL 為系統管理員，用於全新部署建立第一位管理員
+// 使用者已存在時加上 admin 角色；不存在時以 ADMIN_PASSWORD 建立已驗證 Email 的管理員帳號
+// 註冊與 OIDC 登入只會指派 user 角色，管理員一律透過這裡或 RoleService 指派
+type AdminSeeder struct{}
+
+func init() {
+	Register(&AdminSeeder{})
+}
+
+func (s *AdminSeeder) Name() string {
+	return "AdminSeeder"
+}
+
+func (s *AdminSeeder) Run(db *gorm.DB) error {
+	email := strings.ToLower(strings.TrimSpace(os.Getenv("ADMIN_EMAIL")))
+	if email == "" {
+		return errors.New("請設定 ADMIN_EMAIL")
+	}
+
+	var admin models.Role
+	if err := db.Where("name = ?", models.RoleAdmin).First(&admin).Error; err != nil {
+		return fmt.Errorf("找不到 admin 角色，請先執行 migrate: %v", err)
+	}
+
+	var user models.User
+	err := db.Where("email = ?", email).First(&user).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return createAdmin(db, email, os.Getenv("ADMIN_PASSWORD"))
+	case err != nil:
+		return err
+	}
+
+	// 已經有的角色不會重複寫入
+	if err := db.Model(&user).Association("Roles").Append(&admin); err != nil {
+		return err
+	}
+	fmt.Printf("  ✓ %s 已設為管理員\n", email)
+	return nil
+}
+
+// createAdmin - 建立管理員帳號，密碼需符合密碼規則
+func createAdmin(db *gorm.DB, email, plain string) error {
+	if plain == "" {
+		return fmt.Errorf("使用者 %s 不存在，建立管理員帳號需要設定 ADMIN_PASSWORD", email)
+	}
+	name := "系統管理員"
+	if err := password.Default().Validate(plain, password.User{Name: name, Email: email}); err != nil {
+		return fmt.Errorf("ADMIN_PASSWORD 不符合密碼規則: %v", err)
+	}
+
+	_, err := factories.User().Admin().WithPassword(plain).State(func(u *models.User) {
+		u.Name = name
+		u.Email = email
+	}).Create(db)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  ✓ 建立管理員 %s\n", email)
+	return nil
+}
@@ -0,0 +1,23 @@
+package seeders
+
+import (
+	"gorm.io/gorm"
+)
+
+// DatabaseSeeder - seed 預設執行的 Seeder，依序呼叫其他 seeders
+type DatabaseSeeder struct{}
+
+func init() {
+	Register(&DatabaseSeeder{})
+}
+
+func (s *DatabaseSeeder) Name() string {
+	return DefaultSeeder
+}
+
+func (s *DatabaseSeeder) Run(db *gorm.DB) error {
+	return Call(db,
+		"UserSeeder",
+		"PostSeeder",
+	)
+}
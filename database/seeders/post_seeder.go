@@ -0,0 +1,44 @@
+package seeders
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	"gorm.io/gorm"
+	"my-api/app/models"
+	"my-api/database/factories"
+)
+
+// PostSeeder - 為既有的使用者隨機建立文章
+type PostSeeder struct{}
+
+func init() {
+	Register(&PostSeeder{})
+}
+
+func (s *PostSeeder) Name() string {
+	return "PostSeeder"
+}
+
+func (s *PostSeeder) Run(db *gorm.DB) error {
+	var users []models.User
+	if err := db.Where("email_verified_at IS NOT NULL").Find(&users).Error; err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return errors.New("沒有可以作為作者的使用者，請先執行 UserSeeder")
+	}
+
+	total := 0
+	for i := range users {
+		posts, err := factories.Post().ForUser(&users[i]).Count(rand.IntN(4)).CreateMany(db)
+		if err != nil {
+			return err
+		}
+		total += len(posts)
+	}
+
+	fmt.Printf("  ✓ 為 %d 位使用者建立 %d 篇文章\n", len(users), total)
+	return nil
+}
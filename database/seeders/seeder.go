@@ -0,0 +1,76 @@
+// Package seeders - 填入本機與測試環境的資料（類似 Laravel 的 Seeder）
+package seeders
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// Seeder 介面定義
+// Name 為 seed --class 使用的名稱，例如 UserSeeder
+type Seeder interface {
+	Name() string
+	Run(db *gorm.DB) error
+}
+
+// DefaultSeeder - seed 沒有指定 --class 時執行的 Seeder
+const DefaultSeeder = "DatabaseSeeder"
+
+var (
+	registry = make(map[string]Seeder)
+	mu       sync.RWMutex
+)
+
+// Register 註冊 seeder，名稱重複時 panic
+func Register(s Seeder) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := registry[s.Name()]; ok {
+		panic(fmt.Sprintf("seeder 名稱重複: %s", s.Name()))
+	}
+	registry[s.Name()] = s
+}
+
+// All 獲取所有已註冊的 seeders（按名稱排序）
+func All() []Seeder {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var seeders []Seeder
+	for _, s := range registry {
+		seeders = append(seeders, s)
+	}
+
+	sort.Slice(seeders, func(i, j int) bool {
+		return seeders[i].Name() < seeders[j].Name()
+	})
+
+	return seeders
+}
+
+// Get 根據名稱獲取 seeder
+func Get(name string) (Seeder, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	s, ok := registry[name]
+	return s, ok
+}
+
+// Call 依序執行指定的 seeders，其中一個失敗時停止
+func Call(db *gorm.DB, names ...string) error {
+	for _, name := range names {
+		s, ok := Get(name)
+		if !ok {
+			return fmt.Errorf("找不到 seeder: %s", name)
+		}
+
+		fmt.Printf("🌱 執行 Seeder: %s\n", name)
+		if err := s.Run(db); err != nil {
+			return fmt.Errorf("%s 失敗: %v", name, err)
+		}
+	}
+	return nil
+}
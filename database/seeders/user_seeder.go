@@ -0,0 +1,35 @@
+package seeders
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"my-api/database/factories"
+)
+
+// UserSeeder - 建立一般使用者（密碼皆為 factories.DefaultPassword）
+// 不建立管理員：公開的預設密碼不能用在管理員帳號，管理員一律以 AdminSeeder（ADMIN_EMAIL、ADMIN_PASSWORD）指定
+type UserSeeder struct{}
+
+func init() {
+	Register(&UserSeeder{})
+}
+
+func (s *UserSeeder) Name() string {
+	return "UserSeeder"
+}
+
+func (s *UserSeeder) Run(db *gorm.DB) error {
+	users, err := factories.User().Count(10).CreateMany(db)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  ✓ 建立 %d 位使用者\n", len(users))
+
+	_, err = factories.User().Unverified().Count(2).CreateMany(db)
+	if err != nil {
+		return err
+	}
+	fmt.Println("  ✓ 建立 2 位尚未驗證 Email 的使用者")
+	return nil
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"my-api/app"
 	"my-api/bootstrap"
@@ -17,6 +19,9 @@ func main() {
 	// 初始化 Logger（必須在其他初始化之前）
 	bootstrap.InitLogger()
 
+	// 檢查 APP_KEY（簽章連結、TOTP 金鑰加密）
+	bootstrap.InitAppKey()
+
 	// 初始化資料庫連接
 	bootstrap.InitDB()
 
@@ -28,11 +33,25 @@ func main() {
 		// 不中斷程式，繼續啟動
 	}
 
-	// 初始化 Redis（可選）
-	// bootstrap.InitRedis()
+	// 初始化 Redis（可選，REDIS_ENABLED=true 時啟用）
+	if config.GlobalConfig.Redis.Enabled {
+		bootstrap.InitRedis()
+	}
+
+	// 初始化 Mailer（MAIL_DRIVER）
+	bootstrap.InitMailer()
+
+	// 載入 JWT 金鑰（JWT_ALGORITHM）
+	bootstrap.InitJWT()
+
+	// 密碼規則（PASSWORD_MIN_LENGTH 等）
+	bootstrap.InitPasswordPolicy()
 
 	// 建立應用程式容器（Laravel 風格）
-	application := app.NewApp(bootstrap.DB, bootstrap.Log)
+	application := app.NewApp(bootstrap.DB, bootstrap.Log, bootstrap.RedisClient, bootstrap.Mailer)
+
+	// 定期刪除寬限期已過的帳號（ACCOUNT_DELETION_CHECK_INTERVAL_MINUTES）
+	go purgeDeletedAccounts(application)
 
 	// 設定 Gin 模式
 	if config.GlobalConfig.App.Env == "production" {
@@ -41,6 +60,13 @@ func main() {
 
 	r := gin.New() // 使用 gin.New() 而非 gin.Default()，避免重複的日誌
 
+	// Gin 預設信任所有代理的 X-Forwarded-For，c.ClientIP() 可以被偽造（APP_TRUSTED_PROXIES）
+	if err := r.SetTrustedProxies(config.GlobalConfig.App.TrustedProxies); err != nil {
+		bootstrap.Log.Fatal("APP_TRUSTED_PROXIES 格式錯誤", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+
 	// 設定所有路由
 	routes.SetupRoutes(r, application)
 
@@ -50,4 +76,30 @@ func main() {
 	})
 
 	r.Run(":" + config.GlobalConfig.App.Port)
-}
\ No newline at end of file
+}
+
+// purgeDeletedAccounts - 啟動時與之後每隔一段時間，匿名化寬限期已過的帳號
+// 多個實例同時執行也沒關係，Erase 的每個步驟都可以重複執行
+func purgeDeletedAccounts(application *app.App) {
+	interval := time.Duration(config.GlobalConfig.Account.DeletionCheckIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		return
+	}
+
+	for {
+		erased, err := application.AccountService.PurgeDue()
+		if err != nil {
+			bootstrap.Log.Error("刪除到期帳號失敗", map[string]interface{}{
+				"channel": "security",
+				"erased":  erased,
+				"error":   err.Error(),
+			})
+		} else if erased > 0 {
+			bootstrap.Log.Info("已刪除到期帳號", map[string]interface{}{
+				"channel": "security",
+				"erased":  erased,
+			})
+		}
+		time.Sleep(interval)
+	}
+}
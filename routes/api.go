@@ -13,6 +13,15 @@ func SetupRoutes(router *gin.Engine, application *app.App) {
 	userCtrl := controllers.NewUserController(application)
 	authCtrl := controllers.NewAuthController(application)
 	postCtrl := controllers.NewPostController(application)
+	roleCtrl := controllers.NewRoleController(application)
+	passwordCtrl := controllers.NewPasswordController(application)
+	verificationCtrl := controllers.NewEmailVerificationController(application)
+	jwksCtrl := controllers.NewJWKSController(application)
+	tokenCtrl := controllers.NewAccessTokenController(application)
+	twoFactorCtrl := controllers.NewTwoFactorController(application)
+	sessionCtrl := controllers.NewSessionController(application)
+	socialAuthCtrl := controllers.NewSocialAuthController(application)
+	accountCtrl := controllers.NewAccountController(application)
 
 	// 全域中間件
 	router.Use(gin.Recovery())        // 錯誤恢復
@@ -28,6 +37,9 @@ func SetupRoutes(router *gin.Engine, application *app.App) {
 		})
 	})
 
+	// JWT 驗證公鑰（其他服務驗證本服務簽發的 Token 用）
+	router.GET("/.well-known/jwks.json", jwksCtrl.Show)
+
 	// API 路由群組
 	api := router.Group("/api")
 	{
@@ -36,39 +48,115 @@ func SetupRoutes(router *gin.Engine, application *app.App) {
 		{
 			public.POST("/register", authCtrl.Register) // 註冊
 			public.POST("/login", authCtrl.Login)       // 登入
+			public.POST("/refresh", authCtrl.Refresh)   // 刷新 Token
+
+			public.POST("/two-factor/challenge", authCtrl.TwoFactorChallenge) // 兩步驟驗證（以 challenge token 換取 Token）
+
+			// OpenID Connect 登入（授權碼 + PKCE）
+			public.GET("/auth/oidc", socialAuthCtrl.Providers)                   // 可使用的 IdP
+			public.GET("/auth/oidc/:provider/redirect", socialAuthCtrl.Redirect) // 導向 IdP 登入
+			public.GET("/auth/oidc/:provider/callback", socialAuthCtrl.Callback) // IdP 導回，回傳 Token
+
+			public.POST("/password/forgot", passwordCtrl.Forgot) // 忘記密碼（寄送重設連結）
+			public.POST("/password/reset", passwordCtrl.Reset)   // 重設密碼
+
+			public.GET("/email/verify/:id/:hash", middleware.ValidateSignature(), verificationCtrl.Verify)                     // Email 驗證（簽章連結）
+			public.GET("/me/deletion/confirm/:id/:hash", middleware.ValidateSignature(), accountCtrl.ShowDeletionConfirmation) // 確認刪除帳號頁面（簽章連結，不會申請）
+			public.POST("/me/deletion/confirm/:id/:hash", middleware.ValidateSignature(), accountCtrl.ConfirmDeletion)         // 確認刪除帳號（同一個簽章連結）
 		}
 
 		// 需要驗證的路由
 		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware()) // 使用 JWT 驗證中間件
+		protected.Use(middleware.AuthMiddleware(application)) // 使用 JWT 驗證中間件
 		{
 			// 認證相關
-			protected.POST("/logout", authCtrl.Logout) // 登出
+			protected.POST("/logout", authCtrl.Logout)         // 登出
+			protected.POST("/logout/all", middleware.RequireJWT(), authCtrl.LogoutAll) // 登出所有裝置
 			protected.GET("/me", authCtrl.Me)          // 取得當前用戶
+			protected.POST("/email/resend", middleware.RequireJWT(), verificationCtrl.Resend) // 重新寄送驗證信
+			protected.PUT("/me/password", middleware.RequireJWT(), passwordCtrl.Update) // 變更密碼（登出其他裝置）
+
+			// 個人資料匯出與刪除帳號，只能用 JWT 登入後操作
+			me := protected.Group("/me")
+			me.Use(middleware.RequireJWT())
+			{
+				me.GET("/export", accountCtrl.Export)              // GET    /api/me/export
+				me.DELETE("", accountCtrl.Destroy)                 // DELETE /api/me
+				me.DELETE("/deletion", accountCtrl.CancelDeletion) // DELETE /api/me/deletion
+			}
+
+			// 個人存取 Token（API Key），只能用 JWT 登入後管理
+			tokens := protected.Group("/tokens")
+			tokens.Use(middleware.RequireJWT())
+			{
+				tokens.GET("", tokenCtrl.Index)          // GET    /api/tokens
+				tokens.POST("", tokenCtrl.Store)         // POST   /api/tokens
+				tokens.DELETE("/:id", tokenCtrl.Destroy) // DELETE /api/tokens/:id
+			}
 
-			// RESTful User 路由
+			// 兩步驟驗證設定，只能用 JWT 登入後管理
+			twoFactor := protected.Group("/two-factor")
+			twoFactor.Use(middleware.RequireJWT())
+			{
+				twoFactor.POST("", twoFactorCtrl.Enable)                      // POST   /api/two-factor
+				twoFactor.POST("/confirm", twoFactorCtrl.Confirm)             // POST   /api/two-factor/confirm
+				twoFactor.DELETE("", twoFactorCtrl.Disable)                   // DELETE /api/two-factor
+				twoFactor.POST("/recovery-codes", twoFactorCtrl.RecoveryCodes) // POST   /api/two-factor/recovery-codes
+			}
+
+			// 登入裝置與登入紀錄，只能用 JWT 登入後管理
+			sessions := protected.Group("/sessions")
+			sessions.Use(middleware.RequireJWT())
+			{
+				sessions.GET("", sessionCtrl.Index)          // GET    /api/sessions
+				sessions.GET("/history", sessionCtrl.History) // GET    /api/sessions/history
+				sessions.DELETE("/:id", sessionCtrl.Destroy) // DELETE /api/sessions/:id
+			}
+
+			// RESTful User 路由（依權限控管）
 			users := protected.Group("/users")
 			{
-				users.GET("", userCtrl.Index)          // GET    /api/users
-				users.POST("", userCtrl.Store)         // POST   /api/users
-				users.GET("/:id", userCtrl.Show)       // GET    /api/users/:id
-				users.PUT("/:id", userCtrl.Update)     // PUT    /api/users/:id
-				users.PATCH("/:id", userCtrl.Update)   // PATCH  /api/users/:id
-				users.DELETE("/:id", userCtrl.Destroy) // DELETE /api/users/:id
+				users.GET("", middleware.RequirePermission("users.view"), userCtrl.Index)          // GET    /api/users
+				users.POST("", middleware.RequirePermission("users.create"), userCtrl.Store)       // POST   /api/users
+				users.GET("/:id", middleware.RequirePermission("users.view"), userCtrl.Show)       // GET    /api/users/:id
+				users.PUT("/:id", middleware.RequirePermission("users.update"), userCtrl.Update)   // PUT    /api/users/:id
+				users.PATCH("/:id", middleware.RequirePermission("users.update"), userCtrl.Update) // PATCH  /api/users/:id
+				users.DELETE("/:id", middleware.RequirePermission("users.delete"), userCtrl.Destroy) // DELETE /api/users/:id
+
+				users.PUT("/:id/roles", middleware.RequirePermission("roles.manage"), roleCtrl.SyncUserRoles) // PUT /api/users/:id/roles
+				users.POST("/:id/unlock", middleware.RequirePermission("users.update"), userCtrl.Unlock)     // POST /api/users/:id/unlock
 			}
 
+			// 角色權限管理路由（需 roles.manage 權限）
+			roles := protected.Group("/roles")
+			roles.Use(middleware.RequirePermission("roles.manage"))
+			{
+				roles.GET("", roleCtrl.Index)                          // GET    /api/roles
+				roles.POST("", roleCtrl.Store)                         // POST   /api/roles
+				roles.GET("/:id", roleCtrl.Show)                       // GET    /api/roles/:id
+				roles.PUT("/:id", roleCtrl.Update)                     // PUT    /api/roles/:id
+				roles.DELETE("/:id", roleCtrl.Destroy)                 // DELETE /api/roles/:id
+				roles.PUT("/:id/permissions", roleCtrl.SyncPermissions) // PUT    /api/roles/:id/permissions
+			}
+			protected.GET("/permissions", middleware.RequirePermission("roles.manage"), roleCtrl.Permissions) // GET /api/permissions
+
 			// RESTful Post 路由
+			// API Token 需具備 posts.read / posts.write Scope
 			posts := protected.Group("/posts")
 			{
-				posts.GET("", postCtrl.Index)      // GET    /api/posts
-				posts.POST("", postCtrl.Store)     // POST   /api/posts
-				posts.GET("/:id", postCtrl.Show)   // GET    /api/posts/:id
-				posts.PUT("/:id", postCtrl.Update) // PUT    /api/posts/:id
-				posts.DELETE("/:id", postCtrl.Delete) // DELETE /api/posts/:id
+				posts.GET("", middleware.RequireScope("posts.read"), postCtrl.Index)           // GET    /api/posts
+				posts.POST("", middleware.RequireScope("posts.write"), postCtrl.Store)         // POST   /api/posts
+				posts.GET("/:id", middleware.RequireScope("posts.read"), postCtrl.Show)        // GET    /api/posts/:id
+				posts.PUT("/:id", middleware.RequireScope("posts.write"), postCtrl.Update)     // PUT    /api/posts/:id
+				posts.DELETE("/:id", middleware.RequireScope("posts.write"), postCtrl.Delete) // DELETE /api/posts/:id
 			}
 
 			// 其他需要驗證的路由
 			// protected.GET("/profile", userCtrl.Profile)
+
+			// 需要 Email 已驗證的路由群組可以加上 RequireVerified
+			// verified := protected.Group("")
+			// verified.Use(middleware.RequireVerified(application))
 		}
 	}
 }